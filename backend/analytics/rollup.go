@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+// MinRetention is the shortest time raw page views are kept. Rollup recounts
+// the latest day it has rolled up, which needs the views of that day.
+const MinRetention = 48 * time.Hour
+
+// rollupMu keeps the hourly job and the report from rolling up at the same
+// time, which would insert the same rows twice.
+var rollupMu sync.Mutex
+
+// count is the number of views and unique visitors of a path in a period.
+type count struct {
+	Path     string
+	Views    int64
+	Visitors int64
+}
+
+// Rollup recounts the hourly and daily statistics from the raw page views up
+// to now. It starts again at the latest hour and day it has rolled up before,
+// as views of those may have been written since, so it can run any number of
+// times.
+func Rollup(db *gorm.DB, now time.Time) error {
+	rollupMu.Lock()
+	defer rollupMu.Unlock()
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := rollup(tx, now, hourStart, func(t time.Time) time.Time { return t.Add(time.Hour) },
+			func(period time.Time, c count) models.HourlyPageStat {
+				return models.HourlyPageStat{Period: period, Path: c.Path, Views: c.Views, Visitors: c.Visitors}
+			})
+		if err != nil {
+			return err
+		}
+		return rollup(tx, now, DayStart, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) },
+			func(period time.Time, c count) models.DailyPageStat {
+				return models.DailyPageStat{Period: period, Path: c.Path, Views: c.Views, Visitors: c.Visitors}
+			})
+	})
+}
+
+// Prune deletes the raw page views recorded before before and returns how
+// many there were. The rolled up statistics are kept; see PruneHourly.
+func Prune(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("created_at < ?", before).Delete(&models.PageView{})
+	return result.RowsAffected, result.Error
+}
+
+// PruneHourly deletes the hourly statistics of periods before before and
+// returns how many rows there were. The latest period is always kept, as
+// Rollup starts from it; without it, it would roll up the raw views again.
+func PruneHourly(db *gorm.DB, before time.Time) (int64, error) {
+	var latest []time.Time
+	if err := db.Model(&models.HourlyPageStat{}).Order("period DESC").Limit(1).Pluck("period", &latest).Error; err != nil {
+		return 0, err
+	}
+	if len(latest) == 0 {
+		return 0, nil
+	}
+	if latest[0].Before(before) {
+		before = latest[0]
+	}
+	result := db.Where("period < ?", before).Delete(&models.HourlyPageStat{})
+	return result.RowsAffected, result.Error
+}
+
+func hourStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+// DayStart returns midnight of t's day, the period of its daily statistics.
+func DayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// rollup replaces the rows of T from the latest period in its table, or from
+// the first page view, up to the period of now. Each period gets a row per
+// path and one with an empty path for the whole site; periods without views
+// get none.
+func rollup[T any](tx *gorm.DB, now time.Time, start, next func(time.Time) time.Time, row func(time.Time, count) T) error {
+	var first []time.Time
+	if err := tx.Model(new(T)).Order("period DESC").Limit(1).Pluck("period", &first).Error; err != nil {
+		return err
+	}
+	if len(first) == 0 {
+		if err := tx.Model(&models.PageView{}).Order("created_at").Limit(1).Pluck("created_at", &first).Error; err != nil {
+			return err
+		}
+		if len(first) == 0 {
+			return nil
+		}
+	}
+
+	from := start(first[0].In(now.Location()))
+	if err := tx.Where("period >= ?", from).Delete(new(T)).Error; err != nil {
+		return err
+	}
+
+	var rows []T
+	for period := from; !period.After(now); {
+		end := next(period)
+		views := func() *gorm.DB {
+			return tx.Model(&models.PageView{}).Where("created_at >= ? AND created_at < ?", period, end)
+		}
+
+		var counts []count
+		err := views().Select("path, COUNT(*) AS views, COUNT(DISTINCT visitor_hash) AS visitors").Group("path").Scan(&counts).Error
+		if err != nil {
+			return err
+		}
+		if len(counts) == 0 {
+			// Skip ahead to the next view instead of walking through
+			// empty periods
+			var later []time.Time
+			err := tx.Model(&models.PageView{}).Where("created_at >= ?", end).Order("created_at").Limit(1).Pluck("created_at", &later).Error
+			if err != nil {
+				return err
+			}
+			if len(later) == 0 {
+				break
+			}
+			period = start(later[0].In(now.Location()))
+			continue
+		}
+
+		var total count
+		if err := views().Select("COUNT(*) AS views, COUNT(DISTINCT visitor_hash) AS visitors").Scan(&total).Error; err != nil {
+			return err
+		}
+		rows = append(rows, row(period, total))
+		for _, c := range counts {
+			rows = append(rows, row(period, c))
+		}
+		period = end
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(rows, batchSize).Error
+}
@@ -0,0 +1,82 @@
+package analytics
+
+import "strings"
+
+// Client is the coarse description of a browser kept for the statistics.
+type Client struct {
+	Device  string // desktop, mobile or tablet
+	Browser string
+	OS      string
+	Bot     bool // Crawlers, link previews and scripts are not counted
+}
+
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "preview", "fetch", "monitor", "headless",
+	"lighthouse", "curl", "wget", "python", "go-http-client", "java/", "okhttp",
+}
+
+// The first match wins, so browsers that also claim to be Chrome or Safari
+// come first.
+var browsers = []struct{ marker, name string }{
+	{"edg", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser", "Samsung Internet"},
+	{"firefox", "Firefox"},
+	{"fxios", "Firefox"},
+	{"crios", "Chrome"},
+	{"chrome", "Chrome"},
+	{"chromium", "Chrome"},
+	{"safari", "Safari"},
+}
+
+var systems = []struct{ marker, name string }{
+	{"windows", "Windows"},
+	{"iphone", "iOS"},
+	{"ipad", "iOS"},
+	{"ipod", "iOS"},
+	{"android", "Android"},
+	{"cros", "ChromeOS"},
+	{"mac os", "macOS"},
+	{"macintosh", "macOS"},
+	{"linux", "Linux"},
+}
+
+// ParseUserAgent reduces a User-Agent header to device type, browser and
+// operating system. Versions are dropped on purpose.
+func ParseUserAgent(userAgent string) Client {
+	ua := strings.ToLower(userAgent)
+	client := Client{Device: "desktop", Browser: "Other", OS: "Other"}
+	if ua == "" {
+		client.Bot = true
+		return client
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			client.Bot = true
+			return client
+		}
+	}
+
+	for _, b := range browsers {
+		if strings.Contains(ua, b.marker) {
+			client.Browser = b.name
+			break
+		}
+	}
+	for _, s := range systems {
+		if strings.Contains(ua, s.marker) {
+			client.OS = s.name
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		client.Device = "tablet"
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		client.Device = "mobile"
+	}
+	return client
+}
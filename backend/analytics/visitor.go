@@ -0,0 +1,39 @@
+package analytics
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Hasher derives the visitor hash of a page view from the address and
+// User-Agent of the request. The salt is random, lives only in memory and is
+// replaced at midnight, so a hash cannot be turned back into an address or
+// linked to the same visitor on another day. A restart starts a new salt as
+// well.
+type Hasher struct {
+	mu   sync.Mutex
+	day  string
+	salt [32]byte
+}
+
+// Hash returns the hex SHA-256 of the salt of now's day, ip and userAgent.
+func (h *Hasher) Hash(now time.Time, ip, userAgent string) string {
+	h.mu.Lock()
+	if day := now.Format(time.DateOnly); day != h.day {
+		if _, err := rand.Read(h.salt[:]); err != nil {
+			panic(err) // crypto/rand does not fail on supported platforms
+		}
+		h.day = day
+	}
+	sum := sha256.New()
+	sum.Write(h.salt[:])
+	h.mu.Unlock()
+
+	sum.Write([]byte(ip))
+	sum.Write([]byte{0})
+	sum.Write([]byte(userAgent))
+	return hex.EncodeToString(sum.Sum(nil))
+}
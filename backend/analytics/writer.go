@@ -0,0 +1,81 @@
+// Package analytics records page views without cookies or IP addresses and
+// rolls them up into hourly and daily statistics.
+package analytics
+
+import (
+	"log"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+// batchSize is the number of rows per INSERT when the buffer is flushed.
+const batchSize = 500
+
+// Writer buffers page views in memory and stores them in batches, so the
+// beacon never waits for the database. Views that arrive while the buffer is
+// full are dropped, as are the buffered views when the process dies.
+type Writer struct {
+	db     *gorm.DB
+	views  chan models.PageView
+	filled chan struct{} // Signals Run that the buffer is half full
+}
+
+// NewWriter returns a Writer that buffers up to size page views.
+func NewWriter(db *gorm.DB, size int) *Writer {
+	return &Writer{
+		db:     db,
+		views:  make(chan models.PageView, size),
+		filled: make(chan struct{}, 1),
+	}
+}
+
+// Add queues a page view and reports whether there was room for it.
+func (w *Writer) Add(view models.PageView) bool {
+	select {
+	case w.views <- view:
+	default:
+		return false
+	}
+	if len(w.views) >= cap(w.views)/2 {
+		select {
+		case w.filled <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// Flush stores the queued page views and returns how many there were.
+func (w *Writer) Flush() (int, error) {
+	var batch []models.PageView
+drain:
+	for len(batch) < cap(w.views) {
+		select {
+		case view := <-w.views:
+			batch = append(batch, view)
+		default:
+			break drain
+		}
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	return len(batch), w.db.CreateInBatches(batch, batchSize).Error
+}
+
+// Run flushes the buffer every interval, and as soon as it is half full.
+func (w *Writer) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.filled:
+		}
+		if n, err := w.Flush(); err != nil {
+			log.Printf("Failed to store %d page views: %v", n, err)
+		}
+	}
+}
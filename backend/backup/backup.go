@@ -0,0 +1,617 @@
+// Package backup writes the whole blog, every table and the uploaded files,
+// into one versioned zip archive and restores such an archive, possibly into
+// a database of another kind.
+//
+// An archive contains data/<table>.json with the rows of each table as a JSON
+// array keyed by column name, uploads/<file> with the uploaded files, and
+// manifest.json with the format version, row counts and a SHA-256 checksum
+// of every other file.
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Format identifies backup archives; Version is bumped whenever the layout
+// changes in a way older releases cannot restore.
+const (
+	Format  = "blog-backup"
+	Version = 1
+)
+
+const (
+	manifestName = "manifest.json"
+	batchSize    = 500
+)
+
+// namePattern matches the file names CreateFile gives to backups.
+var namePattern = regexp.MustCompile(`^blog-backup-\d{8}-\d{6}\.zip$`)
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	Format    string         `json:"format"`
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Driver    string         `json:"driver"` // Database the backup was taken from
+	Tables    map[string]int `json:"tables"` // Rows per table
+	Files     []File         `json:"files"`
+}
+
+// File is one checksummed file of an archive.
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Info describes a backup file on disk.
+type Info struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// table is a table to back up. Join tables have no model and are copied by
+// column.
+type table struct {
+	name    string
+	model   interface{}
+	columns []string // Only for join tables
+}
+
+// tables lists every table in an order that satisfies foreign keys when
+// restoring. Upload sessions are left out, they only track uploads in
+// progress.
+var tables = []table{
+	{name: "users", model: &models.User{}},
+	{name: "categories", model: &models.Category{}},
+	{name: "tags", model: &models.Tag{}},
+	{name: "tag_aliases", model: &models.TagAlias{}},
+	{name: "series", model: &models.Series{}},
+	{name: "media", model: &models.Media{}},
+	{name: "articles", model: &models.Article{}},
+	{name: "article_tags", columns: []string{"article_id", "tag_id"}},
+	{name: "comments", model: &models.Comment{}},
+	{name: "article_events", model: &models.ArticleEvent{}},
+	{name: "page_views", model: &models.PageView{}},
+	{name: "hourly_page_stats", model: &models.HourlyPageStat{}},
+	{name: "daily_page_stats", model: &models.DailyPageStat{}},
+}
+
+// ValidName reports whether name is the name of a backup made by CreateFile.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// checksumWriter counts and hashes everything written to an archive entry.
+type checksumWriter struct {
+	w    io.Writer
+	hash hash.Hash
+	size int64
+}
+
+func (cw *checksumWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.hash.Write(p[:n])
+	cw.size += int64(n)
+	return n, err
+}
+
+// Create writes a backup of db and the files in uploadDir to w.
+func Create(db *gorm.DB, uploadDir string, w io.Writer) (*Manifest, error) {
+	manifest := &Manifest{
+		Format:    Format,
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Driver:    db.Dialector.Name(),
+		Tables:    map[string]int{},
+		Files:     []File{},
+	}
+
+	archive := zip.NewWriter(w)
+	entry := func(name string, write func(io.Writer) error) error {
+		zw, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		cw := &checksumWriter{w: zw, hash: sha256.New()}
+		if err := write(cw); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, File{Path: name, Size: cw.size, SHA256: hex.EncodeToString(cw.hash.Sum(nil))})
+		return nil
+	}
+
+	// One consistent snapshot of all tables
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range tables {
+			err := entry("data/"+t.name+".json", func(w io.Writer) error {
+				rows, err := dumpTable(tx, t, w)
+				manifest.Tables[t.name] = rows
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", t.name, err)
+			}
+		}
+		return resetSequences(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, err := uploadFiles(uploadDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range uploads {
+		err := entry("uploads/"+name, func(w io.Writer) error {
+			f, err := os.Open(filepath.Join(uploadDir, name))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	mw, err := archive.Create(manifestName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := mw.Write(data); err != nil {
+		return nil, err
+	}
+	return manifest, archive.Close()
+}
+
+// uploadFiles lists the regular files directly in uploadDir. Directories hold
+// the chunks of unfinished uploads and are skipped.
+func uploadFiles(uploadDir string) ([]string, error) {
+	entries, err := os.ReadDir(uploadDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+func parseSchema(db *gorm.DB, model interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// columns returns the fields of s that are stored in its table.
+func columns(s *schema.Schema) []*schema.Field {
+	var fields []*schema.Field
+	for _, f := range s.Fields {
+		if f.DBName != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// dumpTable writes the rows of t, soft deleted ones included, as a JSON array
+// and returns the number of rows.
+func dumpTable(tx *gorm.DB, t table, w io.Writer) (int, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+	count := 0
+	writeRow := func(row map[string]interface{}) error {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		sep := "\n"
+		if count > 0 {
+			sep = ",\n"
+		}
+		count++
+		_, err = io.WriteString(w, sep+string(data))
+		return err
+	}
+
+	if t.model == nil {
+		var rows []map[string]interface{}
+		err := tx.Table(t.name).Select(t.columns).Order(strings.Join(t.columns, ", ")).Find(&rows).Error
+		if err != nil {
+			return 0, err
+		}
+		for _, row := range rows {
+			if err := writeRow(row); err != nil {
+				return count, err
+			}
+		}
+	} else {
+		s, err := parseSchema(tx, t.model)
+		if err != nil {
+			return 0, err
+		}
+		fields := columns(s)
+
+		rows := reflect.New(reflect.SliceOf(s.ModelType))
+		result := tx.Unscoped().Model(t.model).FindInBatches(rows.Interface(), batchSize, func(batch *gorm.DB, _ int) error {
+			for i := 0; i < rows.Elem().Len(); i++ {
+				rv := rows.Elem().Index(i)
+				row := make(map[string]interface{}, len(fields))
+				for _, f := range fields {
+					row[f.DBName] = f.ReflectValueOf(context.Background(), rv).Interface()
+				}
+				if err := writeRow(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if result.Error != nil {
+			return count, result.Error
+		}
+	}
+
+	_, err := io.WriteString(w, "\n]\n")
+	return count, err
+}
+
+// CreateFile writes a new backup into dir and returns its path. The archive
+// only appears under its final name once it is complete.
+func CreateFile(db *gorm.DB, uploadDir, dir string) (string, *Manifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, err
+	}
+
+	name := fmt.Sprintf("blog-backup-%s.zip", time.Now().UTC().Format("20060102-150405"))
+	dst := filepath.Join(dir, name)
+	tmp, err := os.CreateTemp(dir, ".backup-*.tmp")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	manifest, err := Create(db, uploadDir, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return dst, manifest, os.Rename(tmp.Name(), dst)
+}
+
+// List returns the backups in dir, newest first.
+func List(dir string) ([]Info, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []Info{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []Info{}
+	for _, e := range entries {
+		if !ValidName(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		created, _ := time.Parse("20060102-150405", strings.TrimSuffix(strings.TrimPrefix(e.Name(), "blog-backup-"), ".zip"))
+		backups = append(backups, Info{Name: e.Name(), Size: info.Size(), CreatedAt: created})
+	}
+	// Names sort chronologically
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Name > backups[j].Name })
+	return backups, nil
+}
+
+// Rotate deletes all but the newest keep backups in dir and returns the
+// names of the deleted files. A keep of 0 or less keeps everything.
+func Rotate(dir string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+	backups, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for i := keep; i < len(backups); i++ {
+		if err := os.Remove(filepath.Join(dir, backups[i].Name)); err != nil {
+			return removed, err
+		}
+		removed = append(removed, backups[i].Name)
+	}
+	return removed, nil
+}
+
+// ErrNotEmpty is returned by Restore when the database already has data and
+// replace is not set.
+var ErrNotEmpty = errors.New("the database is not empty")
+
+// Restore loads an archive written by Create into db and uploadDir. Every
+// checksum is verified before anything is written. The database has to be
+// empty unless replace is set, in which case all existing rows are deleted
+// first. Tables are restored in a single transaction.
+func Restore(db *gorm.DB, uploadDir string, r io.ReaderAt, size int64, replace bool) (*Manifest, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+
+	manifest, err := readManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(archive, manifest); err != nil {
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if replace {
+			if err := clearTables(tx); err != nil {
+				return err
+			}
+		} else if err := ensureEmpty(tx); err != nil {
+			return err
+		}
+
+		for _, t := range tables {
+			f, err := archive.Open("data/" + t.name + ".json")
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // Table added after the backup was made
+			}
+			if err != nil {
+				return err
+			}
+			err = loadTable(tx, t, f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", t.name, err)
+			}
+		}
+		return resetSequences(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, restoreUploads(archive, manifest, uploadDir)
+}
+
+func readManifest(archive *zip.Reader) (*Manifest, error) {
+	f, err := archive.Open(manifestName)
+	if err != nil {
+		return nil, fmt.Errorf("not a blog backup: %w", err)
+	}
+	defer f.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(f).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if manifest.Format != Format {
+		return nil, errors.New("not a blog backup")
+	}
+	if manifest.Version < 1 || manifest.Version > Version {
+		return nil, fmt.Errorf("backup format version %d is not supported (this release reads up to %d)", manifest.Version, Version)
+	}
+	return &manifest, nil
+}
+
+// verify checks the size and checksum of every file in the manifest.
+func verify(archive *zip.Reader, manifest *Manifest) error {
+	for _, file := range manifest.Files {
+		f, err := archive.Open(file.Path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+		h := sha256.New()
+		n, err := io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+		if n != file.Size || hex.EncodeToString(h.Sum(nil)) != file.SHA256 {
+			return fmt.Errorf("%s: checksum mismatch, the backup is damaged", file.Path)
+		}
+	}
+	return nil
+}
+
+func ensureEmpty(tx *gorm.DB) error {
+	for _, t := range tables {
+		var count int64
+		if err := tx.Table(t.name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: table %s has %d rows", ErrNotEmpty, t.name, count)
+		}
+	}
+	return nil
+}
+
+// clearTables deletes all rows, children before parents.
+func clearTables(tx *gorm.DB) error {
+	for i := len(tables) - 1; i >= 0; i-- {
+		if err := tx.Exec("DELETE FROM " + tx.Statement.Quote(tables[i].name)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTable inserts the rows of a data file. Columns the archive does not
+// have keep their zero value; columns the models no longer have are ignored.
+func loadTable(tx *gorm.DB, t table, r io.Reader) error {
+	dec := json.NewDecoder(r)
+	if _, err := dec.Token(); err != nil { // [
+		return err
+	}
+
+	if t.model == nil {
+		var batch []map[string]interface{}
+		for dec.More() {
+			var raw map[string]interface{}
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			row := make(map[string]interface{}, len(t.columns))
+			for _, col := range t.columns {
+				row[col] = raw[col]
+			}
+			batch = append(batch, row)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		return tx.Table(t.name).CreateInBatches(batch, batchSize).Error
+	}
+
+	s, err := parseSchema(tx, t.model)
+	if err != nil {
+		return err
+	}
+	fields := columns(s)
+
+	batch := reflect.MakeSlice(reflect.SliceOf(s.ModelType), 0, batchSize)
+	flush := func() error {
+		if batch.Len() == 0 {
+			return nil
+		}
+		rows := reflect.New(batch.Type())
+		rows.Elem().Set(batch)
+		err := tx.Session(&gorm.Session{SkipHooks: true}).Omit(clause.Associations).Create(rows.Interface()).Error
+		batch = batch.Slice(0, 0)
+		return err
+	}
+
+	for dec.More() {
+		var raw map[string]json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		rv := reflect.New(s.ModelType).Elem()
+		for _, f := range fields {
+			data, ok := raw[f.DBName]
+			if !ok {
+				continue
+			}
+			value := reflect.New(f.FieldType)
+			if err := json.Unmarshal(data, value.Interface()); err != nil {
+				return fmt.Errorf("column %s: %w", f.DBName, err)
+			}
+			f.ReflectValueOf(context.Background(), rv).Set(value.Elem())
+		}
+		batch = reflect.Append(batch, rv)
+		if batch.Len() >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// resetSequences moves PostgreSQL's id sequences past the restored ids, which
+// were inserted explicitly. MySQL and SQLite continue after the highest id on
+// their own.
+func resetSequences(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	for _, t := range tables {
+		if t.model == nil {
+			continue
+		}
+		s, err := parseSchema(tx, t.model)
+		if err != nil {
+			return err
+		}
+		id := s.PrioritizedPrimaryField
+		if id == nil || !id.AutoIncrement {
+			continue
+		}
+		err = tx.Exec("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE((SELECT MAX(?) FROM ?), 0) + 1, false)",
+			s.Table, id.DBName, clause.Column{Name: id.DBName}, clause.Table{Name: s.Table}).Error
+		if err != nil {
+			return fmt.Errorf("%s: resetting the id sequence: %w", s.Table, err)
+		}
+	}
+	return nil
+}
+
+// restoreUploads writes the uploaded files of the archive into uploadDir.
+func restoreUploads(archive *zip.Reader, manifest *Manifest, uploadDir string) error {
+	if err := os.MkdirAll(uploadDir, 0o755); err != nil {
+		return err
+	}
+	for _, file := range manifest.Files {
+		name := strings.TrimPrefix(file.Path, "uploads/")
+		if name == file.Path || name != path.Base(name) || name == "." || name == ".." {
+			continue
+		}
+
+		src, err := archive.Open(file.Path)
+		if err != nil {
+			return err
+		}
+		dst, err := os.Create(filepath.Join(uploadDir, name))
+		if err == nil {
+			_, err = io.Copy(dst, src)
+			if closeErr := dst.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/your-username/blog-backend/migrations"
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "blog.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seed fills db with a little of everything, a trashed article included, and
+// writes one upload into uploadDir.
+func seed(t *testing.T, db *gorm.DB, uploadDir string) {
+	t.Helper()
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := models.User{Username: "admin", Email: "admin@example.com", Password: "hash"}
+	category := models.Category{Name: "Go"}
+	category.Slug = "go"
+	tag := models.Tag{Name: "web"}
+	tag.Slug = "web"
+	records := []interface{}{
+		&user, &category, &tag,
+		&models.TagAlias{Name: "www", TagID: 1},
+		&models.Series{Title: "Basics", Slug: "basics", AuthorID: 1},
+		&models.Media{UserID: 1, Filename: "1.png", URL: "/uploads/1.png", Size: 3},
+		&models.Article{Title: "One", Slug: "one", Content: "x", AuthorID: 1, CategoryID: &category.ID, Tags: []models.Tag{{ID: 1}}, Views: 7, CreatedAt: created},
+		&models.Article{Title: "Two", Slug: "two", Content: "y", AuthorID: 1, Draft: true},
+		&models.Comment{Content: "hi", ArticleID: 1, UserID: 1},
+		&models.ArticleEvent{ArticleID: 1, Type: "view", CreatedAt: created},
+	}
+	for _, r := range records {
+		if err := db.Create(r).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Delete(&models.Article{}, 2).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(uploadDir, "chunks"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(uploadDir, "1.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createArchive(t *testing.T, db *gorm.DB, uploadDir string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := Create(db, uploadDir, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// rewriteArchive copies an archive, passing every file through edit, which
+// may drop it by returning nil.
+func rewriteArchive(t *testing.T, data []byte, edit func(name string, body []byte) []byte) []byte {
+	t.Helper()
+	src, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	dst := zip.NewWriter(&buf)
+	for _, f := range src.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body = edit(f.Name, body); body == nil {
+			continue
+		}
+		w, err := dst.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(body)
+	}
+	if err := dst.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// editManifest returns an edit function for rewriteArchive that changes the
+// manifest.
+func editManifest(t *testing.T, change func(m *Manifest)) func(string, []byte) []byte {
+	return func(name string, body []byte) []byte {
+		if name != manifestName {
+			return body
+		}
+		var m Manifest
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatal(err)
+		}
+		change(&m)
+		body, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+}
+
+func TestCreateRestore(t *testing.T) {
+	source := openSQLite(t)
+	sourceUploads := t.TempDir()
+	seed(t, source, sourceUploads)
+	data := createArchive(t, source, sourceUploads)
+
+	target := openSQLite(t)
+	targetUploads := filepath.Join(t.TempDir(), "uploads")
+	manifest, err := Restore(target, targetUploads, bytes.NewReader(data), int64(len(data)), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if manifest.Format != Format || manifest.Version != Version || manifest.Driver != "sqlite" {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	for _, tbl := range tables {
+		var count int64
+		if err := target.Table(tbl.name).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		if int(count) != manifest.Tables[tbl.name] {
+			t.Errorf("%s: restored %d rows, the manifest lists %d", tbl.name, count, manifest.Tables[tbl.name])
+		}
+	}
+	if manifest.Tables["articles"] != 2 || manifest.Tables["article_tags"] != 1 {
+		t.Errorf("table counts = %v, want the trashed article and the tag link", manifest.Tables)
+	}
+
+	var one models.Article
+	if err := target.Preload("Tags").Preload("Category").First(&one, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if one.Title != "One" || one.Views != 7 || one.Category.Name != "Go" || len(one.Tags) != 1 ||
+		!one.CreatedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("article = %+v", one)
+	}
+	var two models.Article
+	if err := target.Unscoped().First(&two, 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !two.DeletedAt.Valid || !two.Draft {
+		t.Errorf("trashed draft came back as deleted=%v draft=%v", two.DeletedAt.Valid, two.Draft)
+	}
+
+	upload, err := os.ReadFile(filepath.Join(targetUploads, "1.png"))
+	if err != nil || string(upload) != "png" {
+		t.Errorf("upload = %q, %v", upload, err)
+	}
+	if _, err := os.Stat(filepath.Join(targetUploads, "chunks")); !os.IsNotExist(err) {
+		t.Errorf("chunk directories should not be backed up: %v", err)
+	}
+
+	// New rows continue after the restored ids
+	three := models.Article{Title: "Three", Slug: "three", Content: "z", AuthorID: 1}
+	if err := target.Create(&three).Error; err != nil || three.ID != 3 {
+		t.Errorf("new article got id %d, %v; want 3", three.ID, err)
+	}
+}
+
+func TestRestoreReplace(t *testing.T) {
+	db := openSQLite(t)
+	uploads := t.TempDir()
+	seed(t, db, uploads)
+	data := createArchive(t, db, uploads)
+
+	if _, err := Restore(db, uploads, bytes.NewReader(data), int64(len(data)), false); !errors.Is(err, ErrNotEmpty) {
+		t.Fatalf("restoring into a used database: got %v, want ErrNotEmpty", err)
+	}
+
+	if err := db.Create(&models.Article{Title: "Later", Slug: "later", Content: "x", AuthorID: 1}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(db, uploads, bytes.NewReader(data), int64(len(data)), true); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Unscoped().Model(&models.Article{}).Count(&count)
+	if count != 2 {
+		t.Errorf("%d articles after replacing, want the 2 of the backup", count)
+	}
+}
+
+func TestRestoreRejectsBadArchives(t *testing.T) {
+	source := openSQLite(t)
+	uploads := t.TempDir()
+	seed(t, source, uploads)
+	good := createArchive(t, source, uploads)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "not a zip",
+			data: []byte("hello"),
+			want: "invalid backup archive",
+		},
+		{
+			name: "no manifest",
+			data: rewriteArchive(t, good, func(name string, body []byte) []byte {
+				if name == manifestName {
+					return nil
+				}
+				return body
+			}),
+			want: "not a blog backup",
+		},
+		{
+			name: "other format",
+			data: rewriteArchive(t, good, editManifest(t, func(m *Manifest) { m.Format = "other" })),
+			want: "not a blog backup",
+		},
+		{
+			name: "newer version",
+			data: rewriteArchive(t, good, editManifest(t, func(m *Manifest) { m.Version = Version + 1 })),
+			want: "is not supported",
+		},
+		{
+			name: "damaged data",
+			data: rewriteArchive(t, good, func(name string, body []byte) []byte {
+				if name == "data/articles.json" {
+					return bytes.Replace(body, []byte(`"One"`), []byte(`"Uno"`), 1)
+				}
+				return body
+			}),
+			want: "checksum mismatch",
+		},
+		{
+			name: "missing upload",
+			data: rewriteArchive(t, good, func(name string, body []byte) []byte {
+				if strings.HasPrefix(name, "uploads/") {
+					return nil
+				}
+				return body
+			}),
+			want: "uploads/1.png",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := openSQLite(t)
+			_, err := Restore(target, t.TempDir(), bytes.NewReader(tt.data), int64(len(tt.data)), false)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.want)
+			}
+			var count int64
+			target.Model(&models.User{}).Count(&count)
+			if count != 0 {
+				t.Errorf("%d users were restored from a rejected archive", count)
+			}
+		})
+	}
+}
+
+func TestListAndRotate(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"blog-backup-20240101-000000.zip",
+		"blog-backup-20240103-000000.zip",
+		"blog-backup-20240102-000000.zip",
+		"notes.zip",
+		".backup-123.tmp",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		keep        int
+		wantRemoved []string
+		wantLeft    []string
+	}{
+		{0, nil, []string{"blog-backup-20240103-000000.zip", "blog-backup-20240102-000000.zip", "blog-backup-20240101-000000.zip"}},
+		{2, []string{"blog-backup-20240101-000000.zip"}, []string{"blog-backup-20240103-000000.zip", "blog-backup-20240102-000000.zip"}},
+		{5, nil, []string{"blog-backup-20240103-000000.zip", "blog-backup-20240102-000000.zip"}},
+	}
+	for _, tt := range tests {
+		removed, err := Rotate(dir, tt.keep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(removed, ",") != strings.Join(tt.wantRemoved, ",") {
+			t.Errorf("keep %d removed %v, want %v", tt.keep, removed, tt.wantRemoved)
+		}
+		backups, err := List(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var left []string
+		for _, b := range backups {
+			left = append(left, b.Name)
+		}
+		if strings.Join(left, ",") != strings.Join(tt.wantLeft, ",") {
+			t.Errorf("keep %d left %v, want %v", tt.keep, left, tt.wantLeft)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "notes.zip")); err != nil {
+		t.Errorf("other files must survive a rotation: %v", err)
+	}
+	if backups, err := List(filepath.Join(dir, "missing")); err != nil || len(backups) != 0 {
+		t.Errorf("List of a missing directory = %v, %v", backups, err)
+	}
+}
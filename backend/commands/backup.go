@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/your-username/blog-backend/backup"
+	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/controllers"
+	"github.com/your-username/blog-backend/database"
+)
+
+// Backup implements the backup command:
+//
+//	blog backup [-dir backups] [-keep 7]
+//
+// It writes a new archive into dir and then deletes all but the newest keep
+// archives, so it can run from cron for scheduled backups.
+func Backup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	dir := flags.String("dir", config.AppConfig.BackupDir, "directory to write the backup to")
+	keep := flags.Int("keep", config.AppConfig.BackupKeep, "number of backups to keep, 0 keeps all")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	path, manifest, err := backup.CreateFile(database.DB, controllers.UploadDir, *dir)
+	if err != nil {
+		return err
+	}
+	log.Printf("Backup written to %s (%d files)", path, len(manifest.Files))
+
+	removed, err := backup.Rotate(*dir, *keep)
+	for _, name := range removed {
+		log.Printf("Removed old backup %s", name)
+	}
+	return err
+}
+
+// Restore implements the restore command:
+//
+//	blog restore [-replace] archive.zip
+//
+// The database has to be empty, e.g. freshly created for another driver,
+// unless -replace is given, which deletes all existing content first.
+func Restore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	replace := flags.Bool("replace", false, "delete the existing content before restoring")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: restore [-replace] archive.zip")
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	manifest, err := backup.Restore(database.DB, controllers.UploadDir, f, info.Size(), *replace)
+	if err != nil {
+		return err
+	}
+
+	rows := 0
+	for _, n := range manifest.Tables {
+		rows += n
+	}
+	log.Printf("Restored %d rows and the uploads of the %s backup from %s", rows, manifest.Driver, manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	return nil
+}
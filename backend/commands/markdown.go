@@ -0,0 +1,98 @@
+// Package commands implements the command line tools run as "blog <command>".
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/your-username/blog-backend/controllers"
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/models"
+)
+
+// ImportMarkdown implements the import-markdown command:
+//
+//	blog import-markdown [-author name] <dir>
+//
+// Every Markdown file below dir becomes an article by the given author, the
+// site author by default. Running it again updates the imported articles.
+func ImportMarkdown(args []string) error {
+	flags := flag.NewFlagSet("import-markdown", flag.ContinueOnError)
+	authorName := flags.String("author", "", "username of the author (default: the site author)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import-markdown [-author name] <dir>")
+	}
+
+	var author models.User
+	query := database.DB.Order("id ASC")
+	if *authorName != "" {
+		query = query.Where("username = ?", *authorName)
+	}
+	if err := query.First(&author).Error; err != nil {
+		return fmt.Errorf("author not found: %w", err)
+	}
+
+	results, err := controllers.ImportMarkdownFS(database.DB, os.DirFS(flags.Arg(0)), author.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			log.Printf("%s: %s: %s", r.File, r.Status, r.Error)
+		default:
+			log.Printf("%s: %s (article %d, slug %s)", r.File, r.Status, r.ArticleID, r.Slug)
+		}
+		for _, warning := range r.Warnings {
+			log.Printf("%s: warning: %s", r.File, warning)
+		}
+	}
+
+	counts := controllers.CountImportResults(results)
+	log.Printf("Imported %d files: %d created, %d updated, %d unchanged, %d failed",
+		len(results), counts["created"], counts["updated"], counts["unchanged"], counts["failed"])
+	if counts["failed"] > 0 {
+		return fmt.Errorf("%d files failed to import", counts["failed"])
+	}
+	return nil
+}
+
+// ExportMarkdown implements the export-markdown command:
+//
+//	blog export-markdown [-out content]
+//
+// It writes every article as a Markdown file with front matter, plus the
+// uploaded images they use, in the layout import-markdown reads.
+func ExportMarkdown(args []string) error {
+	flags := flag.NewFlagSet("export-markdown", flag.ContinueOnError)
+	out := flags.String("out", "content", "output directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	files, err := controllers.ExportMarkdownFiles(database.DB)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		dst := filepath.Join(*out, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dst, file.Data, 0644); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Exported %d files to %s", len(files), *out)
+	return nil
+}
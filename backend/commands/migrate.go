@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/migrations"
+)
+
+const migrateUsage = "usage: migrate up [n] | down [n] | status | create [-dir migrations] <name>"
+
+// Migrate implements the migrate command:
+//
+//	blog migrate up [n]        apply all (or the next n) pending migrations
+//	blog migrate down [n]      revert the last (or the last n) migrations
+//	blog migrate status        list migrations and when they were applied
+//	blog migrate create <name> add empty migration files for every driver
+//
+// create only writes files below -dir and needs no database.
+func Migrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	if args[0] != "create" {
+		database.ConnectDB()
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := migrationCount(args[1:], 0)
+		if err != nil {
+			return err
+		}
+		done, err := migrations.Up(database.DB, n)
+		for _, m := range done {
+			log.Printf("Applied %d_%s", m.Version, m.Name)
+		}
+		if err == nil && len(done) == 0 {
+			log.Println("The schema is up to date")
+		}
+		return err
+	case "down":
+		n, err := migrationCount(args[1:], 1)
+		if err != nil {
+			return err
+		}
+		done, err := migrations.Down(database.DB, n)
+		for _, m := range done {
+			log.Printf("Reverted %d_%s", m.Version, m.Name)
+		}
+		if err == nil && len(done) == 0 {
+			log.Println("No migrations have been applied")
+		}
+		return err
+	case "status":
+		statuses, err := migrations.Statuses(database.DB)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	case "create":
+		flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		dir := flags.String("dir", "migrations", "directory of the migration sources")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return errors.New(migrateUsage)
+		}
+		paths, err := migrations.Create(*dir, flags.Arg(0))
+		for _, path := range paths {
+			log.Printf("Created %s", path)
+		}
+		return err
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// migrationCount parses the optional count argument of up and down.
+func migrationCount(args []string, def int) (int, error) {
+	if len(args) == 0 {
+		return def, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if len(args) > 1 || err != nil || n < 1 {
+		return 0, errors.New(migrateUsage)
+	}
+	return n, nil
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/your-username/blog-backend/controllers"
+	"github.com/your-username/blog-backend/database"
+)
+
+// ImportWordPress implements the import-wordpress command:
+//
+//	blog import-wordpress [-uploads dir] [-pages-category Pages] [-map-author login=username ...] [-report file] export.xml
+//
+// Attachments are copied from a local copy of wp-content/uploads when -uploads
+// is given and downloaded from the old site otherwise. Authors are matched
+// with existing users by -map-author or email only. The user every author
+// and commenter became and the items that were not imported are listed in
+// the report file.
+func ImportWordPress(args []string) error {
+	flags := flag.NewFlagSet("import-wordpress", flag.ContinueOnError)
+	uploads := flags.String("uploads", "", "local copy of wp-content/uploads (default: download attachments)")
+	pages := flags.String("pages-category", "Pages", "category for imported pages, empty to skip pages")
+	reportPath := flags.String("report", "wordpress-import-report.txt", "where to write the import report")
+	authorMap := map[string]string{}
+	flags.Func("map-author", "import the posts of a WordPress author `login=username` as an existing user (repeatable)", func(value string) error {
+		login, username, ok := strings.Cut(value, "=")
+		if !ok || login == "" || username == "" {
+			return errors.New("expected login=username")
+		}
+		authorMap[login] = username
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import-wordpress [-uploads dir] [-pages-category Pages] [-map-author login=username ...] [-report file] export.xml")
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	report, err := controllers.ImportWordPress(database.DB, f, controllers.WordPressImportOptions{
+		UploadsDir:    *uploads,
+		PagesCategory: *pages,
+		AuthorMap:     authorMap,
+		Client:        &http.Client{Timeout: time.Minute},
+	})
+	if report != nil {
+		if writeErr := writeWordPressReport(*reportPath, flags.Arg(0), report); writeErr != nil {
+			log.Printf("Cannot write the report: %v", writeErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Imported %d posts, %d pages, %d comments, %d users, %d categories, %d tags and %d media files; %d items skipped (see %s)",
+		report.Articles, report.Pages, report.Comments, report.Users, report.Categories, report.Tags, report.Media,
+		len(report.Skipped), *reportPath)
+	return nil
+}
+
+func writeWordPressReport(name, source string, report *controllers.WordPressImportReport) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "WordPress import of %s at %s\n\n", source, time.Now().Format(time.RFC3339))
+	fmt.Fprintf(w, "Created: %d posts, %d pages, %d comments, %d users, %d categories, %d tags, %d media files\n",
+		report.Articles, report.Pages, report.Comments, report.Users, report.Categories, report.Tags, report.Media)
+	fmt.Fprintf(w, "Users: %d authors and commenters\n", len(report.Mappings))
+	for _, m := range report.Mappings {
+		fmt.Fprintf(w, "%s\t%s\t%s (%d)\t%s\n", m.Type, m.Source, m.Username, m.UserID, m.Match)
+	}
+	fmt.Fprintf(w, "\nSkipped: %d items\n", len(report.Skipped))
+	for _, item := range report.Skipped {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Type, item.ID, item.Title, item.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -2,17 +2,47 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	ServerPort string `mapstructure:"SERVER_PORT"`
+	DBDriver   string `mapstructure:"DB_DRIVER"` // mysql, postgres or sqlite
 	DBUsername string `mapstructure:"DB_USERNAME"`
 	DBPassword string `mapstructure:"DB_PASSWORD"`
 	DBHost     string `mapstructure:"DB_HOST"`
-	DBPort     string `mapstructure:"DB_PORT"`
+	DBPort     string `mapstructure:"DB_PORT"` // Empty for the driver's default port
 	DBName     string `mapstructure:"DB_NAME"`
+	DBSSLMode  string `mapstructure:"DB_SSLMODE"` // PostgreSQL only
+	DBPath     string `mapstructure:"DB_PATH"`    // SQLite database file
+
+	DBAutoMigrate bool `mapstructure:"DB_AUTO_MIGRATE"` // Development only: AutoMigrate instead of migrations
+
+	SiteURL         string `mapstructure:"SITE_URL"` // Public address of the frontend, used in feeds
+	APIURL          string `mapstructure:"API_URL"`  // Public address of the backend for feed and sitemap links; empty uses SITE_URL
+	SiteTitle       string `mapstructure:"SITE_TITLE"`
+	SiteDescription string `mapstructure:"SITE_DESCRIPTION"`
+	FeedLimit       int    `mapstructure:"FEED_LIMIT"`
+	FeedFullContent bool   `mapstructure:"FEED_FULL_CONTENT"` // Full article HTML instead of the excerpt
+	RobotsDisallow  string `mapstructure:"ROBOTS_DISALLOW"`   // Comma separated paths
+	RobotsTxtFile   string `mapstructure:"ROBOTS_TXT_FILE"`   // Served verbatim instead of the generated file
+	SSREnabled      bool   `mapstructure:"SSR_ENABLED"`       // Serve the frontend with prerendered pages
+	FrontendDist    string `mapstructure:"FRONTEND_DIST"`     // Built frontend (vite build output)
+
+	BackupDir      string        `mapstructure:"BACKUP_DIR"`
+	BackupInterval time.Duration `mapstructure:"BACKUP_INTERVAL"` // e.g. 24h; 0 disables scheduled backups
+	BackupKeep     int           `mapstructure:"BACKUP_KEEP"`     // Newest backups to keep; 0 keeps all
+
+	TrashRetentionDays int `mapstructure:"TRASH_RETENTION_DAYS"` // Purge deleted items after this many days; 0 keeps them
+
+	AnalyticsEnabled             bool          `mapstructure:"ANALYTICS_ENABLED"`               // Record page views sent by the frontend
+	AnalyticsRetentionDays       int           `mapstructure:"ANALYTICS_RETENTION_DAYS"`        // Delete raw page views after this many days (at least 2); 0 keeps them
+	AnalyticsHourlyRetentionDays int           `mapstructure:"ANALYTICS_HOURLY_RETENTION_DAYS"` // Delete hourly statistics after this many days; 0 keeps them
+	AnalyticsFlushInterval       time.Duration `mapstructure:"ANALYTICS_FLUSH_INTERVAL"`        // How often buffered page views are written
+
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"` // Comma separated IPs or CIDRs whose X-Forwarded-For is used; empty trusts none
 }
 
 var AppConfig *Config
@@ -25,11 +55,34 @@ func LoadConfig() {
 
 	// Set defaults
 	viper.SetDefault("SERVER_PORT", "8080")
+	viper.SetDefault("DB_DRIVER", "mysql")
 	viper.SetDefault("DB_USERNAME", "root")
 	viper.SetDefault("DB_PASSWORD", "root")
 	viper.SetDefault("DB_HOST", "127.0.0.1")
-	viper.SetDefault("DB_PORT", "3306")
+	viper.SetDefault("DB_PORT", "")
 	viper.SetDefault("DB_NAME", "blog_db")
+	viper.SetDefault("DB_SSLMODE", "disable")
+	viper.SetDefault("DB_PATH", "blog.db")
+	viper.SetDefault("DB_AUTO_MIGRATE", false)
+	viper.SetDefault("SITE_URL", "http://localhost:5173")
+	viper.SetDefault("API_URL", "")
+	viper.SetDefault("SITE_TITLE", "My Blog")
+	viper.SetDefault("SITE_DESCRIPTION", "")
+	viper.SetDefault("FEED_LIMIT", 20)
+	viper.SetDefault("FEED_FULL_CONTENT", true)
+	viper.SetDefault("ROBOTS_DISALLOW", "/api/,/login,/register,/profile,/articles/new")
+	viper.SetDefault("ROBOTS_TXT_FILE", "")
+	viper.SetDefault("SSR_ENABLED", false)
+	viper.SetDefault("FRONTEND_DIST", "../frontend/dist")
+	viper.SetDefault("BACKUP_DIR", "backups")
+	viper.SetDefault("BACKUP_INTERVAL", "0")
+	viper.SetDefault("BACKUP_KEEP", 7)
+	viper.SetDefault("TRASH_RETENTION_DAYS", 0)
+	viper.SetDefault("ANALYTICS_ENABLED", true)
+	viper.SetDefault("ANALYTICS_RETENTION_DAYS", 30)
+	viper.SetDefault("ANALYTICS_HOURLY_RETENTION_DAYS", 7)
+	viper.SetDefault("ANALYTICS_FLUSH_INTERVAL", "10s")
+	viper.SetDefault("TRUSTED_PROXIES", "")
 
 	if err := viper.ReadInConfig(); err != nil {
 		log.Println("No .env file found, using defaults/environment variables")
@@ -0,0 +1,297 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/analytics"
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+// pageViewBuffer is the number of page views held in memory between writes.
+const pageViewBuffer = 10000
+
+// AnalyticsController records and reports page views.
+type AnalyticsController struct {
+	db *gorm.DB
+	// views buffers the views recorded by CollectPageView; nil while
+	// analytics are disabled.
+	views  *analytics.Writer
+	hasher analytics.Hasher
+}
+
+func NewAnalyticsController(db *gorm.DB) *AnalyticsController {
+	return &AnalyticsController{db: db}
+}
+
+// analyticsIntervals are the intervals of GetAnalytics, the default first.
+var analyticsIntervals = []statsInterval{
+	{"day", func(to time.Time) time.Time { return to.AddDate(0, 0, -29) }},
+	{"hour", func(to time.Time) time.Time { return to.AddDate(0, 0, -1) }},
+}
+
+// analyticsBreakdowns are the columns of the raw page views GetAnalytics
+// groups by.
+var analyticsBreakdowns = []struct{ name, column string }{
+	{"referrers", "referrer"},
+	{"utm_sources", "utm_source"},
+	{"utm_mediums", "utm_medium"},
+	{"utm_campaigns", "utm_campaign"},
+	{"devices", "device"},
+	{"browsers", "browser"},
+	{"os", "os"},
+}
+
+// Start starts recording page views. Buffered views are written
+// every flush; once an hour they are rolled up, raw views older than
+// retention and hourly statistics older than hourlyRetention are deleted (0
+// keeps them).
+func (ctl *AnalyticsController) Start(flush, retention, hourlyRetention time.Duration) {
+	ctl.views = analytics.NewWriter(ctl.db, pageViewBuffer)
+	go ctl.views.Run(flush)
+
+	if retention > 0 && retention < analytics.MinRetention {
+		retention = analytics.MinRetention
+	}
+	go func() {
+		for {
+			if err := analytics.Rollup(ctl.db, time.Now()); err != nil {
+				log.Printf("Analytics rollup failed: %v", err)
+				time.Sleep(time.Hour)
+				continue
+			}
+			if retention > 0 {
+				if n, err := analytics.Prune(ctl.db, time.Now().Add(-retention)); err != nil {
+					log.Printf("Deleting old page views failed: %v", err)
+				} else if n > 0 {
+					log.Printf("Deleted %d page views older than the retention", n)
+				}
+			}
+			if hourlyRetention > 0 {
+				if n, err := analytics.PruneHourly(ctl.db, time.Now().Add(-hourlyRetention)); err != nil {
+					log.Printf("Deleting old hourly statistics failed: %v", err)
+				} else if n > 0 {
+					log.Printf("Deleted %d hourly statistics older than the retention", n)
+				}
+			}
+			time.Sleep(time.Hour)
+		}
+	}()
+}
+
+// trackedPage cleans the path of a page view and reports whether it is a
+// public page of the frontend: the home, timeline or about page, an article,
+// or a category, tag or author page. Anything else, such as the editor or a
+// made-up address, is not counted.
+func trackedPage(p string) (string, bool) {
+	p = path.Clean(p)
+	switch p {
+	case "/", "/timeline", "/about":
+		return p, true
+	}
+	return p, articlePagePattern.MatchString(p) || taxonomyPagePattern.MatchString(p)
+}
+
+// PageViewInput is the body of the analytics beacon. Path is the address of
+// the page within the site including the query string, which carries the
+// utm_ parameters. Referrer is document.referrer, sent with the first page
+// of a visit only.
+type PageViewInput struct {
+	Path     string `json:"path"`
+	Referrer string `json:"referrer"`
+}
+
+// CollectPageView records a page view. The frontend sends it with
+// navigator.sendBeacon, which posts JSON as text/plain, so the body is read
+// whatever its content type. Only the public pages of the frontend are
+// counted, and crawlers are ignored.
+func (ctl *AnalyticsController) CollectPageView(c *gin.Context) {
+	if ctl.views == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	var input PageViewInput
+	if err := json.NewDecoder(io.LimitReader(c.Request.Body, 4096)).Decode(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page view"})
+		return
+	}
+	page, err := url.Parse(input.Path)
+	if err != nil || page.Scheme != "" || page.Host != "" || !strings.HasPrefix(page.Path, "/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "path must be a path within the site"})
+		return
+	}
+	pagePath, ok := trackedPage(page.Path)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "path is not a page of the site"})
+		return
+	}
+
+	userAgent := c.Request.UserAgent()
+	client := analytics.ParseUserAgent(userAgent)
+	if client.Bot {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	now := time.Now()
+	query := page.Query()
+	ctl.views.Add(models.PageView{
+		Path:        clip(pagePath, 255),
+		Referrer:    referrerHost(c, input.Referrer),
+		UTMSource:   clip(query.Get("utm_source"), 100),
+		UTMMedium:   clip(query.Get("utm_medium"), 100),
+		UTMCampaign: clip(query.Get("utm_campaign"), 100),
+		Device:      client.Device,
+		Browser:     client.Browser,
+		OS:          client.OS,
+		VisitorHash: ctl.hasher.Hash(now, c.ClientIP(), userAgent),
+		CreatedAt:   now,
+	})
+	c.Status(http.StatusNoContent)
+}
+
+// clip shortens s to at most n characters.
+func clip(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// AnalyticsPoint holds the page views of one hour (YYYY-MM-DD HH:00) or day.
+// Visitors are unique within the period.
+type AnalyticsPoint struct {
+	Period   string `json:"period"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// AnalyticsCount is a page or a value of a breakdown with its views and
+// visitors. Visitors are unique per day and summed over the days, as the
+// visitor hash changes every day.
+type AnalyticsCount struct {
+	Value    string `json:"value"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// GetAnalytics reports the page views between ?from= and ?to= (YYYY-MM-DD,
+// inclusive): views and unique visitors per day or hour (?interval=), the
+// most viewed pages, and the top referrers, UTM parameters, devices,
+// browsers and operating systems. The breakdowns are counted from the raw
+// page views, so they only reach back ANALYTICS_RETENTION_DAYS.
+func (ctl *AnalyticsController) GetAnalytics(c *gin.Context) {
+	q, err := parseStatsRange(c, analyticsIntervals)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := ctl.db
+	// Include the views of the last minutes
+	if ctl.views != nil {
+		if _, err := ctl.views.Flush(); err != nil {
+			log.Printf("Failed to store page views: %v", err)
+		}
+	}
+	if err := analytics.Rollup(db, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var model interface{} = &models.DailyPageStat{}
+	if q.interval == "hour" {
+		model = &models.HourlyPageStat{}
+	}
+	series, err := analyticsSeries(db, model, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var totals struct {
+		Views    int64 `json:"views"`
+		Visitors int64 `json:"visitors"`
+	}
+	err = db.Model(&models.DailyPageStat{}).Where("path = '' AND period >= ? AND period < ?", q.from, q.to).
+		Select("COALESCE(SUM(views), 0) AS views, COALESCE(SUM(visitors), 0) AS visitors").Scan(&totals).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	topPages := []AnalyticsCount{}
+	err = db.Model(&models.DailyPageStat{}).Where("path <> '' AND period >= ? AND period < ?", q.from, q.to).
+		Select("path AS value, SUM(views) AS views, SUM(visitors) AS visitors").
+		Group("path").Order("views DESC, path").Limit(q.limit).
+		Scan(&topPages).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	breakdowns := gin.H{}
+	for _, b := range analyticsBreakdowns {
+		counts := []AnalyticsCount{}
+		err := db.Model(&models.PageView{}).
+			Where(b.column+" <> '' AND created_at >= ? AND created_at < ?", q.from, q.to).
+			Select(b.column + " AS value, COUNT(*) AS views, COUNT(DISTINCT visitor_hash) AS visitors").
+			Group(b.column).Order("views DESC, value").Limit(q.limit).
+			Scan(&counts).Error
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		breakdowns[b.name] = counts
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interval":   q.interval,
+		"from":       q.from.Format(time.DateOnly),
+		"to":         q.to.AddDate(0, 0, -1).Format(time.DateOnly),
+		"totals":     totals,
+		"series":     series,
+		"top_pages":  topPages,
+		"breakdowns": breakdowns,
+	})
+}
+
+// analyticsSeries reads the site totals of model, a rollup table, for every
+// period of the range, including the periods without views.
+func analyticsSeries(tx *gorm.DB, model interface{}, q statsRange) ([]AnalyticsPoint, error) {
+	periods := statsPeriods(q)
+	points := make([]AnalyticsPoint, len(periods))
+	index := make(map[string]int, len(periods))
+	for i, period := range periods {
+		points[i].Period = period
+		index[period] = i
+	}
+
+	var rows []struct {
+		Period   time.Time
+		Views    int64
+		Visitors int64
+	}
+	err := tx.Model(model).Where("path = '' AND period >= ? AND period < ?", q.from, q.to).
+		Select("period, views, visitors").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if i, ok := index[periodLabel(q.interval, row.Period.In(time.Local))]; ok {
+			points[i].Views = row.Views
+			points[i].Visitors = row.Visitors
+		}
+	}
+	return points, nil
+}
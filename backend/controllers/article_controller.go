@@ -1,29 +1,55 @@
 package controllers
 
 import (
+	"errors"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/your-username/blog-backend/database"
-	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
 )
 
-type CreateArticleInput struct {
-	Title      string   `json:"title" binding:"required"`
-	Content    string   `json:"content" binding:"required"`
-	CategoryID *uint    `json:"category_id"`
-	Tags       []string `json:"tags"` // List of tag names
+// ArticleController serves the article endpoints.
+type ArticleController struct {
+	articles *services.ArticleService
+	related  *services.RelatedService
 }
 
-type UpdateArticleInput struct {
-	Title      string   `json:"title"`
-	Content    string   `json:"content"`
-	CategoryID *uint    `json:"category_id"`
-	Tags       []string `json:"tags"`
+func NewArticleController(articles *services.ArticleService, related *services.RelatedService) *ArticleController {
+	return &ArticleController{articles: articles, related: related}
 }
 
-func CreateArticle(c *gin.Context) {
-	var input CreateArticleInput
+// paramID parses the :id path parameter. Anything that is not an ID cannot
+// name an existing record.
+func paramID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	return uint(id), err == nil
+}
+
+// articleWriteError maps the errors of ArticleService writes to responses.
+func articleWriteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+	case errors.Is(err, services.ErrSlugUnavailable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCoverNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cover media not found"})
+	case errors.Is(err, services.ErrSeriesNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Series not found"})
+	case errors.Is(err, services.ErrRenderFailed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to render content"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (ctl *ArticleController) CreateArticle(c *gin.Context) {
+	var input services.CreateArticleInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -35,161 +61,167 @@ func CreateArticle(c *gin.Context) {
 		return
 	}
 
-	// Handle Tags
-	var tags []models.Tag
-	for _, tagName := range input.Tags {
-		var tag models.Tag
-		if err := database.DB.FirstOrCreate(&tag, models.Tag{Name: tagName}).Error; err != nil {
-			continue
-		}
-		tags = append(tags, tag)
-	}
-
-	article := models.Article{
-		Title:      input.Title,
-		Content:    input.Content,
-		AuthorID:   userID.(uint),
-		CategoryID: input.CategoryID,
-		Tags:       tags,
-	}
-
-	if err := database.DB.Create(&article).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	article, err := ctl.articles.Create(userID.(uint), input)
+	if err != nil {
+		articleWriteError(c, err)
 		return
 	}
 
-	// Preload associations for response
-	database.DB.Preload("Author").Preload("Category").Preload("Tags").First(&article, article.ID)
-
+	ctl.related.Invalidate(article.ID)
 	c.JSON(http.StatusOK, article)
 }
 
-func GetArticles(c *gin.Context) {
-	var articles []models.Article
-	query := database.DB.Preload("Author").Preload("Category").Preload("Tags")
-
-	search := c.Query("search")
-	if search != "" {
-		query = query.Where("title LIKE ? OR content LIKE ?", "%"+search+"%", "%"+search+"%")
+// GetArticles returns the lightweight list projection of articles. The
+// optional fields parameter (e.g. fields=id,title,tags) limits the response to
+// the listed fields; content is only included when requested explicitly.
+// Drafts are only listed for signed-in users.
+func (ctl *ArticleController) GetArticles(c *gin.Context) {
+	fields, err := parseListFields(c.Query("fields"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	categoryID := c.Query("category_id")
-	if categoryID != "" {
-		query = query.Where("category_id = ?", categoryID)
+	_, signedIn := c.Get("user_id")
+	opts := services.ArticleListOptions{
+		ArticleQuery: repository.ArticleQuery{
+			IncludeDrafts: signedIn,
+			Search:        c.Query("search"),
+			Columns:       articleListColumnNames(fields),
+			// Only load the associations the projection needs
+			WithAuthor:   hasField(fields, "author"),
+			WithCategory: hasField(fields, "category"),
+			WithTags:     hasField(fields, "tags"),
+		},
+		IncludeDescendants: c.Query("include_descendants") == "true",
 	}
 
-	tagID := c.Query("tag_id")
-	if tagID != "" {
-		query = query.Joins("JOIN article_tags ON article_tags.article_id = articles.id").Where("article_tags.tag_id = ?", tagID)
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := strconv.ParseUint(categoryID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id"})
+			return
+		}
+		opts.CategoryID = uint(id)
+	}
+	if tagID := c.Query("tag_id"); tagID != "" {
+		id, err := strconv.ParseUint(tagID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag_id"})
+			return
+		}
+		opts.TagID = uint(id)
 	}
 
-	if err := query.Find(&articles).Error; err != nil {
+	articles, err := ctl.articles.List(opts)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, articles)
+	items := make([]ArticleListItem, 0, len(articles))
+	for _, a := range articles {
+		items = append(items, newArticleListItem(a))
+	}
+
+	if c.Query("fields") == "" {
+		c.JSON(http.StatusOK, items)
+		return
+	}
+
+	c.JSON(http.StatusOK, projectArticleList(items, fields))
 }
 
-func GetArticle(c *gin.Context) {
-	id := c.Param("id")
-	var article models.Article
+// GetArticle returns a single article. The optional format query parameter
+// selects the body representation: raw (Markdown only), html, or both (default).
+// Drafts are only returned to signed-in users.
+func (ctl *ArticleController) GetArticle(c *gin.Context) {
+	format := c.DefaultQuery("format", "both")
+	if format != "raw" && format != "html" && format != "both" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of raw, html, both"})
+		return
+	}
 
-	if err := database.DB.Preload("Author").Preload("Category").Preload("Tags").First(&article, id).Error; err != nil {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
 		return
 	}
 
+	_, signedIn := c.Get("user_id")
+	article, err := ctl.articles.Get(id, signedIn)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch format {
+	case "raw":
+		article.ContentHTML = ""
+	case "html":
+		article.Content = ""
+	}
+
 	c.JSON(http.StatusOK, article)
 }
 
-func UpdateArticle(c *gin.Context) {
-	id := c.Param("id")
-	var article models.Article
-
-	if err := database.DB.First(&article, id).Error; err != nil {
+// UpdateArticle lets any signed-in user edit an article (single user blog).
+func (ctl *ArticleController) UpdateArticle(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
 		return
 	}
 
-	_, exists := c.Get("user_id")
-	if !exists {
+	if _, exists := c.Get("user_id"); !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	// Allow any logged-in user to update (Single User Blog / Admin mode)
-	// if article.AuthorID != userID.(uint) {
-	// 	c.JSON(http.StatusForbidden, gin.H{"error": "You are not the author of this article"})
-	// 	return
-	// }
-
-	var input UpdateArticleInput
+	var input services.UpdateArticleInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update basic fields
-	if input.Title != "" {
-		article.Title = input.Title
-	}
-	if input.Content != "" {
-		article.Content = input.Content
-	}
-	if input.CategoryID != nil {
-		article.CategoryID = input.CategoryID
-	}
-
-	// Update Tags if provided
-	if input.Tags != nil {
-		var tags []models.Tag
-		for _, tagName := range input.Tags {
-			var tag models.Tag
-			if err := database.DB.FirstOrCreate(&tag, models.Tag{Name: tagName}).Error; err != nil {
-				continue
-			}
-			tags = append(tags, tag)
-		}
-		// Replace tags
-		database.DB.Model(&article).Association("Tags").Replace(tags)
-	}
-
-	if err := database.DB.Save(&article).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	article, err := ctl.articles.Update(id, input)
+	if err != nil {
+		articleWriteError(c, err)
 		return
 	}
 
-	// Preload associations for response
-	database.DB.Preload("Author").Preload("Category").Preload("Tags").First(&article, article.ID)
-
+	ctl.related.Invalidate(article.ID)
 	c.JSON(http.StatusOK, article)
 }
 
-func DeleteArticle(c *gin.Context) {
-	var article models.Article
-	if err := database.DB.Where("id = ?", c.Param("id")).First(&article).Error; err != nil {
+// DeleteArticle lets any signed-in user delete an article.
+func (ctl *ArticleController) DeleteArticle(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Article not found"})
 		return
 	}
 
-	_, exists := c.Get("user_id")
-	if !exists {
+	if _, exists := c.Get("user_id"); !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	// Allow any logged-in user to delete
-	// if article.AuthorID != userID.(uint) {
-	// 	c.JSON(http.StatusForbidden, gin.H{"error": "You are not the author of this article"})
-	// 	return
-	// }
-
-	if err := database.DB.Delete(&article).Error; err != nil {
+	err := ctl.articles.Delete(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Article not found"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	ctl.related.Invalidate(id)
 	c.JSON(http.StatusOK, gin.H{"message": "Article deleted successfully"})
 }
 
@@ -197,51 +229,127 @@ type BatchDeleteInput struct {
 	IDs []uint `json:"ids" binding:"required"`
 }
 
-func BatchDeleteArticles(c *gin.Context) {
+// BatchDeleteArticles deletes the listed articles regardless of their author
+// so the admin can clean up.
+func (ctl *ArticleController) BatchDeleteArticles(c *gin.Context) {
 	var input BatchDeleteInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	_, exists := c.Get("user_id")
-	if !exists {
+	if _, exists := c.Get("user_id"); !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	// Delete articles where id IN input.IDs
-	// Removed author_id check to allow admin deletion of any article
-	result := database.DB.Where("id IN ?", input.IDs).Delete(&models.Article{})
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+	count, err := ctl.articles.DeleteMany(input.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Articles deleted successfully", "count": result.RowsAffected})
+	ctl.related.Invalidate(input.IDs...)
+	c.JSON(http.StatusOK, gin.H{"message": "Articles deleted successfully", "count": count})
 }
 
-func LikeArticle(c *gin.Context) {
-	var article models.Article
-	if err := database.DB.First(&article, c.Param("id")).Error; err != nil {
+// GetFeaturedArticles returns featured articles for the homepage carousel.
+func (ctl *ArticleController) GetFeaturedArticles(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit <= 0 || limit > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+		return
+	}
+
+	articles, err := ctl.articles.List(services.ArticleListOptions{
+		ArticleQuery: repository.ArticleQuery{
+			Featured:     true,
+			Columns:      articleListColumnNames(defaultArticleListFields),
+			WithAuthor:   true,
+			WithCategory: true,
+			WithTags:     true,
+			Limit:        limit,
+		},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]ArticleListItem, 0, len(articles))
+	for _, a := range articles {
+		items = append(items, newArticleListItem(a))
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (ctl *ArticleController) LikeArticle(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
 		return
 	}
 
-	// In a real app, you'd track *who* liked it to prevent duplicates
-	article.Likes++
-	database.DB.Save(&article)
-	c.JSON(http.StatusOK, gin.H{"likes": article.Likes})
+	likes, err := ctl.articles.Like(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"likes": likes})
 }
 
-func ViewArticle(c *gin.Context) {
-	var article models.Article
-	if err := database.DB.First(&article, c.Param("id")).Error; err != nil {
+// ViewInput is the optional body of ViewArticle. The page sends
+// document.referrer, as the Referer header of its own request names the blog.
+type ViewInput struct {
+	Referrer string `json:"referrer"`
+}
+
+func (ctl *ArticleController) ViewArticle(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
 		return
 	}
 
-	article.Views++
-	database.DB.Save(&article)
-	c.JSON(http.StatusOK, gin.H{"views": article.Views})
+	var input ViewInput
+	_ = c.ShouldBindJSON(&input) // The body is optional
+	if input.Referrer == "" {
+		input.Referrer = c.Request.Referer()
+	}
+
+	views, err := ctl.articles.View(id, referrerHost(c, input.Referrer))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"views": views})
+}
+
+// referrerHost reduces a referrer URL to the host of the referring site.
+// Links within the blog and anything that is not a web page count as direct
+// visits.
+func referrerHost(c *gin.Context, referrer string) string {
+	u, err := url.Parse(referrer)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host == "" || len(host) > 255 {
+		return ""
+	}
+
+	own := []string{c.Request.Host}
+	if site, err := url.Parse(siteURL()); err == nil {
+		own = append(own, site.Host)
+	}
+	for _, h := range own {
+		if hostname, _, err := net.SplitHostPort(h); err == nil {
+			h = hostname
+		}
+		if host == strings.TrimPrefix(strings.ToLower(h), "www.") {
+			return ""
+		}
+	}
+	return host
 }
@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+)
+
+// AuthorSummary is the part of models.User shown next to an article in lists.
+type AuthorSummary struct {
+	ID        uint   `json:"id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// ArticleListItem is the lightweight projection returned by GetArticles.
+type ArticleListItem struct {
+	ID          uint             `json:"id"`
+	Title       string           `json:"title"`
+	Slug        string           `json:"slug"`
+	Excerpt     string           `json:"excerpt"`
+	Content     string           `json:"content,omitempty"` // Only with fields=content
+	CoverURL    string           `json:"cover_url"`
+	Pinned      bool             `json:"pinned"`
+	Featured    bool             `json:"featured"`
+	Draft       bool             `json:"draft"`
+	CategoryID  *uint            `json:"category_id"`
+	Category    *models.Category `json:"category"`
+	Tags        []models.Tag     `json:"tags"`
+	AuthorID    uint             `json:"author_id"`
+	Author      AuthorSummary    `json:"author"`
+	Views       uint             `json:"views"`
+	Likes       uint             `json:"likes"`
+	WordCount   int              `json:"word_count"`
+	ReadingTime int              `json:"reading_time"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// articleListColumns maps each selectable list field to the article columns it
+// needs. Fields backed by an association have no columns of their own.
+var articleListColumns = map[string][]string{
+	"id":           {"id"},
+	"title":        {"title"},
+	"slug":         {"slug"},
+	"excerpt":      {"excerpt"},
+	"content":      {"content"},
+	"cover_url":    {"cover_url"},
+	"pinned":       {"pinned", "pin_order"},
+	"featured":     {"featured"},
+	"draft":        {"draft"},
+	"category_id":  {"category_id"},
+	"category":     {"category_id"},
+	"tags":         {},
+	"author_id":    {"author_id"},
+	"author":       {"author_id"},
+	"views":        {"views"},
+	"likes":        {"likes"},
+	"word_count":   {"word_count"},
+	"reading_time": {"reading_time"},
+	"created_at":   {"created_at"},
+	"updated_at":   {"updated_at"},
+}
+
+// defaultArticleListFields is everything except the full Markdown content.
+var defaultArticleListFields = []string{
+	"id", "title", "slug", "excerpt", "cover_url", "pinned", "featured", "draft", "category_id", "category",
+	"tags", "author_id", "author", "views", "likes", "word_count", "reading_time", "created_at", "updated_at",
+}
+
+// parseListFields parses a comma separated fields= parameter. An empty value
+// selects the default projection.
+func parseListFields(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return defaultArticleListFields, nil
+	}
+
+	fields := []string{"id"}
+	seen := map[string]bool{"id": true}
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" || seen[f] {
+			continue
+		}
+		if _, ok := articleListColumns[f]; !ok {
+			return nil, fmt.Errorf("unknown field %q", f)
+		}
+		seen[f] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// articleListColumnNames returns the article columns needed for fields.
+func articleListColumnNames(fields []string) []string {
+	seen := map[string]bool{}
+	columns := []string{}
+	for _, f := range fields {
+		for _, col := range articleListColumns[f] {
+			if !seen[col] {
+				seen[col] = true
+				columns = append(columns, col)
+			}
+		}
+	}
+	return columns
+}
+
+func hasField(fields []string, name string) bool {
+	for _, f := range fields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func newArticleListItem(a models.Article) ArticleListItem {
+	item := ArticleListItem{
+		ID:          a.ID,
+		Title:       a.Title,
+		Slug:        a.Slug,
+		Excerpt:     a.Excerpt,
+		Content:     a.Content,
+		CoverURL:    a.CoverURL,
+		Pinned:      a.Pinned,
+		Featured:    a.Featured,
+		Draft:       a.Draft,
+		CategoryID:  a.CategoryID,
+		Tags:        a.Tags,
+		AuthorID:    a.AuthorID,
+		Author:      AuthorSummary{ID: a.Author.ID, Username: a.Author.Username, AvatarURL: a.Author.AvatarURL},
+		Views:       a.Views,
+		Likes:       a.Likes,
+		WordCount:   a.WordCount,
+		ReadingTime: a.ReadingTime,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+	if a.CategoryID != nil {
+		category := a.Category
+		item.Category = &category
+	}
+	if item.Tags == nil {
+		item.Tags = []models.Tag{}
+	}
+	return item
+}
+
+// field returns the value of a list field by its JSON name.
+func (item ArticleListItem) field(name string) interface{} {
+	switch name {
+	case "id":
+		return item.ID
+	case "title":
+		return item.Title
+	case "slug":
+		return item.Slug
+	case "excerpt":
+		return item.Excerpt
+	case "content":
+		return item.Content
+	case "cover_url":
+		return item.CoverURL
+	case "pinned":
+		return item.Pinned
+	case "featured":
+		return item.Featured
+	case "draft":
+		return item.Draft
+	case "category_id":
+		return item.CategoryID
+	case "category":
+		return item.Category
+	case "tags":
+		return item.Tags
+	case "author_id":
+		return item.AuthorID
+	case "author":
+		return item.Author
+	case "views":
+		return item.Views
+	case "likes":
+		return item.Likes
+	case "word_count":
+		return item.WordCount
+	case "reading_time":
+		return item.ReadingTime
+	case "created_at":
+		return item.CreatedAt
+	case "updated_at":
+		return item.UpdatedAt
+	}
+	return nil
+}
+
+// projectArticleList keeps only the requested fields of each item.
+func projectArticleList(items []ArticleListItem, fields []string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		projected := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			projected[f] = item.field(f)
+		}
+		result = append(result, projected)
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/backup"
+	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/services"
+	"gorm.io/gorm"
+)
+
+// BackupController serves the backup endpoints and writes scheduled
+// backups.
+type BackupController struct {
+	db      *gorm.DB
+	related *services.RelatedService
+
+	// mu serializes backups and restores started from the API and the
+	// scheduler.
+	mu sync.Mutex
+}
+
+func NewBackupController(db *gorm.DB, related *services.RelatedService) *BackupController {
+	return &BackupController{db: db, related: related}
+}
+
+// CreateBackupFile writes a backup into BACKUP_DIR and deletes the backups
+// beyond BACKUP_KEEP.
+func (ctl *BackupController) CreateBackupFile() (string, *backup.Manifest, error) {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+
+	path, manifest, err := backup.CreateFile(ctl.db, UploadDir, config.AppConfig.BackupDir)
+	if err != nil {
+		return "", nil, err
+	}
+	if removed, err := backup.Rotate(config.AppConfig.BackupDir, config.AppConfig.BackupKeep); err != nil {
+		log.Printf("Backup rotation failed: %v", err)
+	} else if len(removed) > 0 {
+		log.Printf("Removed %d old backups", len(removed))
+	}
+	return path, manifest, nil
+}
+
+// StartScheduler creates a backup every interval in the background.
+func (ctl *BackupController) StartScheduler(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+			if path, _, err := ctl.CreateBackupFile(); err != nil {
+				log.Printf("Scheduled backup failed: %v", err)
+			} else {
+				log.Printf("Scheduled backup written to %s", path)
+			}
+		}
+	}()
+}
+
+// GetBackups lists the backups in BACKUP_DIR, newest first.
+func (ctl *BackupController) GetBackups(c *gin.Context) {
+	backups, err := backup.List(config.AppConfig.BackupDir)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, backups)
+}
+
+func (ctl *BackupController) CreateBackup(c *gin.Context) {
+	path, manifest, err := ctl.CreateBackupFile()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"name": filepath.Base(path), "manifest": manifest})
+}
+
+// backupPath returns the file of the backup named in the URL, or "" after
+// responding with an error.
+func backupPath(c *gin.Context) string {
+	name := c.Param("name")
+	if !backup.ValidName(name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid backup name"})
+		return ""
+	}
+	path := filepath.Join(config.AppConfig.BackupDir, name)
+	if _, err := os.Stat(path); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backup not found"})
+		return ""
+	}
+	return path
+}
+
+func (ctl *BackupController) DownloadBackup(c *gin.Context) {
+	if path := backupPath(c); path != "" {
+		c.FileAttachment(path, filepath.Base(path))
+	}
+}
+
+func (ctl *BackupController) DeleteBackup(c *gin.Context) {
+	path := backupPath(c)
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Backup deleted successfully"})
+}
+
+// RestoreBackup restores an uploaded archive (form field "file"). Since the
+// database always has at least the signed-in user, replace=true is required
+// to delete the current content first.
+func (ctl *BackupController) RestoreBackup(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file is received"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	ctl.mu.Lock()
+	manifest, err := backup.Restore(ctl.db, UploadDir, file, header.Size, c.PostForm("replace") == "true")
+	ctl.mu.Unlock()
+	if errors.Is(err, backup.ErrNotEmpty) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error() + "; set replace=true to overwrite it"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctl.related.Reset()
+	c.JSON(http.StatusOK, gin.H{"message": "Backup restored successfully", "manifest": manifest})
+}
@@ -4,33 +4,40 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/your-username/blog-backend/database"
-	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/services"
 )
 
-type CreateCategoryInput struct {
-	Name string `json:"name" binding:"required"`
-}
-
-func CreateCategory(c *gin.Context) {
-	var input CreateCategoryInput
+func (ctl *TaxonomyController) CreateCategory(c *gin.Context) {
+	var input services.CreateCategoryInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	category := models.Category{Name: input.Name}
-	if err := database.DB.Create(&category).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	category, err := ctl.taxonomy.CreateCategory(input)
+	if err != nil {
+		taxonomyError(c, err, "Category")
 		return
 	}
 
 	c.JSON(http.StatusOK, category)
 }
 
-func GetCategories(c *gin.Context) {
-	var categories []models.Category
-	if err := database.DB.Find(&categories).Error; err != nil {
+// GetCategories returns the flat category list, or with tree=true the nested
+// hierarchy with per-node article counts.
+func (ctl *TaxonomyController) GetCategories(c *gin.Context) {
+	if c.Query("tree") == "true" {
+		tree, err := ctl.taxonomy.CategoryTree()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, tree)
+		return
+	}
+
+	categories, err := ctl.taxonomy.Categories()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -38,77 +45,90 @@ func GetCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, categories)
 }
 
-func DeleteCategory(c *gin.Context) {
-	id := c.Param("id")
-	var category models.Category
+// GetCategory looks a category up by ID or slug.
+func (ctl *TaxonomyController) GetCategory(c *gin.Context) {
+	category, err := ctl.taxonomy.GetCategory(c.Param("id"))
+	if err != nil {
+		taxonomyError(c, err, "Category")
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
 
-	if err := database.DB.First(&category, id).Error; err != nil {
+// DeleteCategory removes a category. See parseDeleteOptions for the modes;
+// with dry_run=true only the affected counts are returned.
+func (ctl *TaxonomyController) DeleteCategory(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
 		return
 	}
 
-	// Find all articles in this category
-	var articles []models.Article
-	if err := database.DB.Where("category_id = ?", id).Find(&articles).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find associated articles"})
+	opts, err := parseDeleteOptions(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var articleIDs []uint
-	for _, a := range articles {
-		articleIDs = append(articleIDs, a.ID)
+	preview, err := ctl.taxonomy.DeleteCategory(id, opts)
+	if err != nil {
+		taxonomyError(c, err, "Category")
+		return
+	}
+	if opts.DryRun {
+		c.JSON(http.StatusOK, preview)
+		return
 	}
 
-	if len(articleIDs) > 0 {
-		// Delete mappings in article_tags
-		if err := database.DB.Exec("DELETE FROM article_tags WHERE article_id IN ?", articleIDs).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete article tags"})
-			return
-		}
+	ctl.related.Reset()
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully", "result": preview})
+}
 
-		// Delete comments
-		if err := database.DB.Exec("DELETE FROM comments WHERE article_id IN ?", articleIDs).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete article comments"})
-			return
-		}
+func (ctl *TaxonomyController) UpdateCategory(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
 
-		// Hard delete articles
-		if err := database.DB.Unscoped().Delete(&models.Article{}, articleIDs).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete associated articles"})
-			return
-		}
+	var input services.UpdateCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if err := database.DB.Delete(&category).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	category, err := ctl.taxonomy.UpdateCategory(id, input)
+	if err != nil {
+		taxonomyError(c, err, "Category")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Category and associated articles deleted successfully"})
+	c.JSON(http.StatusOK, category)
 }
 
-type UpdateCategoryInput struct {
-	Name string `json:"name" binding:"required"`
+type MoveCategoryInput struct {
+	ParentID *uint `json:"parent_id"` // null moves the category to the top level
 }
 
-func UpdateCategory(c *gin.Context) {
-	id := c.Param("id")
-	var category models.Category
-
-	if err := database.DB.First(&category, id).Error; err != nil {
+// MoveCategory moves a category, together with its subtree, below another
+// parent.
+func (ctl *TaxonomyController) MoveCategory(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
 		return
 	}
 
-	var input UpdateCategoryInput
+	var input MoveCategoryInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	category.Name = input.Name
-	if err := database.DB.Save(&category).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	category, err := ctl.taxonomy.MoveCategory(id, input.ParentID)
+	if err != nil {
+		taxonomyError(c, err, "Category")
 		return
 	}
 
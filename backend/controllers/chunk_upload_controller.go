@@ -0,0 +1,376 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+)
+
+const (
+	defaultChunkSize int64 = 5 << 20  // 5 MiB
+	maxChunkSize     int64 = 32 << 20 // 32 MiB
+	maxChunkedUpload int64 = 2 << 30  // 2 GiB
+	uploadSessionTTL       = 24 * time.Hour
+	chunkRootDir           = "upload_chunks" // Kept outside the public uploads directory
+)
+
+type InitUploadInput struct {
+	Filename  string `json:"filename" binding:"required"`
+	TotalSize int64  `json:"total_size" binding:"required"`
+	ChunkSize int64  `json:"chunk_size"`
+	Checksum  string `json:"checksum"` // Optional hex SHA-256 of the whole file
+}
+
+func chunkDir(sessionID string) string {
+	return filepath.Join(chunkRootDir, sessionID)
+}
+
+func chunkPath(sessionID string, index int) string {
+	return filepath.Join(chunkDir(sessionID), fmt.Sprintf("%06d.part", index))
+}
+
+func newUploadSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// uploadSessionID returns the :id parameter if it has the form of the IDs
+// made by newUploadSessionID.
+func uploadSessionID(c *gin.Context) (string, bool) {
+	id := c.Param("id")
+	decoded, err := hex.DecodeString(id)
+	return id, err == nil && len(decoded) == 16 && id == strings.ToLower(id)
+}
+
+// receivedChunks lists the chunk indexes already stored for a session.
+func receivedChunks(sessionID string) []int {
+	entries, err := os.ReadDir(chunkDir(sessionID))
+	if err != nil {
+		return []int{}
+	}
+
+	indexes := []int{}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasSuffix(name, ".part") {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSuffix(name, ".part"))
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// findUploadSession loads an open session owned by the current user.
+func (ctl *UploadController) findUploadSession(c *gin.Context) (*models.UploadSession, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	id, ok := uploadSessionID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload session ID"})
+		return nil, false
+	}
+
+	session, err := ctl.media.FindSession(id, userID.(uint))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Upload session not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		ctl.discardUploadSession(session)
+		c.JSON(http.StatusGone, gin.H{"error": "Upload session expired"})
+		return nil, false
+	}
+
+	return session, true
+}
+
+func (ctl *UploadController) discardUploadSession(session *models.UploadSession) {
+	if err := os.RemoveAll(chunkDir(session.ID)); err != nil {
+		log.Printf("Failed to remove chunks for upload %s: %v", session.ID, err)
+	}
+	if err := ctl.media.DeleteSession(session.ID); err != nil {
+		log.Printf("Failed to delete upload session %s: %v", session.ID, err)
+	}
+}
+
+func (ctl *UploadController) InitUpload(c *gin.Context) {
+	var input InitUploadInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	extension, ok := validateUploadExtension(input.Filename)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Only jpg, jpeg, png, gif, pdf, mp3, m4a, mp4 are allowed"})
+		return
+	}
+
+	if input.TotalSize <= 0 || input.TotalSize > maxChunkedUpload {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid total_size"})
+		return
+	}
+
+	chunkSize := input.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	if chunkSize > maxChunkSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("chunk_size must not exceed %d bytes", maxChunkSize)})
+		return
+	}
+
+	checksum := strings.ToLower(strings.TrimSpace(input.Checksum))
+	if checksum != "" {
+		if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "checksum must be a hex encoded SHA-256 digest"})
+			return
+		}
+	}
+
+	id, err := newUploadSessionID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to create upload session"})
+		return
+	}
+
+	session := models.UploadSession{
+		ID:          id,
+		UserID:      userID.(uint),
+		Filename:    filepath.Base(input.Filename),
+		Extension:   extension,
+		TotalSize:   input.TotalSize,
+		ChunkSize:   chunkSize,
+		TotalChunks: int((input.TotalSize + chunkSize - 1) / chunkSize),
+		Checksum:    checksum,
+		ExpiresAt:   time.Now().Add(uploadSessionTTL),
+	}
+
+	if err := os.MkdirAll(chunkDir(session.ID), 0o755); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to create upload session"})
+		return
+	}
+
+	if err := ctl.media.CreateSession(&session); err != nil {
+		os.RemoveAll(chunkDir(session.ID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session": session, "received_chunks": []int{}})
+}
+
+func (ctl *UploadController) GetUploadStatus(c *gin.Context) {
+	session, ok := ctl.findUploadSession(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session": session, "received_chunks": receivedChunks(session.ID)})
+}
+
+// UploadChunk stores the raw request body as chunk :index. Re-sending a chunk
+// overwrites it, so clients can simply retry whatever did not arrive.
+func (ctl *UploadController) UploadChunk(c *gin.Context) {
+	session, ok := ctl.findUploadSession(c)
+	if !ok {
+		return
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 || index >= session.TotalChunks {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chunk index"})
+		return
+	}
+
+	expected := session.ChunkSize
+	if index == session.TotalChunks-1 {
+		expected = session.TotalSize - int64(index)*session.ChunkSize
+	}
+
+	tmp, err := os.CreateTemp(chunkDir(session.ID), "incoming-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to save chunk"})
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(c.Request.Body, expected+1))
+	tmp.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read chunk"})
+		return
+	}
+	if written != expected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Chunk %d must be %d bytes, got %d", index, expected, written)})
+		return
+	}
+
+	// Optional per-chunk integrity check
+	if sum := strings.ToLower(c.GetHeader("X-Chunk-Checksum")); sum != "" && sum != hex.EncodeToString(hash.Sum(nil)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Chunk checksum mismatch"})
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), chunkPath(session.ID, index)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to save chunk"})
+		return
+	}
+
+	// Keep active sessions alive
+	session.ExpiresAt = time.Now().Add(uploadSessionTTL)
+	if err := ctl.media.TouchSession(session.ID, session.ExpiresAt); err != nil {
+		log.Printf("Failed to extend upload session %s: %v", session.ID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"index": index, "received_chunks": receivedChunks(session.ID)})
+}
+
+func (ctl *UploadController) CompleteUpload(c *gin.Context) {
+	session, ok := ctl.findUploadSession(c)
+	if !ok {
+		return
+	}
+
+	received := receivedChunks(session.ID)
+	if len(received) != session.TotalChunks {
+		c.JSON(http.StatusConflict, gin.H{"error": "Upload is incomplete", "received_chunks": received})
+		return
+	}
+
+	filename := newUploadFilename(session.Extension)
+	dst := filepath.Join(UploadDir, filename)
+
+	out, err := os.Create(dst)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to save file"})
+		return
+	}
+
+	hash := sha256.New()
+	writer := io.MultiWriter(out, hash)
+	var size int64
+	for i := 0; i < session.TotalChunks; i++ {
+		part, err := os.Open(chunkPath(session.ID, i))
+		if err != nil {
+			out.Close()
+			os.Remove(dst)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to assemble file"})
+			return
+		}
+		n, err := io.Copy(writer, part)
+		part.Close()
+		if err != nil {
+			out.Close()
+			os.Remove(dst)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to assemble file"})
+			return
+		}
+		size += n
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to save file"})
+		return
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if size != session.TotalSize || (session.Checksum != "" && checksum != session.Checksum) {
+		os.Remove(dst)
+		ctl.discardUploadSession(session)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Checksum verification failed, please restart the upload"})
+		return
+	}
+
+	ctl.discardUploadSession(session)
+
+	media, err := recordMedia(ctl.media, session.UserID, filename, session.Filename, size, checksum)
+	if err != nil {
+		os.Remove(dst)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "File uploaded successfully",
+		"url":      media.URL,
+		"media_id": media.ID,
+		"checksum": checksum,
+		"size":     size,
+	})
+}
+
+func (ctl *UploadController) AbortUpload(c *gin.Context) {
+	session, ok := ctl.findUploadSession(c)
+	if !ok {
+		return
+	}
+
+	ctl.discardUploadSession(session)
+	c.JSON(http.StatusOK, gin.H{"message": "Upload aborted"})
+}
+
+// CleanupExpiredUploads removes abandoned upload sessions and their chunks.
+func (ctl *UploadController) CleanupExpiredUploads() {
+	sessions, err := ctl.media.ExpiredSessions(time.Now())
+	if err != nil {
+		log.Printf("Failed to query expired uploads: %v", err)
+		return
+	}
+
+	for i := range sessions {
+		ctl.discardUploadSession(&sessions[i])
+	}
+	if len(sessions) > 0 {
+		log.Printf("Removed %d expired upload sessions", len(sessions))
+	}
+}
+
+// StartJanitor runs CleanupExpiredUploads periodically in the background.
+func (ctl *UploadController) StartJanitor(interval time.Duration) {
+	go func() {
+		for {
+			ctl.CleanupExpiredUploads()
+			time.Sleep(interval)
+		}
+	}()
+}
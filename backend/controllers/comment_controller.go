@@ -1,20 +1,26 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/your-username/blog-backend/database"
-	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
 )
 
-type CreateCommentInput struct {
-	Content string `json:"content" binding:"required"`
+// CommentController serves the comment endpoints.
+type CommentController struct {
+	comments *services.CommentService
 }
 
-func CreateComment(c *gin.Context) {
-	var input CreateCommentInput
+func NewCommentController(comments *services.CommentService) *CommentController {
+	return &CommentController{comments: comments}
+}
+
+func (ctl *CommentController) CreateComment(c *gin.Context) {
+	var input services.CreateCommentInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -26,41 +32,34 @@ func CreateComment(c *gin.Context) {
 		return
 	}
 
-	// Verify article exists
-	var article models.Article
-	if err := database.DB.First(&article, articleID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
-		return
-	}
-
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	comment := models.Comment{
-		Content:   input.Content,
-		ArticleID: uint(articleID),
-		UserID:    userID.(uint),
-	}
-
-	if err := database.DB.Create(&comment).Error; err != nil {
+	comment, err := ctl.comments.Create(uint(articleID), userID.(uint), input)
+	switch {
+	case errors.Is(err, services.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+	case errors.Is(err, services.ErrRenderFailed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to render comment"})
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	default:
+		c.JSON(http.StatusOK, comment)
 	}
-
-	// Preload user info for response
-	database.DB.Preload("User").First(&comment, comment.ID)
-
-	c.JSON(http.StatusOK, comment)
 }
 
-func GetComments(c *gin.Context) {
-	articleID := c.Param("id")
-	var comments []models.Comment
+func (ctl *CommentController) GetComments(c *gin.Context) {
+	articleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
 
-	if err := database.DB.Where("article_id = ?", articleID).Preload("User").Find(&comments).Error; err != nil {
+	comments, err := ctl.comments.List(uint(articleID))
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -68,30 +67,28 @@ func GetComments(c *gin.Context) {
 	c.JSON(http.StatusOK, comments)
 }
 
-func DeleteComment(c *gin.Context) {
-	commentID := c.Param("id")
-	var comment models.Comment
-
-	if err := database.DB.First(&comment, commentID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
-		return
-	}
-
+func (ctl *CommentController) DeleteComment(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	if comment.UserID != userID.(uint) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not the author of this comment"})
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
 		return
 	}
 
-	if err := database.DB.Delete(&comment).Error; err != nil {
+	err := ctl.comments.Delete(id, userID.(uint))
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+	case errors.Is(err, services.ErrNotCommentOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not the author of this comment"})
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
 }
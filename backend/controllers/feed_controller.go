@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
+	"github.com/your-username/blog-backend/utils"
+)
+
+// feedContentTypes maps the file name at the end of a feed route to the
+// content type of the format it is served in.
+var feedContentTypes = map[string]string{
+	"feed.xml":  "application/rss+xml; charset=utf-8",
+	"atom.xml":  "application/atom+xml; charset=utf-8",
+	"feed.json": "application/feed+json; charset=utf-8",
+}
+
+// feedFormat returns the requested file name, or false for unknown formats.
+func feedFormat(c *gin.Context) (string, bool) {
+	name := path.Base(c.Request.URL.Path)
+	_, ok := feedContentTypes[name]
+	return name, ok
+}
+
+// fullContentRequested reports whether items carry the full article HTML.
+// The content query parameter (full or excerpt) overrides FEED_FULL_CONTENT.
+func fullContentRequested(c *gin.Context) bool {
+	switch c.Query("content") {
+	case "full":
+		return true
+	case "excerpt":
+		return false
+	}
+	return config.AppConfig.FeedFullContent
+}
+
+func feedLimit() int {
+	if config.AppConfig.FeedLimit <= 0 {
+		return 20
+	}
+	return config.AppConfig.FeedLimit
+}
+
+// writeFeed encodes feed in the requested format. Conditional requests are
+// answered with 304 when the ETag or Last-Modified date still match.
+// lastChange is when the records the feed is built from last changed, trashed
+// ones included, so that removing an item also moves Last-Modified.
+func writeFeed(c *gin.Context, feed utils.Feed, lastChange time.Time) {
+	format, ok := feedFormat(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown feed format"})
+		return
+	}
+
+	var body []byte
+	var err error
+	switch format {
+	case "atom.xml":
+		body, err = feed.Atom()
+	case "feed.json":
+		body, err = feed.JSON()
+	default:
+		body, err = feed.RSS()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	modified := feed.Updated
+	if lastChange.After(modified) {
+		modified = lastChange
+	}
+	if !modified.IsZero() {
+		c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, modified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, feedContentTypes[format], body)
+}
+
+// notModified applies the conditional request headers. If-None-Match takes
+// precedence over If-Modified-Since, as in RFC 9110.
+func notModified(c *gin.Context, etag string, updated time.Time) bool {
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := c.GetHeader("If-Modified-Since"); since != "" && !updated.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !updated.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// FeedController serves the RSS, Atom and JSON feeds.
+type FeedController struct {
+	articles *services.ArticleService
+	taxonomy *services.TaxonomyService
+	comments *services.CommentService
+	users    *services.UserService
+}
+
+func NewFeedController(articles *services.ArticleService, taxonomy *services.TaxonomyService, comments *services.CommentService, users *services.UserService) *FeedController {
+	return &FeedController{articles: articles, taxonomy: taxonomy, comments: comments, users: users}
+}
+
+// feedURL is the address of the feed at feedPath in the requested format,
+// used as its ID and self link.
+func feedURL(c *gin.Context, feedPath string) string {
+	format, _ := feedFormat(c)
+	return apiURL() + feedPath + "/" + format
+}
+
+// articleFeed loads the newest published articles matching opts and turns
+// them into a feed served at feedPath.
+func (ctl *FeedController) articleFeed(c *gin.Context, opts services.ArticleListOptions, feedPath, title, description, link string) (utils.Feed, error) {
+	opts.Newest = true
+	opts.Limit = feedLimit()
+	opts.WithAuthor, opts.WithCategory, opts.WithTags = true, true, true
+	articles, err := ctl.articles.List(opts)
+	if err != nil {
+		return utils.Feed{}, err
+	}
+
+	feed := utils.Feed{
+		Title:       title,
+		Description: description,
+		Link:        link,
+		FeedURL:     feedURL(c, feedPath),
+		Items:       []utils.FeedItem{},
+	}
+
+	full := fullContentRequested(c)
+	for i := range articles {
+		article := &articles[i]
+
+		item := utils.FeedItem{
+			ID:        articleURL(article.ID),
+			URL:       articleURL(article.ID),
+			Title:     article.Title,
+			Summary:   article.Excerpt,
+			Author:    article.Author.Username,
+			Published: article.CreatedAt,
+			Updated:   article.UpdatedAt,
+		}
+		if full {
+			item.ContentHTML = article.ContentHTML
+		}
+		if article.Category.ID != 0 {
+			item.Categories = append(item.Categories, article.Category.Name)
+		}
+		for _, tag := range article.Tags {
+			item.Categories = append(item.Categories, tag.Name)
+		}
+
+		if article.UpdatedAt.After(feed.Updated) {
+			feed.Updated = article.UpdatedAt
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed, nil
+}
+
+func (ctl *FeedController) serveArticleFeed(c *gin.Context, opts services.ArticleListOptions, feedPath, title, description, link string) {
+	feed, err := ctl.articleFeed(c, opts, feedPath, title, description, link)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	lastChange, err := ctl.articles.LastChange()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeFeed(c, feed, lastChange)
+}
+
+// GetFeed serves the site wide feed of the newest articles.
+func (ctl *FeedController) GetFeed(c *gin.Context) {
+	ctl.serveArticleFeed(c, services.ArticleListOptions{}, "",
+		config.AppConfig.SiteTitle, config.AppConfig.SiteDescription, homeURL())
+}
+
+// GetCategoryFeed serves the articles of a category, including its
+// subcategories. The category is looked up by ID or slug.
+func (ctl *FeedController) GetCategoryFeed(c *gin.Context) {
+	category, err := ctl.taxonomy.GetCategory(c.Param("id"))
+	if err != nil {
+		taxonomyError(c, err, "Category")
+		return
+	}
+
+	ctl.serveArticleFeed(c, services.ArticleListOptions{CategoryID: category.ID, IncludeDescendants: true}, categoryFeedPath(*category),
+		config.AppConfig.SiteTitle+" - "+category.Name, category.Description, categoryURL(*category))
+}
+
+// GetTagFeed serves the articles with a tag, looked up by ID or slug.
+func (ctl *FeedController) GetTagFeed(c *gin.Context) {
+	tag, err := ctl.taxonomy.GetTag(c.Param("id"))
+	if err != nil {
+		taxonomyError(c, err, "Tag")
+		return
+	}
+
+	opts := services.ArticleListOptions{ArticleQuery: repository.ArticleQuery{TagID: tag.ID}}
+	ctl.serveArticleFeed(c, opts, tagFeedPath(*tag), config.AppConfig.SiteTitle+" - #"+tag.Name, tag.Description, tagURL(*tag))
+}
+
+// GetAuthorFeed serves the articles of an author, looked up by ID or username.
+func (ctl *FeedController) GetAuthorFeed(c *gin.Context) {
+	author, err := ctl.users.Author(c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := services.ArticleListOptions{ArticleQuery: repository.ArticleQuery{AuthorID: author.ID}}
+	ctl.serveArticleFeed(c, opts, authorFeedPath(*author), config.AppConfig.SiteTitle+" - "+author.Username, author.Bio, authorURL(*author))
+}
+
+// GetCommentFeed serves the newest comments on an article.
+func (ctl *FeedController) GetCommentFeed(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	article, err := ctl.articles.Get(id, false)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	comments, err := ctl.comments.Recent(article.ID, feedLimit())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	lastChange, err := ctl.comments.LastChange(article.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed := utils.Feed{
+		Title:   "Comments on " + article.Title,
+		Link:    articleURL(article.ID),
+		FeedURL: feedURL(c, commentFeedPath(article.ID)),
+		Items:   []utils.FeedItem{},
+	}
+
+	for _, comment := range comments {
+		link := fmt.Sprintf("%s#comment-%d", articleURL(article.ID), comment.ID)
+		feed.Items = append(feed.Items, utils.FeedItem{
+			ID:          link,
+			URL:         link,
+			Title:       "Comment by " + comment.User.Username,
+			Summary:     comment.Content,
+			ContentHTML: comment.ContentHTML,
+			Author:      comment.User.Username,
+			Published:   comment.CreatedAt,
+			Updated:     comment.UpdatedAt,
+		})
+		if comment.UpdatedAt.After(feed.Updated) {
+			feed.Updated = comment.UpdatedAt
+		}
+	}
+
+	writeFeed(c, feed, lastChange)
+}
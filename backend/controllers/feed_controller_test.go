@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/utils"
+)
+
+func serveFeed(t *testing.T, feed utils.Feed, lastChange time.Time, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/feed.xml", nil)
+	for k, v := range header {
+		c.Request.Header.Set(k, v)
+	}
+	writeFeed(c, feed, lastChange)
+	c.Writer.WriteHeaderNow()
+	return w
+}
+
+func TestWriteFeedConditionalRequests(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	feed := utils.Feed{Title: "Blog", Updated: updated, Items: []utils.FeedItem{{ID: "1", Title: "One", Published: updated, Updated: updated}}}
+	// An article that was deleted after the newest item was last updated.
+	deleted := updated.Add(time.Hour)
+
+	etag := serveFeed(t, feed, deleted, nil).Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{"unconditional", nil, http.StatusOK},
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag in a list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"stale etag wins over the date", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": deleted.Format(http.TimeFormat)}, http.StatusOK},
+		{"modified since the deletion", map[string]string{"If-Modified-Since": deleted.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"last seen before the deletion", map[string]string{"If-Modified-Since": updated.Format(http.TimeFormat)}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveFeed(t, feed, deleted, tt.header)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if got := w.Header().Get("Last-Modified"); got != deleted.Format(http.TimeFormat) {
+				t.Errorf("Last-Modified = %q, want the time of the deletion", got)
+			}
+		})
+	}
+}
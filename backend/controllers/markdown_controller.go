@@ -0,0 +1,606 @@
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
+	"github.com/your-username/blog-backend/utils"
+	"gorm.io/gorm"
+)
+
+// Markdown import and export. Articles are matched by slug and remember the
+// file they were imported from, so importing the same files again updates
+// the articles instead of duplicating them. Other articles are never
+// overwritten: a file whose slug they use fails to import.
+
+// MarkdownImportResult reports what happened to one imported file.
+type MarkdownImportResult struct {
+	File      string   `json:"file"`
+	Status    string   `json:"status"` // created, updated, unchanged or failed
+	ArticleID uint     `json:"article_id,omitempty"`
+	Slug      string   `json:"slug,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// MarkdownExportFile is one file of a Markdown export.
+type MarkdownExportFile struct {
+	Path string // Slash separated
+	Data []byte
+}
+
+// markdownImagePattern and htmlImagePattern capture the image URL of
+// ![alt](url "title") and <img src="url"> references.
+var (
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?`)
+	htmlImagePattern     = regexp.MustCompile(`<img\b[^>]*?\bsrc\s*=\s*["']([^"']+)["']`)
+	jekyllDatePrefix     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-`)
+)
+
+// exportImageDir is where an export puts the uploads its articles reference.
+const exportImageDir = "images"
+
+// Uncompressed size limits of an uploaded zip archive and of each file in
+// it. archive/zip fails reading an entry that is larger than its header says.
+const (
+	markdownMaxArchiveSize = 1 << 30
+	markdownMaxEntrySize   = 100 << 20
+)
+
+// MarkdownController serves the Markdown import and export.
+type MarkdownController struct {
+	db      *gorm.DB
+	related *services.RelatedService
+}
+
+func NewMarkdownController(db *gorm.DB, related *services.RelatedService) *MarkdownController {
+	return &MarkdownController{db: db, related: related}
+}
+
+type markdownImporter struct {
+	db       *gorm.DB
+	fsys     fs.FS // Nil when importing a single file without its images
+	authorID uint
+	assets   map[string]string // Asset path -> uploaded URL
+}
+
+// MarkdownFiles lists the Markdown documents in fsys. Hidden directories,
+// Hugo section pages (_index.md) and READMEs are skipped.
+func MarkdownFiles(fsys fs.FS) ([]string, error) {
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		base := d.Name()
+		if d.IsDir() {
+			if name != "." && (strings.HasPrefix(base, ".") || base == "__MACOSX") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(path.Ext(base))
+		if ext != ".md" && ext != ".markdown" {
+			return nil
+		}
+		if strings.HasPrefix(base, ".") || strings.EqualFold(base, "_index.md") || strings.EqualFold(base, "readme.md") {
+			return nil
+		}
+		names = append(names, name)
+		return nil
+	})
+	sort.Strings(names)
+	return names, err
+}
+
+// invalidateImportedArticles drops the cached contexts the articles an
+// import created or updated can change.
+func (ctl *MarkdownController) invalidateImportedArticles(results []MarkdownImportResult) {
+	var ids []uint
+	for _, r := range results {
+		if r.Status == "created" || r.Status == "updated" {
+			ids = append(ids, r.ArticleID)
+		}
+	}
+	ctl.related.Invalidate(ids...)
+}
+
+// ImportMarkdownFS imports every Markdown file in fsys into db as an article
+// written by authorID. Local images are uploaded and their references
+// rewritten.
+func ImportMarkdownFS(db *gorm.DB, fsys fs.FS, authorID uint) ([]MarkdownImportResult, error) {
+	names, err := MarkdownFiles(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &markdownImporter{db: db, fsys: fsys, authorID: authorID, assets: map[string]string{}}
+	results := make([]MarkdownImportResult, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			results = append(results, MarkdownImportResult{File: name, Status: "failed", Error: err.Error()})
+			continue
+		}
+		results = append(results, imp.importDocument(name, data))
+	}
+	return results, nil
+}
+
+// CountImportResults returns the number of results per status.
+func CountImportResults(results []MarkdownImportResult) map[string]int {
+	counts := map[string]int{"created": 0, "updated": 0, "unchanged": 0, "failed": 0}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return counts
+}
+
+func (imp *markdownImporter) importDocument(name string, data []byte) MarkdownImportResult {
+	result := MarkdownImportResult{File: name}
+	fail := func(err error) MarkdownImportResult {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	fm, body, err := utils.ParseFrontMatter(data)
+	if err != nil {
+		return fail(err)
+	}
+
+	title := fm.Title
+	if title == "" {
+		title = documentStem(name)
+	}
+	// Purely numeric candidates are skipped, routes would read them as IDs
+	var slug string
+	for _, candidate := range []string{fm.Slug, jekyllDatePrefix.ReplaceAllString(documentStem(name), ""), title} {
+		if slug = utils.Slugify(candidate); slug != "" && !utils.NumericSlug(slug) {
+			break
+		}
+	}
+	if slug == "" || utils.NumericSlug(slug) {
+		return fail(errors.New("cannot derive a slug from the file name or title"))
+	}
+	result.Slug = slug
+	source := clip(name, 255)
+
+	draft := fm.Draft
+	for _, dir := range strings.Split(path.Dir(name), "/") {
+		if dir == "_drafts" {
+			draft = true // Jekyll
+		}
+	}
+
+	content, warnings, err := imp.rewriteImages(name, strings.TrimRight(body, " \t\n"))
+	if err != nil {
+		return fail(err)
+	}
+	result.Warnings = warnings
+	if len(fm.Categories) > 1 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("only the first category %q is used", fm.Categories[0]))
+	}
+
+	err = imp.db.Transaction(func(tx *gorm.DB) error {
+		var categoryID *uint
+		if len(fm.Categories) > 0 {
+			category, err := findOrCreateCategory(tx, fm.Categories[0])
+			if err != nil {
+				return err
+			}
+			categoryID = &category.ID
+		}
+
+		tags, err := resolveTags(tx, fm.Tags)
+		if err != nil {
+			return err
+		}
+
+		// Slugs stay reserved while an article is in the trash
+		var article models.Article
+		err = tx.Unscoped().Preload("Tags").Where("slug = ?", slug).First(&article).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			article = models.Article{Title: title, Slug: slug, AuthorID: imp.authorID, CategoryID: categoryID, Tags: tags, Draft: draft, ImportSource: source}
+			if !fm.Date.IsZero() {
+				article.CreatedAt = fm.Date
+				article.UpdatedAt = fm.Date
+			}
+			if err := services.SetArticleContent(&article, content); err != nil {
+				return err
+			}
+			if err := tx.Create(&article).Error; err != nil {
+				return err
+			}
+			result.Status = "created"
+		case err != nil:
+			return err
+		case article.DeletedAt.Valid:
+			return fmt.Errorf("slug %q belongs to an article in the trash", slug)
+		case article.ImportSource != source:
+			return fmt.Errorf("slug %q is used by an article that was not imported from this file", slug)
+		case importUnchanged(article, title, content, draft, categoryID, tags, fm):
+			result.Status = "unchanged"
+		default:
+			article.Title = title
+			article.Draft = draft
+			article.CategoryID = categoryID
+			if !fm.Date.IsZero() {
+				article.CreatedAt = fm.Date
+			}
+			if err := services.SetArticleContent(&article, content); err != nil {
+				return err
+			}
+			if err := tx.Model(&article).Association("Tags").Replace(tags); err != nil {
+				return err
+			}
+			if err := tx.Omit("Tags").Save(&article).Error; err != nil {
+				return err
+			}
+			result.Status = "updated"
+		}
+		result.ArticleID = article.ID
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+	return result
+}
+
+// importUnchanged reports whether article already matches the imported file.
+func importUnchanged(article models.Article, title, content string, draft bool, categoryID *uint, tags []models.Tag, fm utils.FrontMatter) bool {
+	if article.Title != title || article.Content != content || article.Draft != draft {
+		return false
+	}
+	if (article.CategoryID == nil) != (categoryID == nil) || (categoryID != nil && *article.CategoryID != *categoryID) {
+		return false
+	}
+	if !fm.Date.IsZero() && !article.CreatedAt.Equal(fm.Date) {
+		return false
+	}
+	if len(article.Tags) != len(tags) {
+		return false
+	}
+	current := map[uint]bool{}
+	for _, tag := range article.Tags {
+		current[tag.ID] = true
+	}
+	for _, tag := range tags {
+		if !current[tag.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// findOrCreateCategory looks a category up by name, ignoring case.
+func findOrCreateCategory(tx *gorm.DB, name string) (models.Category, error) {
+	name = strings.TrimSpace(name)
+	var category models.Category
+	err := tx.Where("LOWER(name) = LOWER(?)", name).First(&category).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return category, err
+	}
+
+	// A deleted category of that name comes back out of the trash
+	trashedID, err := restoreTrashedCategory(tx, name)
+	if err != nil {
+		return category, err
+	}
+	if trashedID != 0 {
+		return category, tx.First(&category, trashedID).Error
+	}
+
+	category = models.Category{Name: name}
+	if category.Slug, err = database.UniqueSlug(tx, &models.Category{}, name, 0); err != nil {
+		return category, err
+	}
+	return category, tx.Create(&category).Error
+}
+
+// documentStem is the file name without extension, or the directory name for
+// Hugo page bundles (post/index.md).
+func documentStem(name string) string {
+	base := path.Base(name)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	if strings.EqualFold(stem, "index") && path.Dir(name) != "." {
+		return path.Base(path.Dir(name))
+	}
+	return stem
+}
+
+// rewriteImages uploads the local images content refers to and points the
+// references at the uploaded copies. Images that cannot be found or have an
+// unsupported type are left alone and reported as warnings.
+func (imp *markdownImporter) rewriteImages(name, content string) (string, []string, error) {
+	var warnings []string
+	var out strings.Builder
+	last := 0
+
+	var matches [][]int
+	matches = append(matches, markdownImagePattern.FindAllStringSubmatchIndex(content, -1)...)
+	matches = append(matches, htmlImagePattern.FindAllStringSubmatchIndex(content, -1)...)
+	sort.Slice(matches, func(i, j int) bool { return matches[i][2] < matches[j][2] })
+
+	for _, m := range matches {
+		start, end := m[2], m[3]
+		if start < last {
+			continue // Overlapping match
+		}
+		ref := content[start:end]
+
+		asset, ok := imp.findAsset(name, ref)
+		if !ok {
+			if imp.fsys != nil && isLocalReference(ref) {
+				warnings = append(warnings, "image not found: "+ref)
+			}
+			continue
+		}
+
+		uploaded, err := imp.uploadAsset(asset)
+		if errors.Is(err, errUnsupportedUpload) {
+			warnings = append(warnings, "unsupported image type: "+ref)
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		out.WriteString(content[last:start])
+		out.WriteString(uploaded)
+		last = end
+	}
+	out.WriteString(content[last:])
+	return out.String(), warnings, nil
+}
+
+func isLocalReference(ref string) bool {
+	lower := strings.ToLower(ref)
+	return !strings.Contains(lower, "://") && !strings.HasPrefix(lower, "//") &&
+		!strings.HasPrefix(lower, "data:") && !strings.HasPrefix(lower, "#")
+}
+
+// findAsset resolves ref against the document's directory. Root relative
+// references are tried against every parent directory and its static/
+// folder, which is where Hugo keeps site-wide assets; Hexo asset folders
+// named after the post are tried as well.
+func (imp *markdownImporter) findAsset(name, ref string) (string, bool) {
+	if imp.fsys == nil || !isLocalReference(ref) {
+		return "", false
+	}
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	if ref == "" {
+		return "", false
+	}
+
+	dir := path.Dir(name)
+	var candidates []string
+	if strings.HasPrefix(ref, "/") {
+		for d := dir; ; d = path.Dir(d) {
+			candidates = append(candidates, path.Join(d, ref), path.Join(d, "static", ref))
+			if d == "." {
+				break
+			}
+		}
+	} else {
+		candidates = append(candidates, path.Join(dir, ref), path.Join(dir, documentStem(name), ref))
+	}
+
+	for _, candidate := range candidates {
+		if !fs.ValidPath(candidate) {
+			continue
+		}
+		if info, err := fs.Stat(imp.fsys, candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// uploadAsset stores an image in the upload directory, reusing an existing
+// upload with the same content.
+func (imp *markdownImporter) uploadAsset(asset string) (string, error) {
+	if uploaded, ok := imp.assets[asset]; ok {
+		return uploaded, nil
+	}
+	if _, ok := validateUploadExtension(asset); !ok {
+		return "", errUnsupportedUpload
+	}
+
+	data, err := fs.ReadFile(imp.fsys, asset)
+	if err != nil {
+		return "", err
+	}
+	media, _, err := storeUpload(repository.NewStore(imp.db).Media(), imp.authorID, path.Base(asset), data)
+	if err != nil {
+		return "", err
+	}
+
+	imp.assets[asset] = media.URL
+	return media.URL, nil
+}
+
+// ExportMarkdownFiles writes every article, drafts included, as a Markdown
+// file with YAML front matter. Uploads the articles link to are included
+// under images/ and referenced relatively, so the export can be imported
+// into another blog.
+func ExportMarkdownFiles(tx *gorm.DB) ([]MarkdownExportFile, error) {
+	var articles []models.Article
+	if err := tx.Preload("Category").Preload("Tags").Order("id ASC").Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	files := make([]MarkdownExportFile, 0, len(articles))
+	images := map[string]bool{}
+	for _, article := range articles {
+		content := UploadRefPattern.ReplaceAllStringFunc(article.Content, func(ref string) string {
+			name := UploadRefPattern.FindStringSubmatch(ref)[2]
+			if info, err := os.Stat(filepath.Join(UploadDir, name)); err != nil || info.IsDir() {
+				return ref // Not one of ours
+			}
+			images[name] = true
+			return exportImageDir + "/" + name
+		})
+
+		fm := utils.FrontMatter{Title: article.Title, Date: article.CreatedAt, Slug: article.Slug, Draft: article.Draft}
+		for _, tag := range article.Tags {
+			fm.Tags = append(fm.Tags, tag.Name)
+		}
+		if article.CategoryID != nil {
+			fm.Categories = []string{article.Category.Name}
+		}
+
+		data, err := utils.FormatFrontMatter(fm, content)
+		if err != nil {
+			return nil, err
+		}
+
+		filename := article.Slug
+		if filename == "" {
+			filename = fmt.Sprintf("article-%d", article.ID)
+		}
+		files = append(files, MarkdownExportFile{Path: filename + ".md", Data: data})
+	}
+
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(UploadDir, name))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, MarkdownExportFile{Path: exportImageDir + "/" + name, Data: data})
+	}
+	return files, nil
+}
+
+// ImportMarkdown imports a single Markdown file or a zip archive of Markdown
+// files and their images, uploaded as the "file" form field.
+func (ctl *MarkdownController) ImportMarkdown(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file is received"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	var results []MarkdownImportResult
+	switch strings.ToLower(filepath.Ext(header.Filename)) {
+	case ".zip":
+		archive, err := zip.NewReader(file, header.Size)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid zip archive"})
+			return
+		}
+		if err := checkArchiveSize(archive); err != nil {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		if results, err = ImportMarkdownFS(ctl.db, archive, userID.(uint)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	case ".md", ".markdown":
+		if header.Size > markdownMaxEntrySize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("the file is larger than %d MiB", markdownMaxEntrySize>>20)})
+			return
+		}
+		data, err := io.ReadAll(file)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		imp := &markdownImporter{db: ctl.db, authorID: userID.(uint), assets: map[string]string{}}
+		results = []MarkdownImportResult{imp.importDocument(filepath.Base(header.Filename), data)}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Only .md, .markdown and .zip are allowed"})
+		return
+	}
+
+	ctl.invalidateImportedArticles(results)
+	c.JSON(http.StatusOK, gin.H{"results": results, "summary": CountImportResults(results)})
+}
+
+// checkArchiveSize rejects archives that would unpack to more than
+// markdownMaxArchiveSize, or with a file larger than markdownMaxEntrySize.
+func checkArchiveSize(archive *zip.Reader) error {
+	var total uint64
+	for _, f := range archive.File {
+		if f.UncompressedSize64 > markdownMaxEntrySize {
+			return fmt.Errorf("%s is larger than %d MiB", f.Name, markdownMaxEntrySize>>20)
+		}
+		total += f.UncompressedSize64
+		if total > markdownMaxArchiveSize {
+			return fmt.Errorf("the archive unpacks to more than %d MiB", markdownMaxArchiveSize>>20)
+		}
+	}
+	return nil
+}
+
+// ExportMarkdown downloads all articles as a zip archive of Markdown files.
+func (ctl *MarkdownController) ExportMarkdown(c *gin.Context) {
+	files, err := ExportMarkdownFiles(ctl.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.Path)
+		if err == nil {
+			_, err = w.Write(file.Data)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="articles.zip"`)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
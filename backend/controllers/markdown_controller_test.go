@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/glebarez/sqlite"
+	"github.com/your-username/blog-backend/migrations"
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB returns a migrated SQLite database with one user and makes the
+// test's temporary directory the working directory, so uploads land there.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	db, err := gorm.Open(sqlite.Open(filepath.Join(dir, "blog.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.User{Username: "admin", Email: "admin@example.com", Password: "x"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func findArticle(t *testing.T, db *gorm.DB, slug string) models.Article {
+	t.Helper()
+	var article models.Article
+	if err := db.Unscoped().Preload("Category").Preload("Tags").Where("slug = ?", slug).First(&article).Error; err != nil {
+		t.Fatalf("article %s: %v", slug, err)
+	}
+	return article
+}
+
+func TestMarkdownFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":                  {Data: []byte("# Site")},
+		"content/_index.md":          {Data: []byte("section")},
+		"content/post/index.md":      {Data: []byte("bundle")},
+		"content/post/notes.txt":     {Data: []byte("not markdown")},
+		"_posts/2024-01-02-hello.md": {Data: []byte("hello")},
+		"_drafts/idea.markdown":      {Data: []byte("idea")},
+		".git/HEAD.md":               {Data: []byte("hidden")},
+		"__MACOSX/._hello.md":        {Data: []byte("resource fork")},
+	}
+	got, err := MarkdownFiles(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"_drafts/idea.markdown", "_posts/2024-01-02-hello.md", "content/post/index.md"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestImportMarkdownFS(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		status   string
+		slug     string
+		warnings int
+		check    func(t *testing.T, article models.Article)
+	}{
+		{
+			name: "front matter",
+			files: fstest.MapFS{"hello.md": {Data: []byte(
+				"---\ntitle: Hello World\ndate: 2024-01-02T03:04:05Z\ntags: [Go, web]\ncategories: [Notes, Misc]\n---\nSome *text*.\n",
+			)}},
+			status:   "created",
+			slug:     "hello",
+			warnings: 1, // Only the first category is used
+			check: func(t *testing.T, a models.Article) {
+				if a.Title != "Hello World" || a.Draft || a.Category.Name != "Notes" || len(a.Tags) != 2 {
+					t.Errorf("article = %q draft=%v category=%q tags=%v", a.Title, a.Draft, a.Category.Name, a.Tags)
+				}
+				if a.CreatedAt.UTC().Format("2006-01-02T15:04:05") != "2024-01-02T03:04:05" {
+					t.Errorf("created at %v, want the front matter date", a.CreatedAt)
+				}
+				if !strings.Contains(a.ContentHTML, "<em>text</em>") {
+					t.Errorf("content is not rendered: %q", a.ContentHTML)
+				}
+			},
+		},
+		{
+			name:   "jekyll draft",
+			files:  fstest.MapFS{"_drafts/2024-05-06-an-idea.md": {Data: []byte("Just an idea")}},
+			status: "created",
+			slug:   "an-idea",
+			check: func(t *testing.T, a models.Article) {
+				if !a.Draft || a.Title != "2024-05-06-an-idea" {
+					t.Errorf("title %q draft=%v, want the file name as a draft", a.Title, a.Draft)
+				}
+			},
+		},
+		{
+			name: "hugo bundle with images",
+			files: fstest.MapFS{
+				"post/trip/index.md": {Data: []byte("+++\ntitle = \"Trip\"\n+++\n![map](map.png) ![gone](missing.png) ![remote](https://example.com/a.png)\n")},
+				"post/trip/map.png":  {Data: []byte("png")},
+			},
+			status:   "created",
+			slug:     "trip",
+			warnings: 1, // missing.png
+			check: func(t *testing.T, a models.Article) {
+				if strings.Contains(a.Content, "(map.png)") || !strings.Contains(a.Content, "/uploads/") {
+					t.Errorf("local image is not uploaded: %q", a.Content)
+				}
+				if !strings.Contains(a.Content, "(missing.png)") || !strings.Contains(a.Content, "https://example.com/a.png") {
+					t.Errorf("other images changed: %q", a.Content)
+				}
+			},
+		},
+		{
+			name:   "unclosed front matter",
+			files:  fstest.MapFS{"broken.md": {Data: []byte("---\ntitle: Broken\n")}},
+			status: "failed",
+		},
+		{
+			name:   "numeric file name",
+			files:  fstest.MapFS{"2024.md": {Data: []byte("---\ntitle: Year in review\n---\nYear")}},
+			status: "created",
+			slug:   "year-in-review",
+		},
+		{
+			name:   "numeric file name and title",
+			files:  fstest.MapFS{"2024.md": {Data: []byte("---\ntitle: \"2024\"\n---\nYear")}},
+			status: "failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			results, err := ImportMarkdownFS(db, tt.files, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("results = %+v, want one", results)
+			}
+			r := results[0]
+			if r.Status != tt.status || r.Slug != tt.slug || len(r.Warnings) != tt.warnings {
+				t.Fatalf("result = %+v, want status %s, slug %q and %d warnings", r, tt.status, tt.slug, tt.warnings)
+			}
+			if tt.check != nil {
+				tt.check(t, findArticle(t, db, tt.slug))
+			}
+		})
+	}
+}
+
+func TestImportMarkdownFSAgain(t *testing.T) {
+	db := openTestDB(t)
+	files := fstest.MapFS{
+		"a.md": {Data: []byte("---\ntitle: A\ntags: [x]\n---\nFirst")},
+		"b.md": {Data: []byte("---\ntitle: B\n---\nSecond")},
+	}
+	if _, err := ImportMarkdownFS(db, files, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Articles not imported from a file keep their slug
+	if err := db.Create(&models.Article{Title: "Manual", Slug: "c", Content: "x", AuthorID: 1}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	files["b.md"] = &fstest.MapFile{Data: []byte("---\ntitle: B\n---\nSecond, edited")}
+	files["c.md"] = &fstest.MapFile{Data: []byte("Clash")}
+	results, err := ImportMarkdownFS(db, files, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"a.md": "unchanged", "b.md": "updated", "c.md": "failed"}
+	for _, r := range results {
+		if r.Status != want[r.File] {
+			t.Errorf("%s: %s (%s), want %s", r.File, r.Status, r.Error, want[r.File])
+		}
+	}
+	if counts := CountImportResults(results); counts["unchanged"] != 1 || counts["updated"] != 1 || counts["failed"] != 1 {
+		t.Errorf("counts = %v", counts)
+	}
+	if b := findArticle(t, db, "b"); b.Content != "Second, edited" {
+		t.Errorf("b = %q, want the edited content", b.Content)
+	}
+	if c := findArticle(t, db, "c"); c.Content != "x" {
+		t.Errorf("the manual article was overwritten: %q", c.Content)
+	}
+	var count int64
+	db.Model(&models.Article{}).Count(&count)
+	if count != 3 {
+		t.Errorf("%d articles, want 3", count)
+	}
+}
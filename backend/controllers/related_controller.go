@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
+)
+
+// RelatedController serves the navigation around single articles.
+type RelatedController struct {
+	related *services.RelatedService
+}
+
+func NewRelatedController(related *services.RelatedService) *RelatedController {
+	return &RelatedController{related: related}
+}
+
+// GetArticleContext returns the previous/next articles and a ranked list of
+// related articles for :id. Results are cached until a related article
+// changes.
+func (ctl *RelatedController) GetArticleContext(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit <= 0 || limit > services.MaxRelatedArticles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 20"})
+		return
+	}
+
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid article ID"})
+		return
+	}
+
+	result, err := ctl.related.Context(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(result.Related) > limit {
+		result.Related = result.Related[:limit]
+	}
+	c.JSON(http.StatusOK, result)
+}
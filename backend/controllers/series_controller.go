@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
+)
+
+// SeriesController serves the series endpoints.
+type SeriesController struct {
+	series   *services.SeriesService
+	articles *services.ArticleService
+}
+
+func NewSeriesController(series *services.SeriesService, articles *services.ArticleService) *SeriesController {
+	return &SeriesController{series: series, articles: articles}
+}
+
+// seriesError maps the errors of SeriesService to responses.
+func seriesError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+	case errors.Is(err, services.ErrTitleRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+	case errors.Is(err, services.ErrSlugUnavailable), errors.Is(err, services.ErrUnknownArticles):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (ctl *SeriesController) CreateSeries(c *gin.Context) {
+	var input services.SeriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	series, err := ctl.series.Create(userID.(uint), input)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+func (ctl *SeriesController) GetSeriesList(c *gin.Context) {
+	result, err := ctl.series.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSeries returns a series, looked up by ID or slug, with its articles in
+// reading order.
+func (ctl *SeriesController) GetSeries(c *gin.Context) {
+	series, err := ctl.series.Get(c.Param("id"))
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	_, signedIn := c.Get("user_id")
+	articles, err := ctl.articles.List(services.ArticleListOptions{ArticleQuery: repository.ArticleQuery{
+		IncludeDrafts: signedIn,
+		SeriesID:      series.ID,
+		Columns:       articleListColumnNames(defaultArticleListFields),
+		WithAuthor:    true,
+		WithCategory:  true,
+		WithTags:      true,
+	}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]ArticleListItem, 0, len(articles))
+	for _, a := range articles {
+		items = append(items, newArticleListItem(a))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"series": series, "articles": items})
+}
+
+func (ctl *SeriesController) UpdateSeries(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+
+	var input services.SeriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := ctl.series.Update(id, input)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// DeleteSeries removes a series. Its articles are kept and detached.
+func (ctl *SeriesController) DeleteSeries(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+
+	if err := ctl.series.Delete(id); err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series deleted successfully"})
+}
+
+// ReorderSeriesArticles sets the articles of a series to exactly article_ids,
+// in that order. Articles left out are removed from the series.
+func (ctl *SeriesController) ReorderSeriesArticles(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+
+	var input services.ReorderSeriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ctl.series.Reorder(id, input.ArticleIDs); err != nil {
+		seriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series order updated", "article_ids": input.ArticleIDs})
+}
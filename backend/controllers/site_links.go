@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/models"
+)
+
+// Public page URLs. By default they mirror the routes of the Vue app, which
+// lists categories and tags on the timeline and has a single author page.
+// A static export cannot serve query strings, so it switches to one
+// directory per page with UseStaticLinks.
+
+var staticLinks bool
+
+// UseStaticLinks makes every generated page URL point into the directory
+// layout written by the static site export.
+func UseStaticLinks() {
+	staticLinks = true
+}
+
+func siteURL() string {
+	return strings.TrimRight(config.AppConfig.SiteURL, "/")
+}
+
+func homeURL() string {
+	return siteURL() + "/"
+}
+
+func timelineURL() string {
+	if staticLinks {
+		return siteURL() + "/timeline/"
+	}
+	return siteURL() + "/timeline"
+}
+
+func articleURL(id uint) string {
+	if staticLinks {
+		return fmt.Sprintf("%s/articles/%d/", siteURL(), id)
+	}
+	return fmt.Sprintf("%s/articles/%d", siteURL(), id)
+}
+
+func categoryURL(category models.Category) string {
+	if staticLinks {
+		return siteURL() + "/categories/" + url.PathEscape(category.Slug) + "/"
+	}
+	return fmt.Sprintf("%s/timeline?category_id=%d", siteURL(), category.ID)
+}
+
+func tagURL(tag models.Tag) string {
+	if staticLinks {
+		return siteURL() + "/tags/" + url.PathEscape(tag.Slug) + "/"
+	}
+	return fmt.Sprintf("%s/timeline?tag_id=%d", siteURL(), tag.ID)
+}
+
+func authorURL(author models.User) string {
+	if staticLinks {
+		return siteURL() + "/authors/" + url.PathEscape(author.Username) + "/"
+	}
+	return siteURL() + "/about"
+}
+
+// Feed paths, without the format. Taxonomies and authors use their slug or
+// username so a feed has one address whether it was requested by ID or not.
+
+func categoryFeedPath(category models.Category) string {
+	return "/feeds/categories/" + url.PathEscape(category.Slug)
+}
+
+func tagFeedPath(tag models.Tag) string {
+	return "/feeds/tags/" + url.PathEscape(tag.Slug)
+}
+
+func authorFeedPath(author models.User) string {
+	return "/feeds/authors/" + url.PathEscape(author.Username)
+}
+
+func commentFeedPath(articleID uint) string {
+	return fmt.Sprintf("/feeds/articles/%d/comments", articleID)
+}
+
+// apiURL returns the public address of the backend, for links to resources
+// it serves itself such as feeds and sitemaps. It comes from API_URL, or
+// SITE_URL when the backend shares the frontend's domain, rather than from
+// the request, so feed IDs do not change with the Host header.
+func apiURL() string {
+	if config.AppConfig.APIURL != "" {
+		return strings.TrimRight(config.AppConfig.APIURL, "/")
+	}
+	return siteURL()
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/utils"
+	"gorm.io/gorm"
+)
+
+// sitemapMaxURLs is the protocol limit of URLs in a single sitemap. Larger
+// sites get a sitemap index with numbered child sitemaps.
+const sitemapMaxURLs = 50000
+
+const xmlContentType = "application/xml; charset=utf-8"
+
+// SitemapController serves the sitemaps.
+type SitemapController struct {
+	db *gorm.DB
+}
+
+func NewSitemapController(db *gorm.DB) *SitemapController {
+	return &SitemapController{db: db}
+}
+
+// publishedArticles scopes a query to the articles visible to readers.
+func publishedArticles(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&models.Article{}).Where("articles.draft = ?", false)
+}
+
+// sitemapURLs lists every public page: the home, timeline and author pages,
+// articles, categories and tags, each with its last modification time.
+func sitemapURLs(tx *gorm.DB) ([]utils.SitemapURL, error) {
+	var articles []models.Article
+	if err := publishedArticles(tx).Select("id", "updated_at").Order("id ASC").Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	var categories []models.Category
+	if err := tx.Select("id", "slug", "updated_at").Order("id ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := tx.Select("id", "slug", "updated_at").Order("id ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	var latest time.Time
+	for _, a := range articles {
+		if a.UpdatedAt.After(latest) {
+			latest = a.UpdatedAt
+		}
+	}
+
+	urls := []utils.SitemapURL{
+		{Loc: homeURL(), LastMod: latest},
+		{Loc: timelineURL(), LastMod: latest},
+	}
+
+	authors, err := articleAuthors(tx)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, author := range authors {
+		// Every author shares the about page of the Vue app
+		if loc := authorURL(author); !seen[loc] {
+			seen[loc] = true
+			urls = append(urls, utils.SitemapURL{Loc: loc, LastMod: author.UpdatedAt})
+		}
+	}
+
+	for _, a := range articles {
+		urls = append(urls, utils.SitemapURL{Loc: articleURL(a.ID), LastMod: a.UpdatedAt})
+	}
+	for _, cat := range categories {
+		urls = append(urls, utils.SitemapURL{Loc: categoryURL(cat), LastMod: cat.UpdatedAt})
+	}
+	for _, tag := range tags {
+		urls = append(urls, utils.SitemapURL{Loc: tagURL(tag), LastMod: tag.UpdatedAt})
+	}
+
+	return urls, nil
+}
+
+// articleAuthors returns the users with at least one published article.
+func articleAuthors(tx *gorm.DB) ([]models.User, error) {
+	var authors []models.User
+	err := tx.Where("id IN (?)", publishedArticles(tx).Select("author_id")).Order("id ASC").Find(&authors).Error
+	return authors, err
+}
+
+// sitemapPage returns the URLs of the 1-based child sitemap page.
+func sitemapPage(urls []utils.SitemapURL, page int) []utils.SitemapURL {
+	start := (page - 1) * sitemapMaxURLs
+	end := start + sitemapMaxURLs
+	if end > len(urls) {
+		end = len(urls)
+	}
+	return urls[start:end]
+}
+
+// GetSitemap serves /sitemap.xml. Up to sitemapMaxURLs it is a plain
+// sitemap; beyond that it becomes an index of /sitemaps/<n>.xml pages.
+func (ctl *SitemapController) GetSitemap(c *gin.Context) {
+	urls, err := sitemapURLs(ctl.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var body []byte
+	if len(urls) <= sitemapMaxURLs {
+		body, err = utils.SitemapURLSet(urls)
+	} else {
+		pages := (len(urls) + sitemapMaxURLs - 1) / sitemapMaxURLs
+		sitemaps := make([]utils.SitemapURL, 0, pages)
+		for page := 1; page <= pages; page++ {
+			entry := utils.SitemapURL{Loc: fmt.Sprintf("%s/sitemaps/%d.xml", apiURL(), page)}
+			for _, u := range sitemapPage(urls, page) {
+				if u.LastMod.After(entry.LastMod) {
+					entry.LastMod = u.LastMod
+				}
+			}
+			sitemaps = append(sitemaps, entry)
+		}
+		body, err = utils.SitemapIndex(sitemaps)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, xmlContentType, body)
+}
+
+// GetSitemapPage serves one child sitemap of the sitemap index.
+func (ctl *SitemapController) GetSitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil || page < 1 || !strings.HasSuffix(c.Param("page"), ".xml") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+
+	urls, err := sitemapURLs(ctl.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(urls) <= sitemapMaxURLs || (page-1)*sitemapMaxURLs >= len(urls) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+
+	body, err := utils.SitemapURLSet(sitemapPage(urls, page))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, xmlContentType, body)
+}
+
+// GetRobotsTxt serves ROBOTS_TXT_FILE when configured, and otherwise a file
+// built from ROBOTS_DISALLOW that points crawlers to the sitemap.
+func GetRobotsTxt(c *gin.Context) {
+	if file := config.AppConfig.RobotsTxtFile; file != "" {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read robots.txt"})
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", body)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("User-agent: *\n")
+	for _, rule := range strings.Split(config.AppConfig.RobotsDisallow, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			sb.WriteString("Disallow: " + rule + "\n")
+		}
+	}
+	sb.WriteString("\nSitemap: " + apiURL() + "/sitemap.xml\n")
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(sb.String()))
+}
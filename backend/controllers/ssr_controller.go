@@ -0,0 +1,423 @@
+package controllers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/templates"
+	"gorm.io/gorm"
+)
+
+const ssrListLimit = 20 // Articles on the prerendered home page
+
+var (
+	articlePagePattern  = regexp.MustCompile(`^/articles/(\d+)$`)
+	taxonomyPagePattern = regexp.MustCompile(`^/(categories|tags|authors)/([^/]+)$`)
+	titleTagPattern     = regexp.MustCompile(`(?is)<title>.*?</title>`)
+)
+
+// FrontendController serves the built frontend with prerendered pages.
+type FrontendController struct {
+	db *gorm.DB
+}
+
+func NewFrontendController(db *gorm.DB) *FrontendController {
+	return &FrontendController{db: db}
+}
+
+// PageMeta is the metadata rendered into the head of a prerendered page.
+type PageMeta struct {
+	SiteName     string
+	Title        string
+	Description  string
+	CanonicalURL string
+	Type         string // Open Graph type: website, article or profile
+	Image        string
+	Published    time.Time
+	Modified     time.Time
+	Author       string
+	Tags         []string
+	FeedLinks    []FeedLink
+	JSONLD       interface{} // Encoded as JSON by html/template
+}
+
+type FeedLink struct {
+	Type  string
+	Title string
+	Href  string
+}
+
+// PageItem is an article in a prerendered list.
+type PageItem struct {
+	URL       string
+	Title     string
+	Excerpt   string
+	CreatedAt time.Time
+}
+
+type listPageData struct {
+	Heading string
+	Intro   string
+	Items   []PageItem
+}
+
+type articlePageData struct {
+	Article     models.Article
+	Content     template.HTML // Already sanitized by the Markdown pipeline
+	AuthorURL   string
+	CategoryURL string
+	TagURLs     map[uint]string
+}
+
+type authorPageData struct {
+	Author models.User
+	Items  []PageItem
+}
+
+func newPageMeta(title, description, canonical string) PageMeta {
+	site := config.AppConfig.SiteTitle
+	if title != site {
+		title += " - " + site
+	}
+	return PageMeta{SiteName: site, Title: title, Description: description, CanonicalURL: canonical, Type: "website"}
+}
+
+func feedLinks(prefix, title string) []FeedLink {
+	base := apiURL() + prefix
+	return []FeedLink{
+		{Type: "application/rss+xml", Title: title, Href: base + "/feed.xml"},
+		{Type: "application/atom+xml", Title: title, Href: base + "/atom.xml"},
+		{Type: "application/feed+json", Title: title, Href: base + "/feed.json"},
+	}
+}
+
+// pageItems lists the newest articles of query; a limit of 0 lists all.
+func pageItems(query *gorm.DB, limit int) ([]PageItem, error) {
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var articles []models.Article
+	err := query.Select("articles.id", "articles.title", "articles.excerpt", "articles.created_at").
+		Order("articles.created_at DESC").Order("articles.id DESC").
+		Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]PageItem, 0, len(articles))
+	for _, a := range articles {
+		items = append(items, PageItem{URL: articleURL(a.ID), Title: a.Title, Excerpt: a.Excerpt, CreatedAt: a.CreatedAt})
+	}
+	return items, nil
+}
+
+func blogPostingRefs(items []PageItem) []gin.H {
+	refs := make([]gin.H, 0, len(items))
+	for _, item := range items {
+		refs = append(refs, gin.H{
+			"@type":         "BlogPosting",
+			"headline":      item.Title,
+			"url":           item.URL,
+			"datePublished": item.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return refs
+}
+
+// renderPage fills the SPA's index.html with the page metadata and the
+// prerendered body. The Vue app replaces the body once it mounts.
+func renderPage(c *gin.Context, index []byte, meta PageMeta, name string, data interface{}) {
+	var head, body bytes.Buffer
+	if err := templates.Pages.ExecuteTemplate(&head, "head", meta); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := templates.Pages.ExecuteTemplate(&body, name, data); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	page := string(index)
+	if titleTagPattern.MatchString(page) {
+		page = titleTagPattern.ReplaceAllLiteralString(page, strings.TrimSpace(head.String()))
+	} else {
+		page = strings.Replace(page, "</head>", head.String()+"</head>", 1)
+	}
+	page = strings.Replace(page, `<div id="app"></div>`, `<div id="app">`+body.String()+`</div>`, 1)
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// serveSPA returns the unmodified index.html and leaves routing to the app.
+func serveSPA(c *gin.Context, status int, index []byte) {
+	c.Data(status, "text/html; charset=utf-8", index)
+}
+
+// frontendIndex returns the page shell prerendered content is inserted into:
+// the built index.html of the Vue app, or the standalone "document" template
+// in a static export, which has no app to boot.
+func frontendIndex() ([]byte, error) {
+	if staticLinks {
+		var shell bytes.Buffer
+		err := templates.Pages.ExecuteTemplate(&shell, "document", gin.H{
+			"SiteName":    config.AppConfig.SiteTitle,
+			"HomeURL":     homeURL(),
+			"TimelineURL": timelineURL(),
+		})
+		return shell.Bytes(), err
+	}
+	return os.ReadFile(filepath.Join(config.AppConfig.FrontendDist, "index.html"))
+}
+
+// ServeFrontend serves the built frontend when SSR_ENABLED is set. Static
+// files are returned as they are, the article, list, category, tag and author
+// pages are prerendered, and every other route falls back to index.html.
+func (ctl *FrontendController) ServeFrontend(c *gin.Context) {
+	urlPath := path.Clean("/" + c.Request.URL.Path)
+	if strings.HasPrefix(urlPath, "/api/") || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	if urlPath != "/" && !staticLinks {
+		file := filepath.Join(config.AppConfig.FrontendDist, filepath.FromSlash(urlPath))
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			c.File(file)
+			return
+		}
+	}
+
+	index, err := frontendIndex()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Frontend build not found"})
+		return
+	}
+
+	if m := articlePagePattern.FindStringSubmatch(urlPath); m != nil {
+		ctl.renderArticlePage(c, index, m[1])
+		return
+	}
+	if m := taxonomyPagePattern.FindStringSubmatch(urlPath); m != nil {
+		switch m[1] {
+		case "categories":
+			ctl.renderCategoryPage(c, index, ctl.db.Where("slug = ?", m[2]))
+		case "tags":
+			ctl.renderTagPage(c, index, ctl.db.Where("slug = ?", m[2]))
+		default:
+			ctl.renderAuthorPage(c, index, ctl.db.Where("username = ?", m[2]))
+		}
+		return
+	}
+
+	switch urlPath {
+	case "/":
+		ctl.renderHomePage(c, index)
+	case "/timeline":
+		// The timeline doubles as category and tag page in the Vue app
+		if id, err := strconv.ParseUint(c.Query("category_id"), 10, 32); err == nil {
+			ctl.renderCategoryPage(c, index, ctl.db.Where("id = ?", id))
+		} else if id, err := strconv.ParseUint(c.Query("tag_id"), 10, 32); err == nil {
+			ctl.renderTagPage(c, index, ctl.db.Where("id = ?", id))
+		} else {
+			ctl.renderTimelinePage(c, index)
+		}
+	case "/about":
+		author, err := siteAuthor(ctl.db)
+		if err != nil {
+			serveSPA(c, http.StatusNotFound, index)
+			return
+		}
+		ctl.renderAuthorPage(c, index, ctl.db.Where("id = ?", author.ID))
+	default:
+		serveSPA(c, http.StatusOK, index)
+	}
+}
+
+func (ctl *FrontendController) renderArticlePage(c *gin.Context, index []byte, id string) {
+	var article models.Article
+	err := publishedArticles(ctl.db).
+		Preload("Author").Preload("Category").Preload("Tags").
+		First(&article, id).Error
+	if err != nil {
+		serveSPA(c, http.StatusNotFound, index)
+		return
+	}
+
+	meta := newPageMeta(article.Title, article.Excerpt, articleURL(article.ID))
+	meta.Type = "article"
+	meta.Image = article.CoverURL
+	meta.Published = article.CreatedAt
+	meta.Modified = article.UpdatedAt
+	meta.Author = article.Author.Username
+	meta.FeedLinks = feedLinks(commentFeedPath(article.ID), "Comments on "+article.Title)
+
+	data := articlePageData{
+		Article:     article,
+		Content:     template.HTML(article.ContentHTML),
+		AuthorURL:   authorURL(article.Author),
+		CategoryURL: categoryURL(article.Category),
+		TagURLs:     map[uint]string{},
+	}
+	for _, tag := range article.Tags {
+		meta.Tags = append(meta.Tags, tag.Name)
+		data.TagURLs[tag.ID] = tagURL(tag)
+	}
+
+	posting := gin.H{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         article.Title,
+		"description":      article.Excerpt,
+		"url":              meta.CanonicalURL,
+		"mainEntityOfPage": meta.CanonicalURL,
+		"datePublished":    article.CreatedAt.UTC().Format(time.RFC3339),
+		"dateModified":     article.UpdatedAt.UTC().Format(time.RFC3339),
+		"wordCount":        article.WordCount,
+		"author":           gin.H{"@type": "Person", "name": article.Author.Username, "url": data.AuthorURL},
+	}
+	if article.CoverURL != "" {
+		posting["image"] = article.CoverURL
+	}
+	if len(meta.Tags) > 0 {
+		posting["keywords"] = strings.Join(meta.Tags, ", ")
+	}
+	if article.Category.ID != 0 {
+		posting["articleSection"] = article.Category.Name
+	}
+	meta.JSONLD = posting
+
+	renderPage(c, index, meta, "article", data)
+}
+
+func (ctl *FrontendController) renderHomePage(c *gin.Context, index []byte) {
+	items, err := pageItems(publishedArticles(ctl.db), ssrListLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	site := config.AppConfig.SiteTitle
+	meta := newPageMeta(site, config.AppConfig.SiteDescription, homeURL())
+	meta.FeedLinks = feedLinks("", site)
+	meta.JSONLD = gin.H{
+		"@context":    "https://schema.org",
+		"@type":       "Blog",
+		"name":        site,
+		"description": config.AppConfig.SiteDescription,
+		"url":         meta.CanonicalURL,
+		"blogPost":    blogPostingRefs(items),
+	}
+
+	renderPage(c, index, meta, "list", listPageData{Heading: site, Intro: config.AppConfig.SiteDescription, Items: items})
+}
+
+// renderListPage renders an archive page listing every article of query.
+func (ctl *FrontendController) renderListPage(c *gin.Context, index []byte, query *gorm.DB, meta PageMeta, data listPageData) {
+	items, err := pageItems(query, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	data.Items = items
+
+	meta.JSONLD = gin.H{
+		"@context":    "https://schema.org",
+		"@type":       "CollectionPage",
+		"name":        data.Heading,
+		"description": data.Intro,
+		"url":         meta.CanonicalURL,
+		"hasPart":     blogPostingRefs(items),
+	}
+
+	renderPage(c, index, meta, "list", data)
+}
+
+func (ctl *FrontendController) renderTimelinePage(c *gin.Context, index []byte) {
+	meta := newPageMeta("Timeline", config.AppConfig.SiteDescription, timelineURL())
+	meta.FeedLinks = feedLinks("", config.AppConfig.SiteTitle)
+	ctl.renderListPage(c, index, publishedArticles(ctl.db), meta, listPageData{Heading: "Timeline"})
+}
+
+// renderCategoryPage renders the category matched by lookup together with
+// the articles of its subcategories.
+func (ctl *FrontendController) renderCategoryPage(c *gin.Context, index []byte, lookup *gorm.DB) {
+	var category models.Category
+	if err := lookup.First(&category).Error; err != nil {
+		serveSPA(c, http.StatusNotFound, index)
+		return
+	}
+	categoryIDs, err := categoryDescendants(ctl.db, category.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	meta := newPageMeta(category.Name, category.Description, categoryURL(category))
+	meta.Image = category.CoverURL
+	meta.FeedLinks = feedLinks(categoryFeedPath(category), category.Name)
+
+	query := publishedArticles(ctl.db).Where("category_id IN ?", categoryIDs)
+	ctl.renderListPage(c, index, query, meta, listPageData{Heading: category.Name, Intro: category.Description})
+}
+
+func (ctl *FrontendController) renderTagPage(c *gin.Context, index []byte, lookup *gorm.DB) {
+	var tag models.Tag
+	if err := lookup.First(&tag).Error; err != nil {
+		serveSPA(c, http.StatusNotFound, index)
+		return
+	}
+
+	heading := "#" + tag.Name
+	meta := newPageMeta(heading, tag.Description, tagURL(tag))
+	meta.Image = tag.CoverURL
+	meta.FeedLinks = feedLinks(tagFeedPath(tag), heading)
+
+	query := publishedArticles(ctl.db).
+		Joins("JOIN article_tags ON article_tags.article_id = articles.id").
+		Where("article_tags.tag_id = ?", tag.ID)
+	ctl.renderListPage(c, index, query, meta, listPageData{Heading: heading, Intro: tag.Description})
+}
+
+func (ctl *FrontendController) renderAuthorPage(c *gin.Context, index []byte, lookup *gorm.DB) {
+	var author models.User
+	if err := lookup.First(&author).Error; err != nil {
+		serveSPA(c, http.StatusNotFound, index)
+		return
+	}
+
+	items, err := pageItems(publishedArticles(ctl.db).Where("author_id = ?", author.ID), 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	meta := newPageMeta(author.Username, author.Bio, authorURL(author))
+	meta.Type = "profile"
+	meta.Image = author.AvatarURL
+	meta.FeedLinks = feedLinks(authorFeedPath(author), author.Username)
+
+	person := gin.H{"@type": "Person", "name": author.Username, "url": meta.CanonicalURL, "description": author.Bio}
+	if author.AvatarURL != "" {
+		person["image"] = author.AvatarURL
+	}
+	meta.JSONLD = gin.H{
+		"@context":   "https://schema.org",
+		"@type":      "ProfilePage",
+		"url":        meta.CanonicalURL,
+		"mainEntity": person,
+	}
+
+	renderPage(c, index, meta, "author", authorPageData{Author: author, Items: items})
+}
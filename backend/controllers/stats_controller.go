@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+// maxStatsPoints bounds the length of the time series.
+const maxStatsPoints = 1000
+
+// StatsController serves the dashboard statistics.
+type StatsController struct {
+	db *gorm.DB
+}
+
+func NewStatsController(db *gorm.DB) *StatsController {
+	return &StatsController{db: db}
+}
+
+// statsInterval is a period of a time series together with the range shown
+// when from is omitted.
+type statsInterval struct {
+	name        string
+	defaultFrom func(to time.Time) time.Time
+}
+
+// statsIntervals are the intervals of GetStats, the default first.
+var statsIntervals = []statsInterval{
+	{"day", func(to time.Time) time.Time { return to.AddDate(0, 0, -29) }},
+	{"week", func(to time.Time) time.Time { return to.AddDate(0, 0, -7*11) }},
+	{"month", func(to time.Time) time.Time { return to.AddDate(0, -11, 0) }},
+}
+
+// StatsTotals are the current numbers of the blog.
+type StatsTotals struct {
+	Articles struct {
+		Published int64 `json:"published"`
+		Drafts    int64 `json:"drafts"`
+		Trashed   int64 `json:"trashed"`
+	} `json:"articles"`
+	Comments struct {
+		Live    int64 `json:"live"`
+		Trashed int64 `json:"trashed"`
+	} `json:"comments"`
+	Tags       int64 `json:"tags"`
+	Categories int64 `json:"categories"`
+	Media      struct {
+		Files int64 `json:"files"`
+		Bytes int64 `json:"bytes"`
+	} `json:"media"`
+	Views int64 `json:"views"` // All-time counters of the live articles
+	Likes int64 `json:"likes"`
+}
+
+// StatsPoint holds the activity of one day, week or month. Period is the
+// first day of it (YYYY-MM-DD), or YYYY-MM for months.
+type StatsPoint struct {
+	Period   string `json:"period"`
+	Views    int64  `json:"views"`
+	Likes    int64  `json:"likes"`
+	Comments int64  `json:"comments"`
+}
+
+// StatsArticle is an article ranked by its views in the selected range.
+type StatsArticle struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+	Views int64  `json:"views"`
+}
+
+// StatsReferrer is a referring site ranked by the views it sent.
+type StatsReferrer struct {
+	Referrer string `json:"referrer"`
+	Views    int64  `json:"views"`
+}
+
+// statsRange is the validated query of GetStats. Days run from from up to
+// but excluding to, in local time.
+type statsRange struct {
+	interval string
+	from, to time.Time
+	limit    int
+}
+
+// GetStats returns the dashboard statistics: totals, views, likes and
+// comments per day, week or month (?interval=), and the top articles and
+// referrers between ?from= and ?to= (YYYY-MM-DD, inclusive). Views and likes
+// are counted from the recorded events, comments from their creation time.
+func (ctl *StatsController) GetStats(c *gin.Context) {
+	q, err := parseStatsRange(c, statsIntervals)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := ctl.db
+	totals, err := statsTotals(db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	series, err := statsSeries(db, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	topArticles := []StatsArticle{}
+	err = statsEvents(db, q, models.ArticleEventView).
+		Joins("JOIN articles ON articles.id = article_events.article_id AND articles.deleted_at IS NULL").
+		Select("articles.id, articles.title, articles.slug, COUNT(*) AS views").
+		Group("articles.id, articles.title, articles.slug").
+		Order("views DESC, articles.id").Limit(q.limit).
+		Scan(&topArticles).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	topReferrers := []StatsReferrer{}
+	err = statsEvents(db, q, models.ArticleEventView).
+		Where("referrer <> ''").
+		Select("referrer, COUNT(*) AS views").
+		Group("referrer").
+		Order("views DESC, referrer").Limit(q.limit).
+		Scan(&topReferrers).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interval":      q.interval,
+		"from":          q.from.Format(time.DateOnly),
+		"to":            q.to.AddDate(0, 0, -1).Format(time.DateOnly),
+		"totals":        totals,
+		"series":        series,
+		"top_articles":  topArticles,
+		"top_referrers": topReferrers,
+	})
+}
+
+// parseStatsRange reads the interval, from, to and limit parameters. The
+// interval is one of intervals, the first by default.
+func parseStatsRange(c *gin.Context, intervals []statsInterval) (statsRange, error) {
+	q := statsRange{interval: c.DefaultQuery("interval", intervals[0].name)}
+	var defaultFrom func(to time.Time) time.Time
+	names := make([]string, len(intervals))
+	for i, interval := range intervals {
+		names[i] = interval.name
+		if interval.name == q.interval {
+			defaultFrom = interval.defaultFrom
+		}
+	}
+	if defaultFrom == nil {
+		return q, fmt.Errorf("interval must be one of %s", strings.Join(names, ", "))
+	}
+
+	var err error
+	if q.limit, err = strconv.Atoi(c.DefaultQuery("limit", "10")); err != nil || q.limit <= 0 || q.limit > 100 {
+		return q, fmt.Errorf("limit must be between 1 and 100")
+	}
+
+	now := time.Now()
+	q.to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if raw := c.Query("to"); raw != "" {
+		if q.to, err = time.ParseInLocation(time.DateOnly, raw, time.Local); err != nil {
+			return q, fmt.Errorf("to must be a date like 2024-01-31")
+		}
+	}
+	q.from = defaultFrom(q.to)
+	if raw := c.Query("from"); raw != "" {
+		if q.from, err = time.ParseInLocation(time.DateOnly, raw, time.Local); err != nil {
+			return q, fmt.Errorf("from must be a date like 2024-01-01")
+		}
+	}
+	q.from = periodStart(q.interval, q.from)
+	q.to = q.to.AddDate(0, 0, 1)
+
+	if !q.from.Before(q.to) {
+		return q, fmt.Errorf("from must not be after to")
+	}
+	if len(statsPeriods(q)) > maxStatsPoints {
+		return q, fmt.Errorf("the range has more than %d %ss, choose a longer interval", maxStatsPoints, q.interval)
+	}
+	return q, nil
+}
+
+// periodStart returns the first day of the week (Monday) or month day is in.
+// Days and hours start with day itself.
+func periodStart(interval string, day time.Time) time.Time {
+	switch interval {
+	case "week":
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "month":
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+func periodLabel(interval string, start time.Time) string {
+	switch interval {
+	case "hour":
+		return start.Format("2006-01-02 15:00")
+	case "month":
+		return start.Format("2006-01")
+	}
+	return start.Format(time.DateOnly)
+}
+
+// statsPeriods lists the labels of all periods of the range in order.
+func statsPeriods(q statsRange) []string {
+	var periods []string
+	for start := q.from; start.Before(q.to); {
+		periods = append(periods, periodLabel(q.interval, start))
+		switch q.interval {
+		case "hour":
+			start = start.Add(time.Hour)
+		case "week":
+			start = start.AddDate(0, 0, 7)
+		case "month":
+			start = start.AddDate(0, 1, 0)
+		default:
+			start = start.AddDate(0, 0, 1)
+		}
+		if len(periods) > maxStatsPoints {
+			break
+		}
+	}
+	return periods
+}
+
+// statsDay is the SQL expression of the local date (YYYY-MM-DD) of column.
+// MySQL and SQLite store the local time as written by the blog; PostgreSQL
+// uses the time zone of the connection.
+func statsDay(tx *gorm.DB, column string) string {
+	switch tx.Dialector.Name() {
+	case "mysql":
+		return "DATE_FORMAT(" + column + ", '%Y-%m-%d')"
+	case "postgres":
+		return "to_char(" + column + ", 'YYYY-MM-DD')"
+	}
+	return "substr(" + column + ", 1, 10)"
+}
+
+// statsEvents selects the events of one type in the range.
+func statsEvents(tx *gorm.DB, q statsRange, eventType string) *gorm.DB {
+	return tx.Model(&models.ArticleEvent{}).
+		Where("article_events.type = ? AND article_events.created_at >= ? AND article_events.created_at < ?", eventType, q.from, q.to)
+}
+
+// statsSeries counts views, likes and comments per period, including the
+// periods without any.
+func statsSeries(tx *gorm.DB, q statsRange) ([]StatsPoint, error) {
+	periods := statsPeriods(q)
+	points := make([]StatsPoint, len(periods))
+	index := make(map[string]int, len(periods))
+	for i, period := range periods {
+		points[i].Period = period
+		index[period] = i
+	}
+
+	day := statsDay(tx, "created_at")
+	counters := []struct {
+		query   *gorm.DB
+		counter func(p *StatsPoint) *int64
+	}{
+		{statsEvents(tx, q, models.ArticleEventView), func(p *StatsPoint) *int64 { return &p.Views }},
+		{statsEvents(tx, q, models.ArticleEventLike), func(p *StatsPoint) *int64 { return &p.Likes }},
+		{tx.Model(&models.Comment{}).Where("created_at >= ? AND created_at < ?", q.from, q.to), func(p *StatsPoint) *int64 { return &p.Comments }},
+	}
+	for _, c := range counters {
+		var rows []struct {
+			Day   string
+			Count int64
+		}
+		if err := c.query.Select(day + " AS day, COUNT(*) AS count").Group(day).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			date, err := time.ParseInLocation(time.DateOnly, row.Day, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected date %q: %w", row.Day, err)
+			}
+			if i, ok := index[periodLabel(q.interval, periodStart(q.interval, date))]; ok {
+				*c.counter(&points[i]) += row.Count
+			}
+		}
+	}
+	return points, nil
+}
+
+func statsTotals(tx *gorm.DB) (StatsTotals, error) {
+	var t StatsTotals
+	counts := []struct {
+		query *gorm.DB
+		dest  *int64
+	}{
+		{tx.Model(&models.Article{}).Where("draft = ?", false), &t.Articles.Published},
+		{tx.Model(&models.Article{}).Where("draft = ?", true), &t.Articles.Drafts},
+		{tx.Unscoped().Model(&models.Article{}).Where("deleted_at IS NOT NULL"), &t.Articles.Trashed},
+		{tx.Model(&models.Comment{}), &t.Comments.Live},
+		{tx.Unscoped().Model(&models.Comment{}).Where("deleted_at IS NOT NULL"), &t.Comments.Trashed},
+		{tx.Model(&models.Tag{}), &t.Tags},
+		{tx.Model(&models.Category{}), &t.Categories},
+		{tx.Model(&models.Media{}), &t.Media.Files},
+	}
+	for _, c := range counts {
+		if err := c.query.Count(c.dest).Error; err != nil {
+			return t, err
+		}
+	}
+
+	if err := tx.Model(&models.Media{}).Select("COALESCE(SUM(size), 0)").Scan(&t.Media.Bytes).Error; err != nil {
+		return t, err
+	}
+	var sums struct {
+		Views int64
+		Likes int64
+	}
+	err := tx.Model(&models.Article{}).Select("COALESCE(SUM(views), 0) AS views, COALESCE(SUM(likes), 0) AS likes").Scan(&sums).Error
+	t.Views, t.Likes = sums.Views, sums.Likes
+	return t, err
+}
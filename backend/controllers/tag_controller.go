@@ -1,101 +1,187 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"sort"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/your-username/blog-backend/database"
-	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/services"
 )
 
-func GetTags(c *gin.Context) {
-	var tags []models.Tag
-	if err := database.DB.Find(&tags).Error; err != nil {
+// GetTags lists tags with usage counts. sort=popular orders by usage,
+// sort=name alphabetically; by default the creation order is kept.
+func (ctl *TaxonomyController) GetTags(c *gin.Context) {
+	result, err := ctl.taxonomy.Tags()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, tags)
+	switch c.Query("sort") {
+	case "popular":
+		sort.SliceStable(result, func(i, j int) bool { return result[i].UsageCount > result[j].UsageCount })
+	case "name":
+		sort.SliceStable(result, func(i, j int) bool { return services.TagKey(result[i].Name) < services.TagKey(result[j].Name) })
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetTag looks a tag up by ID or slug.
+func (ctl *TaxonomyController) GetTag(c *gin.Context) {
+	tag, err := ctl.taxonomy.GetTag(c.Param("id"))
+	if err != nil {
+		taxonomyError(c, err, "Tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
 }
 
-func DeleteTag(c *gin.Context) {
-	id := c.Param("id")
-	var tag models.Tag
+func (ctl *TaxonomyController) CreateTag(c *gin.Context) {
+	var input services.CreateTagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if err := database.DB.First(&tag, id).Error; err != nil {
+	tag, err := ctl.taxonomy.CreateTag(input)
+	if err != nil {
+		taxonomyError(c, err, "Tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag removes a tag. See parseDeleteOptions for the modes; with
+// dry_run=true only the affected counts are returned.
+func (ctl *TaxonomyController) DeleteTag(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
 		return
 	}
 
-	// Delete all articles associated with this tag
-	// First find article IDs
-	var articleTags []struct {
-		ArticleID uint
+	opts, err := parseDeleteOptions(c, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := ctl.taxonomy.DeleteTag(id, opts)
+	if err != nil {
+		taxonomyError(c, err, "Tag")
+		return
 	}
-	// Assuming article_tags table name. GORM default is `article_tags` for many2many
-	if err := database.DB.Table("article_tags").Select("article_id").Where("tag_id = ?", id).Scan(&articleTags).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find associated articles"})
+	if opts.DryRun {
+		c.JSON(http.StatusOK, preview)
 		return
 	}
 
-	var articleIDs []uint
-	for _, at := range articleTags {
-		articleIDs = append(articleIDs, at.ArticleID)
+	ctl.related.Reset()
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully", "result": preview})
+}
+
+func (ctl *TaxonomyController) UpdateTag(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
 	}
 
-	if len(articleIDs) > 0 {
-		// Delete ALL tag associations for these articles (not just the current tag)
-		if err := database.DB.Exec("DELETE FROM article_tags WHERE article_id IN ?", articleIDs).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete article tags"})
-			return
-		}
+	var input services.UpdateTagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-		// Delete comments
-		if err := database.DB.Exec("DELETE FROM comments WHERE article_id IN ?", articleIDs).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete article comments"})
-			return
-		}
+	tag, err := ctl.taxonomy.UpdateTag(id, input)
+	if err != nil {
+		taxonomyError(c, err, "Tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+type MergeTagInput struct {
+	TargetID uint `json:"target_id" binding:"required"`
+}
 
-		// Hard delete articles to avoid issues with soft deleted records still referencing things
-		if err := database.DB.Unscoped().Delete(&models.Article{}, articleIDs).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete associated articles"})
-			return
-		}
+// MergeTag moves every article link of tag :id to target_id and deletes :id.
+// The old name and its aliases become aliases of the target.
+func (ctl *TaxonomyController) MergeTag(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
 	}
 
-	// Delete the tag itself
-	if err := database.DB.Delete(&tag).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var input MergeTagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, moved, err := ctl.taxonomy.MergeTag(id, input.TargetID)
+	if errors.Is(err, services.ErrTargetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Target tag not found"})
+		return
+	}
+	if err != nil {
+		taxonomyError(c, err, "Tag")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Tag and associated articles deleted successfully"})
+	ctl.related.Reset()
+	c.JSON(http.StatusOK, gin.H{"message": "Tags merged successfully", "tag": target, "moved_links": moved})
 }
 
-type UpdateTagInput struct {
+type TagAliasInput struct {
 	Name string `json:"name" binding:"required"`
 }
 
-func UpdateTag(c *gin.Context) {
-	id := c.Param("id")
-	var tag models.Tag
-
-	if err := database.DB.First(&tag, id).Error; err != nil {
+func (ctl *TaxonomyController) CreateTagAlias(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
 		return
 	}
 
-	var input UpdateTagInput
+	var input TagAliasInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	tag.Name = input.Name
-	if err := database.DB.Save(&tag).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	alias, err := ctl.taxonomy.CreateTagAlias(id, input.Name)
+	if errors.Is(err, services.ErrNameRequired) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Alias must not be empty"})
+		return
+	}
+	if err != nil {
+		taxonomyError(c, err, "Tag")
 		return
 	}
 
-	c.JSON(http.StatusOK, tag)
+	c.JSON(http.StatusOK, alias)
+}
+
+func (ctl *TaxonomyController) DeleteTagAlias(c *gin.Context) {
+	id, ok := paramID(c)
+	aliasID, err := strconv.ParseUint(c.Param("alias_id"), 10, 32)
+	if !ok || err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alias not found"})
+		return
+	}
+
+	if err := ctl.taxonomy.DeleteTagAlias(id, uint(aliasID)); err != nil {
+		taxonomyError(c, err, "Alias")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Alias deleted successfully"})
 }
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
+	"gorm.io/gorm"
+)
+
+// TaxonomyController serves the category and tag endpoints.
+type TaxonomyController struct {
+	taxonomy *services.TaxonomyService
+	related  *services.RelatedService
+}
+
+func NewTaxonomyController(taxonomy *services.TaxonomyService, related *services.RelatedService) *TaxonomyController {
+	return &TaxonomyController{taxonomy: taxonomy, related: related}
+}
+
+// taxonomyError maps the errors of TaxonomyService to responses. noun names
+// the record in not-found messages.
+func taxonomyError(c *gin.Context, err error, noun string) {
+	var conflict *services.ConflictError
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": noun + " not found"})
+	case errors.As(err, &conflict) && conflict.Trashed:
+		c.JSON(http.StatusConflict, gin.H{"error": conflict.Message, "trash_id": conflict.ID})
+	case errors.As(err, &conflict) && noun == "Category":
+		c.JSON(http.StatusConflict, gin.H{"error": conflict.Message, "category_id": conflict.ID})
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{"error": conflict.Message, "tag_id": conflict.ID})
+	case errors.Is(err, services.ErrNameRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": noun + " name must not be empty"})
+	case errors.Is(err, services.ErrTargetNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Target " + strings.ToLower(noun) + " not found"})
+	case errors.Is(err, services.ErrInvalidColor),
+		errors.Is(err, services.ErrSlugUnavailable),
+		errors.Is(err, services.ErrCoverNotFound),
+		errors.Is(err, services.ErrParentNotFound),
+		errors.Is(err, services.ErrCategoryCycle),
+		errors.Is(err, services.ErrMergeIntoSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// parseDeleteOptions reads ?mode=detach|reassign|cascade&target_id=&dry_run=.
+// Detach is the default so a bare DELETE never removes articles.
+func parseDeleteOptions(c *gin.Context, id uint) (services.DeleteOptions, error) {
+	opts := services.DeleteOptions{Mode: c.DefaultQuery("mode", services.DeleteModeDetach)}
+
+	switch opts.Mode {
+	case services.DeleteModeDetach, services.DeleteModeCascade:
+	case services.DeleteModeReassign:
+		target, err := strconv.ParseUint(c.Query("target_id"), 10, 32)
+		if err != nil || target == 0 {
+			return opts, fmt.Errorf("target_id is required for mode=reassign")
+		}
+		if uint(target) == id {
+			return opts, fmt.Errorf("target_id must differ from the deleted item")
+		}
+		opts.TargetID = uint(target)
+	default:
+		return opts, fmt.Errorf("mode must be one of detach, reassign, cascade")
+	}
+
+	if raw := c.Query("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, fmt.Errorf("dry_run must be a boolean")
+		}
+		opts.DryRun = dryRun
+	}
+
+	return opts, nil
+}
+
+// The helpers below serve the handlers that query the database themselves
+// (SSR pages and the importers).
+
+// resolveTags maps free-text tag names to canonical tags, creating the ones
+// that do not exist yet. Duplicates after normalization are dropped.
+func resolveTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	return services.NewTaxonomyService(repository.NewStore(tx)).ResolveTags(names)
+}
+
+// categoryDescendants returns id followed by the IDs of all its descendants.
+func categoryDescendants(tx *gorm.DB, id uint) ([]uint, error) {
+	return services.NewTaxonomyService(repository.NewStore(tx)).CategoryDescendants(id)
+}
+
+// restoreTrashedCategory takes the deleted category called name out of the
+// trash and returns its ID, or 0 when there is none.
+func restoreTrashedCategory(tx *gorm.DB, name string) (uint, error) {
+	store := repository.NewStore(tx)
+	id, err := store.Taxonomy().TrashedByName(repository.KindCategory, name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, services.NewTrashService(store).Restore(repository.KindCategory, id)
+}
+
+func slugTaken(tx *gorm.DB, model interface{}, slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := tx.Unscoped().Model(model).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
+)
+
+// TrashController serves the trash endpoints.
+type TrashController struct {
+	trash   *services.TrashService
+	related *services.RelatedService
+}
+
+func NewTrashController(trash *services.TrashService, related *services.RelatedService) *TrashController {
+	return &TrashController{trash: trash, related: related}
+}
+
+// TrashItem is a soft-deleted record.
+type TrashItem struct {
+	Type      string     `json:"type"`
+	ID        uint       `json:"id"`
+	Title     string     `json:"title"`
+	ArticleID *uint      `json:"article_id,omitempty"` // Comments only
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"` // When the retention job removes it
+}
+
+// trashRetention is how long deleted items are kept, 0 for forever.
+func trashRetention() time.Duration {
+	return time.Duration(config.AppConfig.TrashRetentionDays) * 24 * time.Hour
+}
+
+// parseTrashKinds reads the optional type parameter; empty selects all
+// kinds.
+func parseTrashKinds(raw string) ([]repository.Kind, error) {
+	if raw == "" {
+		return services.TrashKinds, nil
+	}
+	kind, ok := trashKind(raw)
+	if !ok {
+		return nil, fmt.Errorf("type must be one of %s", trashKindNames())
+	}
+	return []repository.Kind{kind}, nil
+}
+
+func trashKind(raw string) (repository.Kind, bool) {
+	for _, kind := range services.TrashKinds {
+		if string(kind) == raw {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+func trashKindNames() string {
+	names := make([]string, 0, len(services.TrashKinds))
+	for _, kind := range services.TrashKinds {
+		names = append(names, string(kind))
+	}
+	return strings.Join(names, ", ")
+}
+
+// GetTrash lists soft-deleted articles, comments, tags and categories, most
+// recently deleted first. ?type= limits the list to one kind.
+func (ctl *TrashController) GetTrash(c *gin.Context) {
+	kinds, err := parseTrashKinds(c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	records, err := ctl.trash.List(kinds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	retention := trashRetention()
+	items := make([]TrashItem, 0, len(records))
+	for _, record := range records {
+		item := TrashItem{Type: string(record.Kind), ID: record.ID, Title: trashTitle(record.Title), DeletedAt: record.DeletedAt}
+		if record.Kind == repository.KindComment {
+			item.ArticleID = record.ArticleID
+		}
+		if retention > 0 {
+			purgeAt := record.DeletedAt.Add(retention)
+			item.PurgeAt = &purgeAt
+		}
+		items = append(items, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"retention_days": config.AppConfig.TrashRetentionDays, "items": items})
+}
+
+// trashTitle shortens comment bodies to a one-line title.
+func trashTitle(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= 80 {
+		return s
+	}
+	return string([]rune(s)[:80]) + "…"
+}
+
+// trashItem parses the :type and :id parameters.
+func trashItem(c *gin.Context) (repository.Kind, uint, bool) {
+	kind, ok := trashKind(c.Param("type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be one of " + trashKindNames()})
+		return kind, 0, false
+	}
+
+	id, ok := paramID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found in the trash"})
+		return kind, 0, false
+	}
+	return kind, id, true
+}
+
+// trashError maps the errors of TrashService to responses.
+func trashError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found in the trash"})
+	case errors.Is(err, services.ErrParentTrashed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// RestoreTrashItem takes a record out of the trash. Restoring an article also
+// restores its category and tags if they were deleted since, so it comes
+// back as it was.
+func (ctl *TrashController) RestoreTrashItem(c *gin.Context) {
+	kind, id, ok := trashItem(c)
+	if !ok {
+		return
+	}
+
+	if err := ctl.trash.Restore(kind, id); err != nil {
+		trashError(c, err)
+		return
+	}
+
+	switch kind {
+	case repository.KindArticle:
+		ctl.related.Invalidate(id)
+	case repository.KindTag, repository.KindCategory:
+		ctl.related.Reset()
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Item restored successfully"})
+}
+
+// PurgeTrashItem permanently deletes a record from the trash.
+func (ctl *TrashController) PurgeTrashItem(c *gin.Context) {
+	kind, id, ok := trashItem(c)
+	if !ok {
+		return
+	}
+
+	if err := ctl.trash.Purge(kind, id); err != nil {
+		trashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item deleted permanently"})
+}
+
+// EmptyTrash permanently deletes everything in the trash, or only the items
+// of ?type=.
+func (ctl *TrashController) EmptyTrash(c *gin.Context) {
+	kinds, err := parseTrashKinds(c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	counts, err := ctl.trash.Empty(kinds, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Trash emptied", "purged": counts})
+}
+
+// StartJanitor purges items that have been in the trash longer than
+// retention, checking every interval.
+func (ctl *TrashController) StartJanitor(retention, interval time.Duration) {
+	go func() {
+		for {
+			counts, err := ctl.trash.Empty(services.TrashKinds, time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Trash purge failed: %v", err)
+			} else if counts[repository.KindArticle]+counts[repository.KindComment]+counts[repository.KindTag]+counts[repository.KindCategory] > 0 {
+				log.Printf("Purged expired trash: %v", counts)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
@@ -1,15 +1,149 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
 )
 
-func UploadFile(c *gin.Context) {
+// UploadDir is where uploaded files are stored and served from.
+const UploadDir = "uploads"
+
+// UploadController serves single-shot and chunked uploads.
+type UploadController struct {
+	media repository.MediaRepository
+}
+
+func NewUploadController(media repository.MediaRepository) *UploadController {
+	return &UploadController{media: media}
+}
+
+// UploadRefPattern matches links to uploaded files, absolute or site relative.
+// The second submatch is the file name in UploadDir.
+var UploadRefPattern = regexp.MustCompile(`(https?://[^\s"'<>()]+?)?/uploads/([A-Za-z0-9._-]+)`)
+
+// imageExtensions are the files single-shot uploads accept.
+var imageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+}
+
+// attachmentExtensions are the large files, such as podcast audio, that are
+// only accepted through chunked uploads and imports, in addition to images.
+var attachmentExtensions = map[string]bool{
+	".pdf": true,
+	".mp3": true,
+	".m4a": true,
+	".mp4": true,
+}
+
+func validateImageExtension(filename string) (string, bool) {
+	extension := strings.ToLower(filepath.Ext(filename))
+	return extension, imageExtensions[extension]
+}
+
+func validateUploadExtension(filename string) (string, bool) {
+	extension := strings.ToLower(filepath.Ext(filename))
+	return extension, imageExtensions[extension] || attachmentExtensions[extension]
+}
+
+func newUploadFilename(extension string) string {
+	return fmt.Sprintf("%d%s", time.Now().UnixNano(), extension)
+}
+
+func uploadURL(filename string) string {
+	// Assuming the server is running on localhost:8080 and serving static files from /uploads
+	return fmt.Sprintf("http://localhost:8080/uploads/%s", filename)
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// recordMedia stores a Media row for a file already saved in UploadDir.
+func recordMedia(repo repository.MediaRepository, userID uint, filename, originalName string, size int64, checksum string) (models.Media, error) {
+	media := models.Media{
+		UserID:       userID,
+		Filename:     filename,
+		OriginalName: filepath.Base(originalName),
+		URL:          uploadURL(filename),
+		Size:         size,
+		MimeType:     mime.TypeByExtension(filepath.Ext(filename)),
+		Checksum:     checksum,
+	}
+	err := repo.Create(&media)
+	return media, err
+}
+
+var errUnsupportedUpload = errors.New("unsupported file type")
+
+// storeUpload saves data in UploadDir as a new upload of userID, unless an
+// upload with the same content exists, in which case that one is returned
+// and created is false. The file type is taken from originalName.
+func storeUpload(repo repository.MediaRepository, userID uint, originalName string, data []byte) (media models.Media, created bool, err error) {
+	extension, ok := validateUploadExtension(originalName)
+	if !ok {
+		return media, false, errUnsupportedUpload
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	existing, err := repo.FindByChecksum(checksum)
+	if err == nil {
+		return *existing, false, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return media, false, err
+	}
+
+	if err := os.MkdirAll(UploadDir, 0o755); err != nil {
+		return media, false, err
+	}
+	filename := newUploadFilename(extension)
+	dst := filepath.Join(UploadDir, filename)
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		return media, false, err
+	}
+	if media, err = recordMedia(repo, userID, filename, originalName, int64(len(data)), checksum); err != nil {
+		os.Remove(dst)
+		return media, false, err
+	}
+	return media, true, nil
+}
+
+func (ctl *UploadController) UploadFile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
 	file, err := c.FormFile("file")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No file is received"})
@@ -17,27 +151,38 @@ func UploadFile(c *gin.Context) {
 	}
 
 	// Validate file extension
-	extension := filepath.Ext(file.Filename)
-	if extension != ".jpg" && extension != ".jpeg" && extension != ".png" && extension != ".gif" {
+	extension, ok := validateImageExtension(file.Filename)
+	if !ok {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Only jpg, jpeg, png, gif are allowed"})
 		return
 	}
 
 	// Generate unique filename
-	filename := fmt.Sprintf("%d%s", time.Now().UnixNano(), extension)
-	dst := filepath.Join("uploads", filename)
+	filename := newUploadFilename(extension)
+	dst := filepath.Join(UploadDir, filename)
 
 	if err := c.SaveUploadedFile(file, dst); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to save file"})
 		return
 	}
 
-	// Return the URL to access the file
-	// Assuming the server is running on localhost:8080 and serving static files from /uploads
-	fileURL := fmt.Sprintf("http://localhost:8080/uploads/%s", filename)
+	checksum, err := fileChecksum(dst)
+	if err != nil {
+		os.Remove(dst)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to save file"})
+		return
+	}
+
+	media, err := recordMedia(ctl.media, userID.(uint), filename, file.Filename, file.Size, checksum)
+	if err != nil {
+		os.Remove(dst)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "File uploaded successfully",
-		"url":     fileURL,
+		"message":  "File uploaded successfully",
+		"url":      media.URL,
+		"media_id": media.ID,
 	})
 }
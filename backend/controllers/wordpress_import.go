@@ -0,0 +1,831 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
+	"github.com/your-username/blog-backend/utils"
+	"gorm.io/gorm"
+)
+
+// WordPress eXtended RSS (WXR) import. Posts and pages become articles,
+// authors and commenters become users, and attachments are stored as uploads.
+// Everything that is left out is listed in the report.
+
+// importedUserPassword is not a bcrypt hash, so nobody can sign in as a user
+// created by an import until a password is set.
+const importedUserPassword = "!"
+
+// wordpressMaxDownload limits the size of a downloaded attachment.
+const wordpressMaxDownload = 100 << 20
+
+// WordPressImportOptions configures ImportWordPress.
+type WordPressImportOptions struct {
+	UploadsDir    string            // Local copy of wp-content/uploads; attachments are downloaded when empty
+	PagesCategory string            // Category for imported pages; pages are skipped when empty
+	AuthorMap     map[string]string // WordPress author login -> username of an existing user
+	Client        *http.Client      // For downloads, http.DefaultClient when nil
+}
+
+// WordPressSkippedItem is an item of the export that was not imported.
+type WordPressSkippedItem struct {
+	Type   string `json:"type"` // post, page, attachment, media, comment, ...
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// WordPressUserMapping records which user an author or commenter of the
+// export was imported as.
+type WordPressUserMapping struct {
+	Type     string `json:"type"`   // author or commenter
+	Source   string `json:"source"` // Author login, or commenter name and email
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Match    string `json:"match"` // mapped, email, author or created
+}
+
+// WordPressImportReport counts what an import created and lists how authors
+// and commenters were mapped and what was skipped.
+type WordPressImportReport struct {
+	Articles   int                    `json:"articles"`
+	Pages      int                    `json:"pages"`
+	Comments   int                    `json:"comments"`
+	Users      int                    `json:"users"`
+	Categories int                    `json:"categories"`
+	Tags       int                    `json:"tags"`
+	Media      int                    `json:"media"`
+	Mappings   []WordPressUserMapping `json:"mappings"`
+	Skipped    []WordPressSkippedItem `json:"skipped"`
+}
+
+type wxrDocument struct {
+	Channel struct {
+		BaseSiteURL string        `xml:"base_site_url"`
+		BaseBlogURL string        `xml:"base_blog_url"`
+		Authors     []wxrAuthor   `xml:"author"`
+		Categories  []wxrCategory `xml:"category"`
+		Tags        []wxrTag      `xml:"tag"`
+		Items       []wxrItem     `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrAuthor struct {
+	ID    string `xml:"author_id"`
+	Login string `xml:"author_login"`
+	Email string `xml:"author_email"`
+}
+
+type wxrCategory struct {
+	Nicename string `xml:"category_nicename"`
+	Parent   string `xml:"category_parent"`
+	Name     string `xml:"cat_name"`
+}
+
+type wxrTag struct {
+	Slug string `xml:"tag_slug"`
+	Name string `xml:"tag_name"`
+}
+
+type wxrItem struct {
+	Title         string       `xml:"title"`
+	PubDate       string       `xml:"pubDate"`
+	Creator       string       `xml:"creator"` // dc:creator, the author login
+	Content       string       `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostID        string       `xml:"post_id"`
+	PostDate      string       `xml:"post_date"`
+	PostDateGMT   string       `xml:"post_date_gmt"`
+	PostName      string       `xml:"post_name"`
+	Status        string       `xml:"status"`
+	PostType      string       `xml:"post_type"`
+	Sticky        string       `xml:"is_sticky"`
+	AttachmentURL string       `xml:"attachment_url"`
+	Terms         []wxrTerm    `xml:"category"`
+	Meta          []wxrMeta    `xml:"postmeta"`
+	Comments      []wxrComment `xml:"comment"`
+}
+
+type wxrTerm struct {
+	Domain   string `xml:"domain,attr"` // category or post_tag
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+type wxrMeta struct {
+	Key   string `xml:"meta_key"`
+	Value string `xml:"meta_value"`
+}
+
+type wxrComment struct {
+	ID          string `xml:"comment_id"`
+	Author      string `xml:"comment_author"`
+	AuthorEmail string `xml:"comment_author_email"`
+	Date        string `xml:"comment_date"`
+	DateGMT     string `xml:"comment_date_gmt"`
+	Content     string `xml:"comment_content"`
+	Approved    string `xml:"comment_approved"` // 1, 0, spam or trash
+	Type        string `xml:"comment_type"`     // Empty or comment for regular comments
+	UserID      string `xml:"comment_user_id"`
+}
+
+var (
+	wordpressUploadRefPattern = regexp.MustCompile(`(?:https?://[^\s"'()<>\[\]]+)?/wp-content/uploads/[^\s"'()<>\[\]]+`)
+	wordpressBlockPattern     = regexp.MustCompile(`(?i)^<(p|div|h[1-6]|ul|ol|li|dl|blockquote|pre|table|figure|hr|form|address|section|!--)[\s>/]`)
+	wordpressPrePattern       = regexp.MustCompile(`(?is)<pre\b.*?</pre>`)
+	wordpressParagraphBreak   = regexp.MustCompile(`\n\s*\n`)
+	wordpressCaptionPattern   = regexp.MustCompile(`(?is)\[caption[^\]]*\](.*?)\[/caption\]`)
+	wordpressEmbedPattern     = regexp.MustCompile(`(?is)\[embed[^\]]*\](.*?)\[/embed\]`)
+)
+
+type wordpressImporter struct {
+	db      *gorm.DB
+	opts    WordPressImportOptions
+	siteURL string
+	author  uint // For posts whose author is not in the export
+	report  *WordPressImportReport
+
+	users       map[string]uint // Author login -> user
+	authorIDs   map[string]uint // WordPress author ID -> user
+	commenters  map[string]uint // Commenter email or name, or "user:" and author ID -> user
+	categories  map[string]uint // Nicename -> category
+	categoryDef map[string]wxrCategory
+	attachments map[string]models.Media // Attachment post ID -> upload
+	media       map[string]models.Media // Source URL -> upload
+	mediaErrors map[string]error
+	mapped      map[string]models.User // AuthorMap with the users looked up
+}
+
+// ImportWordPress imports a WXR file as written by Tools > Export in
+// WordPress into db. Articles whose slug already exists are skipped, so an
+// import can be repeated after fixing the reported problems.
+func ImportWordPress(db *gorm.DB, r io.Reader, opts WordPressImportOptions) (*WordPressImportReport, error) {
+	var doc wxrDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid WXR file: %w", err)
+	}
+	channel := doc.Channel
+	if len(channel.Items) == 0 && len(channel.Authors) == 0 {
+		return nil, errors.New("the file contains no WordPress posts or authors")
+	}
+
+	author, err := siteAuthor(db)
+	if err != nil {
+		return nil, fmt.Errorf("no user to own the imported posts: %w", err)
+	}
+
+	// Check the mapping before anything is created
+	mapped := map[string]models.User{}
+	for login, username := range opts.AuthorMap {
+		var user models.User
+		err := db.Where("username = ?", username).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("author %q is mapped to %q, which does not exist", login, username)
+		}
+		if err != nil {
+			return nil, err
+		}
+		mapped[login] = user
+	}
+
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	imp := &wordpressImporter{
+		db:          db,
+		opts:        opts,
+		siteURL:     strings.TrimRight(firstNonEmpty(channel.BaseBlogURL, channel.BaseSiteURL), "/"),
+		author:      author.ID,
+		report:      &WordPressImportReport{Mappings: []WordPressUserMapping{}, Skipped: []WordPressSkippedItem{}},
+		users:       map[string]uint{},
+		authorIDs:   map[string]uint{},
+		commenters:  map[string]uint{},
+		categories:  map[string]uint{},
+		categoryDef: map[string]wxrCategory{},
+		attachments: map[string]models.Media{},
+		media:       map[string]models.Media{},
+		mediaErrors: map[string]error{},
+		mapped:      mapped,
+	}
+
+	for _, a := range channel.Authors {
+		user, err := imp.mapAuthor(a)
+		if err != nil {
+			return imp.report, err
+		}
+		imp.users[a.Login] = user
+		imp.authorIDs[a.ID] = user
+	}
+
+	for _, c := range channel.Categories {
+		imp.categoryDef[c.Nicename] = c
+	}
+	for _, c := range channel.Categories {
+		if _, err := imp.category(c.Nicename, c.Name, nil); err != nil {
+			return imp.report, err
+		}
+	}
+
+	tagsBefore, err := countRows(db, &models.Tag{})
+	if err != nil {
+		return imp.report, err
+	}
+	tagNames := make([]string, 0, len(channel.Tags))
+	for _, t := range channel.Tags {
+		tagNames = append(tagNames, html.UnescapeString(t.Name))
+	}
+	if _, err := resolveTags(db, tagNames); err != nil {
+		return imp.report, err
+	}
+
+	// Attachments first, so posts can refer to them as featured images
+	for _, item := range channel.Items {
+		if item.PostType != "attachment" {
+			continue
+		}
+		media, err := imp.fetchMedia(item.AttachmentURL, imp.itemAuthor(item))
+		if err != nil {
+			imp.skip("attachment", item.PostID, item.Title, err.Error())
+			continue
+		}
+		imp.attachments[item.PostID] = media
+	}
+
+	for _, item := range channel.Items {
+		if item.PostType == "attachment" {
+			continue
+		}
+		if err := imp.importItem(item); err != nil {
+			return imp.report, err
+		}
+	}
+
+	tagsAfter, err := countRows(db, &models.Tag{})
+	if err != nil {
+		return imp.report, err
+	}
+	imp.report.Tags = int(tagsAfter - tagsBefore)
+	return imp.report, nil
+}
+
+func countRows(tx *gorm.DB, model interface{}) (int64, error) {
+	var count int64
+	err := tx.Model(model).Count(&count).Error
+	return count, err
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (imp *wordpressImporter) skip(kind, id, title, reason string) {
+	imp.report.Skipped = append(imp.report.Skipped, WordPressSkippedItem{
+		Type: kind, ID: id, Title: html.UnescapeString(title), Reason: reason,
+	})
+}
+
+func (imp *wordpressImporter) itemAuthor(item wxrItem) uint {
+	if id, ok := imp.users[item.Creator]; ok {
+		return id
+	}
+	return imp.author
+}
+
+// mapAuthor maps an author of the export to the user named in AuthorMap, or to
+// the user with the same email. Otherwise a user that cannot sign in is
+// created; usernames alone never match, as they say nothing about who owns
+// an account.
+func (imp *wordpressImporter) mapAuthor(a wxrAuthor) (uint, error) {
+	source := strings.TrimSpace(html.UnescapeString(a.Login))
+
+	if user, ok := imp.mapped[a.Login]; ok {
+		imp.mapUser("author", source, user, "mapped")
+		return user.ID, nil
+	}
+
+	if email := strings.TrimSpace(a.Email); email != "" {
+		var user models.User
+		err := imp.db.Where("email = ?", email).First(&user).Error
+		if err == nil {
+			imp.mapUser("author", source, user, "email")
+			return user.ID, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+	}
+
+	user, err := imp.createUser(source, a.Email)
+	if err != nil {
+		return 0, err
+	}
+	imp.mapUser("author", source, user, "created")
+	return user.ID, nil
+}
+
+// createUser creates a user that cannot sign in. The username gets a
+// number when it is taken, and the email is replaced when it is empty or
+// belongs to another account.
+func (imp *wordpressImporter) createUser(username, email string) (models.User, error) {
+	email = strings.TrimSpace(email)
+
+	base := username
+	if base == "" {
+		base = "anonymous"
+	}
+	name := base
+	for i := 2; ; i++ {
+		var count int64
+		if err := imp.db.Model(&models.User{}).Where("username = ?", name).Count(&count).Error; err != nil {
+			return models.User{}, err
+		}
+		if count == 0 {
+			break
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	if email != "" {
+		var count int64
+		if err := imp.db.Model(&models.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+			return models.User{}, err
+		}
+		if count > 0 {
+			email = ""
+		}
+	}
+	if email == "" {
+		// Email is required and unique; .invalid never resolves
+		local := utils.Slugify(name)
+		if local == "" {
+			local = "user"
+		}
+		email = fmt.Sprintf("%s-%d@wordpress.invalid", local, time.Now().UnixNano())
+	}
+
+	user := models.User{Username: name, Email: email, Password: importedUserPassword}
+	if err := imp.db.Create(&user).Error; err != nil {
+		return models.User{}, err
+	}
+	imp.report.Users++
+	return user, nil
+}
+
+func (imp *wordpressImporter) mapUser(kind, source string, user models.User, match string) {
+	imp.report.Mappings = append(imp.report.Mappings, WordPressUserMapping{
+		Type: kind, Source: source, UserID: user.ID, Username: user.Username, Match: match,
+	})
+}
+
+// category resolves a category by nicename, creating it and its parents as
+// needed. Existing categories are matched by slug or name.
+func (imp *wordpressImporter) category(nicename, name string, visiting map[string]bool) (uint, error) {
+	if id, ok := imp.categories[nicename]; ok {
+		return id, nil
+	}
+	if def, ok := imp.categoryDef[nicename]; ok {
+		name = def.Name
+	}
+	name = strings.TrimSpace(html.UnescapeString(name))
+	if name == "" {
+		name = nicename
+	}
+
+	var category models.Category
+	err := imp.db.Where("slug = ? OR LOWER(name) = LOWER(?)", nicename, name).First(&category).Error
+	if err == nil {
+		imp.categories[nicename] = category.ID
+		return category.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	// A deleted category of that name comes back out of the trash
+	trashedID, err := restoreTrashedCategory(imp.db, name)
+	if err != nil {
+		return 0, err
+	}
+	if trashedID != 0 {
+		imp.categories[nicename] = trashedID
+		return trashedID, nil
+	}
+
+	category = models.Category{Name: name}
+	if parent := imp.categoryDef[nicename].Parent; parent != "" && !visiting[nicename] {
+		if visiting == nil {
+			visiting = map[string]bool{}
+		}
+		visiting[nicename] = true
+		parentID, err := imp.category(parent, parent, visiting)
+		if err != nil {
+			return 0, err
+		}
+		category.ParentID = &parentID
+	}
+
+	slugSource := nicename
+	if unescaped, err := url.PathUnescape(nicename); err == nil {
+		slugSource = unescaped
+	}
+	if category.Slug, err = database.UniqueSlug(imp.db, &models.Category{}, firstNonEmpty(slugSource, name), 0); err != nil {
+		return 0, err
+	}
+	if err := imp.db.Create(&category).Error; err != nil {
+		return 0, err
+	}
+	imp.report.Categories++
+	imp.categories[nicename] = category.ID
+	return category.ID, nil
+}
+
+// fetchMedia copies an upload from UploadsDir or downloads it, and stores it
+// in the upload directory. Failures are remembered so every URL is only
+// tried once.
+func (imp *wordpressImporter) fetchMedia(source string, userID uint) (models.Media, error) {
+	if media, ok := imp.media[source]; ok {
+		return media, nil
+	}
+	if err, ok := imp.mediaErrors[source]; ok {
+		return models.Media{}, err
+	}
+
+	media, err := imp.storeMedia(source, userID)
+	if err != nil {
+		imp.mediaErrors[source] = err
+		return media, err
+	}
+	imp.media[source] = media
+	return media, nil
+}
+
+func (imp *wordpressImporter) storeMedia(source string, userID uint) (models.Media, error) {
+	u, err := url.Parse(source)
+	if err != nil || source == "" {
+		return models.Media{}, fmt.Errorf("invalid URL %q", source)
+	}
+	if !u.IsAbs() && imp.siteURL != "" {
+		if base, err := url.Parse(imp.siteURL + "/"); err == nil {
+			u = base.ResolveReference(u)
+		}
+	}
+	name := path.Base(u.Path)
+	if _, ok := validateUploadExtension(name); !ok {
+		return models.Media{}, errUnsupportedUpload
+	}
+
+	var data []byte
+	if imp.opts.UploadsDir != "" {
+		i := strings.Index(u.Path, "/wp-content/uploads/")
+		rel := ""
+		if i >= 0 {
+			rel = u.Path[i+len("/wp-content/uploads/"):]
+		}
+		if !fs.ValidPath(rel) || rel == "" {
+			return models.Media{}, errors.New("not in wp-content/uploads")
+		}
+		if data, err = os.ReadFile(filepath.Join(imp.opts.UploadsDir, filepath.FromSlash(rel))); err != nil {
+			return models.Media{}, err
+		}
+	} else {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return models.Media{}, fmt.Errorf("cannot download %q", source)
+		}
+		resp, err := imp.opts.Client.Get(u.String())
+		if err != nil {
+			return models.Media{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return models.Media{}, fmt.Errorf("download failed: %s", resp.Status)
+		}
+		if data, err = io.ReadAll(io.LimitReader(resp.Body, wordpressMaxDownload+1)); err != nil {
+			return models.Media{}, err
+		}
+		if len(data) > wordpressMaxDownload {
+			return models.Media{}, errors.New("file is too large")
+		}
+	}
+
+	media, created, err := storeUpload(repository.NewStore(imp.db).Media(), userID, name, data)
+	if created {
+		imp.report.Media++
+	}
+	return media, err
+}
+
+// rewriteMedia stores the uploads a post links to, including resized
+// variants that are not attachments of their own, and points the links at
+// the stored copies.
+func (imp *wordpressImporter) rewriteMedia(content string, item wxrItem, userID uint) string {
+	return wordpressUploadRefPattern.ReplaceAllStringFunc(content, func(ref string) string {
+		_, known := imp.mediaErrors[ref]
+		media, err := imp.fetchMedia(ref, userID)
+		if err != nil {
+			if !known {
+				imp.skip("media", ref, item.Title, err.Error())
+			}
+			return ref
+		}
+		return media.URL
+	})
+}
+
+// wordpressContentHTML turns stored post content into plain HTML: shortcodes
+// that only wrap content are unwrapped and classic editor paragraphs, which
+// are separated by blank lines, become <p> elements as WordPress renders
+// them.
+func wordpressContentHTML(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = wordpressCaptionPattern.ReplaceAllString(content, "$1")
+	content = wordpressEmbedPattern.ReplaceAllString(content, `<a href="$1">$1</a>`)
+
+	// Blank lines inside <pre> are part of the code. The placeholders are
+	// comments, which wordpressBlockPattern keeps out of paragraphs.
+	var pres []string
+	content = wordpressPrePattern.ReplaceAllStringFunc(content, func(pre string) string {
+		pres = append(pres, pre)
+		return fmt.Sprintf("\n\n<!-- wordpress-pre-%d -->\n\n", len(pres)-1)
+	})
+
+	var out strings.Builder
+	for _, block := range wordpressParagraphBreak.Split(content, -1) {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		if wordpressBlockPattern.MatchString(block) {
+			out.WriteString(block)
+		} else {
+			out.WriteString("<p>" + strings.ReplaceAll(block, "\n", "<br>\n") + "</p>")
+		}
+		out.WriteString("\n\n")
+	}
+
+	result := out.String()
+	for i, pre := range pres {
+		result = strings.Replace(result, fmt.Sprintf("<!-- wordpress-pre-%d -->", i), pre, 1)
+	}
+	return result
+}
+
+// wordpressTime parses a WXR date, preferring the GMT variant. Unset dates
+// are written as 0000-00-00 00:00:00.
+func wordpressTime(gmt, local string) time.Time {
+	const layout = "2006-01-02 15:04:05"
+	if t, err := time.Parse(layout, gmt); err == nil && t.Year() > 1 {
+		return t
+	}
+	if t, err := time.ParseInLocation(layout, local, time.Local); err == nil && t.Year() > 1 {
+		return t
+	}
+	return time.Time{}
+}
+
+func (imp *wordpressImporter) importItem(item wxrItem) error {
+	kind := item.PostType
+	if kind != "post" && kind != "page" {
+		imp.skip(kind, item.PostID, item.Title, "unsupported post type")
+		return nil
+	}
+
+	draft := false
+	switch item.Status {
+	case "publish":
+	case "draft", "pending", "private", "future":
+		draft = true
+	default:
+		imp.skip(kind, item.PostID, item.Title, fmt.Sprintf("status %q", item.Status))
+		return nil
+	}
+	if kind == "page" && imp.opts.PagesCategory == "" {
+		imp.skip(kind, item.PostID, item.Title, "pages are not imported")
+		return nil
+	}
+
+	title := strings.TrimSpace(html.UnescapeString(item.Title))
+	if title == "" {
+		title = "Untitled"
+	}
+	slugSource := item.PostName
+	if unescaped, err := url.PathUnescape(item.PostName); err == nil {
+		slugSource = unescaped
+	}
+	slug := utils.Slugify(firstNonEmpty(slugSource, title))
+	if slug == "" || utils.NumericSlug(slug) {
+		slug = "post-" + firstNonEmpty(slug, item.PostID)
+	}
+	taken, err := slugTaken(imp.db, &models.Article{}, slug, 0)
+	if err != nil {
+		return err
+	}
+	if taken {
+		imp.skip(kind, item.PostID, item.Title, fmt.Sprintf("an article with slug %q already exists", slug))
+		return nil
+	}
+
+	authorID := imp.itemAuthor(item)
+	body := wordpressContentHTML(imp.rewriteMedia(item.Content, item, authorID))
+	content, err := utils.HTMLToMarkdown(body, imp.siteURL)
+	if err != nil {
+		imp.skip(kind, item.PostID, item.Title, "cannot convert content: "+err.Error())
+		return nil
+	}
+	if content == "" {
+		content = " " // Content is required
+	}
+
+	published := wordpressTime(item.PostDateGMT, item.PostDate)
+	if published.IsZero() {
+		if t, err := time.Parse(time.RFC1123Z, item.PubDate); err == nil {
+			published = t
+		} else {
+			published = time.Now()
+		}
+	}
+
+	article := models.Article{
+		Title:    title,
+		Slug:     slug,
+		AuthorID: authorID,
+		Draft:    draft,
+		Pinned:   item.Sticky == "1",
+	}
+	article.CreatedAt = published
+	article.UpdatedAt = published
+
+	if kind == "page" {
+		category, err := findOrCreateCategory(imp.db, imp.opts.PagesCategory)
+		if err != nil {
+			return err
+		}
+		article.CategoryID = &category.ID
+	}
+
+	var tagNames []string
+	for _, term := range item.Terms {
+		switch term.Domain {
+		case "category":
+			if article.CategoryID == nil {
+				id, err := imp.category(term.Nicename, term.Name, nil)
+				if err != nil {
+					return err
+				}
+				article.CategoryID = &id
+			}
+		case "post_tag":
+			tagNames = append(tagNames, html.UnescapeString(term.Name))
+		}
+	}
+	for _, meta := range item.Meta {
+		if meta.Key != "_thumbnail_id" {
+			continue
+		}
+		if media, ok := imp.attachments[meta.Value]; ok {
+			article.CoverMediaID = &media.ID
+			article.CoverURL = media.URL
+		}
+	}
+
+	if err := services.SetArticleContent(&article, content); err != nil {
+		imp.skip(kind, item.PostID, item.Title, "cannot render content: "+err.Error())
+		return nil
+	}
+
+	comments, err := imp.comments(item)
+	if err != nil {
+		return err
+	}
+
+	err = imp.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if article.Tags, err = resolveTags(tx, tagNames); err != nil {
+			return err
+		}
+		if err := tx.Create(&article).Error; err != nil {
+			return err
+		}
+		for i := range comments {
+			comments[i].ArticleID = article.ID
+			if err := tx.Create(&comments[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if kind == "page" {
+		imp.report.Pages++
+	} else {
+		imp.report.Articles++
+	}
+	imp.report.Comments += len(comments)
+	return nil
+}
+
+// comments converts the approved comments of a post. Replies are imported
+// as top level comments since comments are not threaded here.
+func (imp *wordpressImporter) comments(item wxrItem) ([]models.Comment, error) {
+	var comments []models.Comment
+	for _, wc := range item.Comments {
+		title := fmt.Sprintf("on %s", html.UnescapeString(item.Title))
+		if wc.Type != "" && wc.Type != "comment" {
+			imp.skip("comment", wc.ID, title, wc.Type)
+			continue
+		}
+		if wc.Approved != "1" {
+			imp.skip("comment", wc.ID, title, fmt.Sprintf("not approved (%s)", wc.Approved))
+			continue
+		}
+
+		userID, err := imp.commenter(wc)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := utils.HTMLToMarkdown(wordpressContentHTML(wc.Content), imp.siteURL)
+		if err == nil && content == "" {
+			err = errors.New("empty comment")
+		}
+		if err != nil {
+			imp.skip("comment", wc.ID, title, err.Error())
+			continue
+		}
+		contentHTML, err := utils.RenderCommentMarkdown(content)
+		if err != nil {
+			imp.skip("comment", wc.ID, title, err.Error())
+			continue
+		}
+
+		comment := models.Comment{Content: content, ContentHTML: contentHTML, UserID: userID}
+		if created := wordpressTime(wc.DateGMT, wc.Date); !created.IsZero() {
+			comment.CreatedAt = created
+			comment.UpdatedAt = created
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// commenter maps a comment to the author account it was written with, or
+// to a new user per commenter email (or name, without one). Commenters are
+// never matched with existing accounts, since anyone can comment with any
+// email.
+func (imp *wordpressImporter) commenter(wc wxrComment) (uint, error) {
+	name := strings.TrimSpace(html.UnescapeString(wc.Author))
+	source := name
+	if email := strings.TrimSpace(wc.AuthorEmail); email != "" {
+		source += " <" + email + ">"
+	}
+
+	if id, ok := imp.authorIDs[wc.UserID]; ok && wc.UserID != "0" {
+		key := "user:" + wc.UserID
+		if _, ok := imp.commenters[key]; !ok {
+			var user models.User
+			if err := imp.db.First(&user, id).Error; err != nil {
+				return 0, err
+			}
+			imp.commenters[key] = id
+			imp.mapUser("commenter", source, user, "author")
+		}
+		return id, nil
+	}
+
+	key := strings.ToLower(strings.TrimSpace(wc.AuthorEmail))
+	if key == "" {
+		key = "name:" + name
+	}
+	if id, ok := imp.commenters[key]; ok {
+		return id, nil
+	}
+
+	user, err := imp.createUser(name, wc.AuthorEmail)
+	if err != nil {
+		return 0, err
+	}
+	imp.commenters[key] = user.ID
+	imp.mapUser("commenter", source, user, "created")
+	return user.ID, nil
+}
@@ -0,0 +1,307 @@
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+)
+
+const wxrSample = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<wp:base_site_url>https://old.example.com</wp:base_site_url>
+	<wp:base_blog_url>https://old.example.com</wp:base_blog_url>
+	<wp:author><wp:author_id>1</wp:author_id><wp:author_login>admin</wp:author_login><wp:author_email>admin@example.com</wp:author_email></wp:author>
+	<wp:author><wp:author_id>2</wp:author_id><wp:author_login>jane</wp:author_login><wp:author_email>jane@example.com</wp:author_email></wp:author>
+	<wp:category><wp:category_nicename>tech</wp:category_nicename><wp:category_parent></wp:category_parent><wp:cat_name><![CDATA[Tech]]></wp:cat_name></wp:category>
+	<wp:category><wp:category_nicename>go</wp:category_nicename><wp:category_parent>tech</wp:category_parent><wp:cat_name><![CDATA[Go &amp; Friends]]></wp:cat_name></wp:category>
+	<wp:tag><wp:tag_slug>unused</wp:tag_slug><wp:tag_name><![CDATA[Unused]]></wp:tag_name></wp:tag>
+	<item>
+		<title>pic</title>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<wp:post_id>10</wp:post_id>
+		<wp:post_type>attachment</wp:post_type>
+		<wp:status>inherit</wp:status>
+		<wp:attachment_url>https://old.example.com/wp-content/uploads/2024/01/pic.png</wp:attachment_url>
+	</item>
+	<item>
+		<title>Hello &amp; welcome</title>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[First paragraph
+second line
+
+[caption id="x"]<img src="https://old.example.com/wp-content/uploads/2024/01/pic.png" />[/caption]
+
+<img src="https://old.example.com/wp-content/uploads/2024/01/pic-300x200.png" />]]></content:encoded>
+		<wp:post_id>11</wp:post_id>
+		<wp:post_date>2024-01-02 10:00:00</wp:post_date>
+		<wp:post_date_gmt>2024-01-02 09:00:00</wp:post_date_gmt>
+		<wp:post_name>hello-welcome</wp:post_name>
+		<wp:status>publish</wp:status>
+		<wp:post_type>post</wp:post_type>
+		<wp:is_sticky>1</wp:is_sticky>
+		<category domain="category" nicename="go"><![CDATA[Go &amp; Friends]]></category>
+		<category domain="post_tag" nicename="golang"><![CDATA[Golang]]></category>
+		<wp:postmeta><wp:meta_key>_thumbnail_id</wp:meta_key><wp:meta_value>10</wp:meta_value></wp:postmeta>
+		<wp:comment>
+			<wp:comment_id>1</wp:comment_id>
+			<wp:comment_author><![CDATA[Visitor]]></wp:comment_author>
+			<wp:comment_author_email>visitor@example.com</wp:comment_author_email>
+			<wp:comment_date_gmt>2024-01-03 08:00:00</wp:comment_date_gmt>
+			<wp:comment_content><![CDATA[Nice post]]></wp:comment_content>
+			<wp:comment_approved>1</wp:comment_approved>
+			<wp:comment_type></wp:comment_type>
+			<wp:comment_user_id>0</wp:comment_user_id>
+		</wp:comment>
+		<wp:comment>
+			<wp:comment_id>2</wp:comment_id>
+			<wp:comment_author><![CDATA[jane]]></wp:comment_author>
+			<wp:comment_content><![CDATA[Thanks]]></wp:comment_content>
+			<wp:comment_approved>1</wp:comment_approved>
+			<wp:comment_type>comment</wp:comment_type>
+			<wp:comment_user_id>2</wp:comment_user_id>
+		</wp:comment>
+		<wp:comment>
+			<wp:comment_id>3</wp:comment_id>
+			<wp:comment_author><![CDATA[Spammer]]></wp:comment_author>
+			<wp:comment_content><![CDATA[Buy now]]></wp:comment_content>
+			<wp:comment_approved>spam</wp:comment_approved>
+			<wp:comment_user_id>0</wp:comment_user_id>
+		</wp:comment>
+		<wp:comment>
+			<wp:comment_id>4</wp:comment_id>
+			<wp:comment_author><![CDATA[Other blog]]></wp:comment_author>
+			<wp:comment_content><![CDATA[Linked]]></wp:comment_content>
+			<wp:comment_approved>1</wp:comment_approved>
+			<wp:comment_type>pingback</wp:comment_type>
+			<wp:comment_user_id>0</wp:comment_user_id>
+		</wp:comment>
+	</item>
+	<item>
+		<title>Work in progress</title>
+		<dc:creator><![CDATA[admin]]></dc:creator>
+		<content:encoded><![CDATA[<p>Later</p>]]></content:encoded>
+		<wp:post_id>12</wp:post_id>
+		<wp:post_date_gmt>0000-00-00 00:00:00</wp:post_date_gmt>
+		<wp:post_name></wp:post_name>
+		<wp:status>draft</wp:status>
+		<wp:post_type>post</wp:post_type>
+	</item>
+	<item>
+		<title>About</title>
+		<dc:creator><![CDATA[admin]]></dc:creator>
+		<content:encoded><![CDATA[About me]]></content:encoded>
+		<wp:post_id>13</wp:post_id>
+		<wp:post_name>about</wp:post_name>
+		<wp:status>publish</wp:status>
+		<wp:post_type>page</wp:post_type>
+	</item>
+	<item>
+		<title>Gone</title>
+		<wp:post_id>14</wp:post_id>
+		<wp:post_name>gone</wp:post_name>
+		<wp:status>trash</wp:status>
+		<wp:post_type>post</wp:post_type>
+	</item>
+	<item>
+		<title>Menu</title>
+		<wp:post_id>15</wp:post_id>
+		<wp:status>publish</wp:status>
+		<wp:post_type>nav_menu_item</wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+// wordpressUploads writes pic.png into a copy of wp-content/uploads.
+func wordpressUploads(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "uploads")
+	if err := os.MkdirAll(filepath.Join(dir, "2024", "01"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2024", "01", "pic.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestImportWordPress(t *testing.T) {
+	db := openTestDB(t)
+	opts := WordPressImportOptions{UploadsDir: wordpressUploads(t), PagesCategory: "Pages"}
+
+	report, err := ImportWordPress(db, strings.NewReader(wxrSample), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := []struct {
+		name      string
+		got, want int
+	}{
+		{"articles", report.Articles, 2},
+		{"pages", report.Pages, 1},
+		{"comments", report.Comments, 2},
+		{"users", report.Users, 2}, // jane and the visitor
+		{"categories", report.Categories, 2},
+		{"tags", report.Tags, 2}, // Unused and Golang
+		{"media", report.Media, 1},
+	}
+	for _, c := range counts {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+
+	matches := map[string]string{}
+	for _, m := range report.Mappings {
+		matches[m.Type+" "+m.Source] = m.Match
+	}
+	wantMatches := map[string]string{
+		"author admin": "email",
+		"author jane":  "created",
+		"commenter Visitor <visitor@example.com>": "created",
+		"commenter jane": "author",
+	}
+	for source, match := range wantMatches {
+		if matches[source] != match {
+			t.Errorf("%s matched by %q, want %q (mappings %+v)", source, matches[source], match, report.Mappings)
+		}
+	}
+
+	skipped := map[string]string{}
+	for _, s := range report.Skipped {
+		skipped[s.Type+" "+s.ID] = s.Reason
+	}
+	for _, key := range []string{
+		"post 14", "nav_menu_item 15", "comment 3", "comment 4",
+		"media https://old.example.com/wp-content/uploads/2024/01/pic-300x200.png",
+	} {
+		if _, ok := skipped[key]; !ok {
+			t.Errorf("%s is not reported as skipped (skipped %+v)", key, report.Skipped)
+		}
+	}
+
+	hello := findArticle(t, db, "hello-welcome")
+	if hello.Title != "Hello & welcome" || hello.Draft || !hello.Pinned {
+		t.Errorf("hello = %q draft=%v pinned=%v", hello.Title, hello.Draft, hello.Pinned)
+	}
+	if hello.Category.Name != "Go & Friends" || hello.Category.ParentID == nil {
+		t.Errorf("category = %+v, want Go & Friends below Tech", hello.Category)
+	}
+	if len(hello.Tags) != 1 || hello.Tags[0].Name != "Golang" {
+		t.Errorf("tags = %+v", hello.Tags)
+	}
+	if !hello.CreatedAt.Equal(time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("created at %v, want the GMT post date", hello.CreatedAt)
+	}
+	if hello.CoverMediaID == nil || !strings.Contains(hello.Content, hello.CoverURL) || strings.Contains(hello.Content, "old.example.com/wp-content/uploads/2024/01/pic.png") {
+		t.Errorf("cover %q and content %q, want the attachment stored and linked", hello.CoverURL, hello.Content)
+	}
+	if !strings.Contains(hello.Content, "pic-300x200.png") {
+		t.Errorf("the missing variant should keep its link: %q", hello.Content)
+	}
+
+	draft := findArticle(t, db, "work-in-progress")
+	if !draft.Draft {
+		t.Error("the draft is published")
+	}
+	about := findArticle(t, db, "about")
+	if about.Category.Name != "Pages" {
+		t.Errorf("page category = %q, want Pages", about.Category.Name)
+	}
+
+	var commentCount int64
+	db.Model(&models.Comment{}).Where("article_id = ?", hello.ID).Count(&commentCount)
+	if commentCount != 2 {
+		t.Errorf("%d comments, want 2", commentCount)
+	}
+
+	// Imported slugs are skipped on a second run
+	again, err := ImportWordPress(db, strings.NewReader(wxrSample), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Articles != 0 || again.Pages != 0 || again.Users != 0 || again.Media != 0 {
+		t.Errorf("second import = %+v, want nothing new", again)
+	}
+}
+
+func TestImportWordPressInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		opts WordPressImportOptions
+		want string
+	}{
+		{"not xml", "{}", WordPressImportOptions{}, "invalid WXR file"},
+		{"empty channel", "<rss><channel></channel></rss>", WordPressImportOptions{}, "no WordPress posts or authors"},
+		{"unknown mapped user", wxrSample, WordPressImportOptions{AuthorMap: map[string]string{"jane": "nobody"}}, `mapped to "nobody"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			_, err := ImportWordPress(db, strings.NewReader(tt.doc), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want one containing %q", err, tt.want)
+			}
+			var count int64
+			db.Model(&models.Article{}).Count(&count)
+			if count != 0 {
+				t.Errorf("%d articles were imported", count)
+			}
+		})
+	}
+}
+
+func TestWordPressContentHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"paragraphs", "One\ntwo\n\nThree", "<p>One<br>\ntwo</p>\n\n<p>Three</p>\n\n"},
+		{"block elements", "<h2>Title</h2>\n\n<ul><li>x</li></ul>", "<h2>Title</h2>\n\n<ul><li>x</li></ul>\n\n"},
+		{"caption", `[caption id="a" align="left"]<img src="a.png"> A[/caption]`, `<p><img src="a.png"> A</p>` + "\n\n"},
+		{"embed", "[embed]https://youtu.be/x[/embed]", `<p><a href="https://youtu.be/x">https://youtu.be/x</a></p>` + "\n\n"},
+		{"pre keeps blank lines", "<pre>a\n\nb</pre>\n\nText", "<pre>a\n\nb</pre>\n\n<p>Text</p>\n\n"},
+		{"windows line endings", "One\r\n\r\nTwo", "<p>One</p>\n\n<p>Two</p>\n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wordpressContentHTML(tt.content); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordPressTime(t *testing.T) {
+	tests := []struct {
+		name       string
+		gmt, local string
+		want       time.Time
+	}{
+		{"gmt", "2024-01-02 09:00:00", "2024-01-02 10:00:00", time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"local only", "0000-00-00 00:00:00", "2024-01-02 10:00:00", time.Date(2024, 1, 2, 10, 0, 0, 0, time.Local)},
+		{"unset", "0000-00-00 00:00:00", "0000-00-00 00:00:00", time.Time{}},
+		{"garbage", "yesterday", "", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wordpressTime(tt.gmt, tt.local); !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,33 +3,249 @@ package database
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/glebarez/sqlite"
 	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/migrations"
 	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/utils"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
+// Dialector returns the gorm dialector for DB_DRIVER: mysql (the default),
+// postgres or sqlite. SQLite uses a pure-Go driver, so no database server or
+// cgo is needed.
+func Dialector(cfg *config.Config) (gorm.Dialector, error) {
+	port := func(def string) string {
+		if cfg.DBPort != "" {
+			return cfg.DBPort
+		}
+		return def
+	}
+
+	switch strings.ToLower(cfg.DBDriver) {
+	case "", "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.DBUsername,
+			cfg.DBPassword,
+			net.JoinHostPort(cfg.DBHost, port("3306")),
+			cfg.DBName,
+		)
+		return mysql.Open(dsn), nil
+	case "postgres", "postgresql":
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.DBUsername, cfg.DBPassword),
+			Host:     net.JoinHostPort(cfg.DBHost, port("5432")),
+			Path:     "/" + cfg.DBName,
+			RawQuery: url.Values{"sslmode": {cfg.DBSSLMode}}.Encode(),
+		}
+		return postgres.Open(dsn.String()), nil
+	case "sqlite", "sqlite3":
+		// Enforce foreign keys like the server databases do and wait for
+		// locks instead of failing when requests write concurrently.
+		dsn := cfg.DBPath + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q (available: mysql, postgres, sqlite)", cfg.DBDriver)
+	}
+}
+
 func ConnectDB() {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.AppConfig.DBUsername,
-		config.AppConfig.DBPassword,
-		config.AppConfig.DBHost,
-		config.AppConfig.DBPort,
-		config.AppConfig.DBName,
-	)
-
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	dialector, err := Dialector(config.AppConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	DB, err = gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
 		log.Fatal("Failed to connect to database: ", err)
 	}
 
-	log.Println("Database connected successfully")
+	log.Printf("Database connected successfully (%s)", DB.Dialector.Name())
+}
+
+// AutoMigrate creates and alters the tables straight from the models. It is
+// only meant for development (DB_AUTO_MIGRATE=true); production databases
+// are changed by the versioned migrations.
+func AutoMigrate() error {
+	return DB.AutoMigrate(&models.User{}, &models.Article{}, &models.Comment{}, &models.Category{}, &models.Tag{}, &models.TagAlias{}, &models.UploadSession{}, &models.Media{}, &models.Series{}, &models.ArticleEvent{}, &models.PageView{}, &models.HourlyPageStat{}, &models.DailyPageStat{})
+}
+
+// PrepareSchema makes sure the schema is current before the blog uses the
+// database: it refuses to continue while migrations are pending, unless
+// DB_AUTO_MIGRATE is set.
+func PrepareSchema() {
+	if config.AppConfig.DBAutoMigrate {
+		log.Println("DB_AUTO_MIGRATE is set, updating the schema with AutoMigrate")
+		if err := AutoMigrate(); err != nil {
+			log.Fatal("Failed to migrate database: ", err)
+		}
+	} else {
+		pending, err := migrations.Pending(DB)
+		if err != nil {
+			log.Fatal("Failed to check migrations: ", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("The database schema is out of date (%d pending migrations, the first is %d_%s); run \"blog migrate up\"",
+				len(pending), pending[0].Version, pending[0].Name)
+		}
+	}
+
+	if err := backfillSlugs(); err != nil {
+		log.Fatal("Failed to backfill slugs: ", err)
+	}
+	if err := backfillRendered(); err != nil {
+		log.Fatal("Failed to render stored Markdown: ", err)
+	}
+}
+
+// backfillRendered renders the Markdown of articles and comments saved
+// before content_html existed, including those in the trash, so requests
+// only ever read it. Documents that fail to render are logged and left
+// empty.
+func backfillRendered() error {
+	var articles []models.Article
+	err := DB.Unscoped().Select("id", "content").
+		Where("(content_html = '' OR content_html IS NULL) AND content <> ''").
+		FindInBatches(&articles, 100, func(batch *gorm.DB, _ int) error {
+			for _, article := range articles {
+				contentHTML, err := utils.RenderArticleMarkdown(article.Content)
+				if err != nil {
+					log.Printf("Cannot render article %d: %v", article.ID, err)
+					continue
+				}
+				meta := utils.AnalyzeMarkdown(article.Content)
+				err = DB.Unscoped().Model(&article).UpdateColumns(map[string]interface{}{
+					"content_html": contentHTML,
+					"toc":          meta.TOCJSON(),
+					"word_count":   meta.WordCount,
+					"reading_time": meta.ReadingTime,
+					"excerpt":      meta.Excerpt,
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	var comments []models.Comment
+	return DB.Unscoped().Select("id", "content").
+		Where("(content_html = '' OR content_html IS NULL) AND content <> ''").
+		FindInBatches(&comments, 100, func(batch *gorm.DB, _ int) error {
+			for _, comment := range comments {
+				contentHTML, err := utils.RenderCommentMarkdown(comment.Content)
+				if err != nil {
+					log.Printf("Cannot render comment %d: %v", comment.ID, err)
+					continue
+				}
+				if err := DB.Unscoped().Model(&comment).UpdateColumn("content_html", contentHTML).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// backfillSlugs gives categories, tags and articles created before slugs
+// existed one derived from their name or title.
+func backfillSlugs() error {
+	var categories []models.Category
+	if err := DB.Where("slug = '' OR slug IS NULL").Find(&categories).Error; err != nil {
+		return err
+	}
+	for _, category := range categories {
+		slug, err := UniqueSlug(DB, &models.Category{}, category.Name, category.ID)
+		if err != nil {
+			return err
+		}
+		if err := DB.Model(&category).UpdateColumn("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+
+	var tags []models.Tag
+	if err := DB.Where("slug = '' OR slug IS NULL").Find(&tags).Error; err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		slug, err := UniqueSlug(DB, &models.Tag{}, tag.Name, tag.ID)
+		if err != nil {
+			return err
+		}
+		if err := DB.Model(&tag).UpdateColumn("slug", slug).Error; err != nil {
+			return err
+		}
+	}
 
-	if err := DB.AutoMigrate(&models.User{}, &models.Article{}, &models.Comment{}, &models.Category{}, &models.Tag{}); err != nil {
-		log.Fatal("Failed to migrate database: ", err)
+	var articles []models.Article
+	if err := DB.Select("id", "title").Where("slug = '' OR slug IS NULL").Find(&articles).Error; err != nil {
+		return err
+	}
+	for _, article := range articles {
+		slug, err := UniqueSlug(DB, &models.Article{}, article.Title, article.ID)
+		if err != nil {
+			return err
+		}
+		if err := DB.Model(&article).UpdateColumn("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UniqueSlug derives a slug from name that is unused in model's table,
+// appending -2, -3, ... on collisions. excludeID is the row being updated.
+// Rows in the trash keep their slug, and purely numeric slugs get an "item-"
+// prefix so they are not mistaken for IDs.
+func UniqueSlug(tx *gorm.DB, model interface{}, name string, excludeID uint) (string, error) {
+	base := utils.Slugify(name)
+	if base == "" {
+		base = "item"
+	} else if utils.NumericSlug(base) {
+		base = "item-" + base
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		var count int64
+		if err := tx.Unscoped().Model(model).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// Restore takes a soft-deleted row of model out of the trash and returns its
+// slug. The slug is derived from name again when a live row took it in the
+// meantime. The row counts as updated, so feeds and sitemaps pick it up.
+func Restore(tx *gorm.DB, model interface{}, id uint, name, slug string) (string, error) {
+	var count int64
+	if err := tx.Model(model).Where("slug = ? AND id <> ?", slug, id).Count(&count).Error; err != nil {
+		return "", err
+	}
+	if slug == "" || count > 0 {
+		var err error
+		if slug, err = UniqueSlug(tx, model, name, id); err != nil {
+			return "", err
+		}
 	}
+	err := tx.Unscoped().Model(model).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"slug": slug, "deleted_at": nil, "updated_at": time.Now()}).Error
+	return slug, err
 }
@@ -0,0 +1,26 @@
+package database
+
+import (
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapes LIKE wildcards with "!", which unlike the backslash
+// needs no quoting on any driver. SQLite has no default escape character, so
+// conditions spell it out with ESCAPE '!'.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// Contains matches rows where any of columns contains s as a literal
+// substring. It compares lowercased values because LIKE is case sensitive on
+// PostgreSQL but not on MySQL or SQLite.
+func Contains(s string, columns ...string) clause.Expr {
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(s)) + "%"
+	conds := make([]string, len(columns))
+	vars := make([]interface{}, len(columns))
+	for i, column := range columns {
+		conds[i] = "LOWER(" + column + ") LIKE ? ESCAPE '!'"
+		vars[i] = pattern
+	}
+	return clause.Expr{SQL: "(" + strings.Join(conds, " OR ") + ")", Vars: vars}
+}
@@ -0,0 +1,595 @@
+// Package export renders the blog into a directory tree that can be published
+// to any static file host.
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/config"
+	"github.com/your-username/blog-backend/controllers"
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/routes"
+	"github.com/your-username/blog-backend/services"
+	"github.com/your-username/blog-backend/templates"
+	"gorm.io/gorm"
+)
+
+// manifestName is the file in the output directory that records what the
+// previous export wrote, so the next run only rewrites changed files.
+const manifestName = ".export-manifest.json"
+
+var feedFiles = []string{"feed.xml", "atom.xml", "feed.json"}
+
+type manifest struct {
+	Version string              `json:"version"` // Hash of the templates and settings the pages were rendered with
+	Files   map[string]string   `json:"files"`   // Output path -> content hash
+	Pages   map[string]pageInfo `json:"pages"`   // Output path -> what the page was rendered from
+}
+
+// pageInfo records what a prerendered page was built from, so the next
+// export can tell whether it has to be rendered again.
+type pageInfo struct {
+	Source  string   `json:"source"`            // Fingerprint of the rows shown on the page
+	Uploads []string `json:"uploads,omitempty"` // Uploaded files the page links to
+}
+
+// page is a URL path to export and the fingerprint of the rows it shows.
+type page struct {
+	path   string
+	source string
+}
+
+// exportStats summarizes an export run.
+type exportStats struct {
+	Written   int
+	Unchanged int
+	Removed   int
+}
+
+type exporter struct {
+	out     string
+	baseURL string
+	full    bool
+	router  http.Handler
+
+	previous manifest
+	current  manifest
+	uploads  map[string]bool
+	stats    exportStats
+}
+
+// Run implements the export command:
+//
+//	blog export [-out public] [-base-url https://blog.example.com] [-full]
+//
+// Every published article, list, taxonomy and author page is prerendered
+// together with the feeds, sitemap and robots.txt, and referenced uploads are
+// copied. Pages whose rows, templates and settings did not change since the
+// last export are not rendered again, and files whose content did not change
+// are left untouched; -full rewrites everything.
+func Run(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := flags.String("out", "public", "output directory")
+	baseURL := flags.String("base-url", config.AppConfig.SiteURL, "public URL the site is served from")
+	full := flags.Bool("full", false, "rewrite every file instead of only changed ones")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	base, err := url.Parse(strings.TrimRight(*baseURL, "/"))
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return fmt.Errorf("invalid base URL %q", *baseURL)
+	}
+	if base.Path != "" {
+		return errors.New("the base URL must not contain a path; the export is served from the root of its host")
+	}
+
+	// Render pages the way the SSR mode does, but with links into the
+	// exported directory layout
+	config.AppConfig.SiteURL = base.String()
+	config.AppConfig.APIURL = base.String()
+	config.AppConfig.SSREnabled = true
+	controllers.UseStaticLinks()
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = io.Discard
+
+	e := &exporter{
+		out:     *out,
+		baseURL: base.String(),
+		full:    *full,
+		router:  routes.SetupRouter(routes.NewControllers(database.DB)),
+		current: manifest{Files: map[string]string{}, Pages: map[string]pageInfo{}},
+		uploads: map[string]bool{},
+	}
+
+	stats, err := e.run(database.DB)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Exported to %s: %d written, %d unchanged, %d removed", *out, stats.Written, stats.Unchanged, stats.Removed)
+	return nil
+}
+
+func (e *exporter) run(tx *gorm.DB) (exportStats, error) {
+	if err := e.loadManifest(); err != nil {
+		return e.stats, err
+	}
+
+	version, err := renderVersion()
+	if err != nil {
+		return e.stats, err
+	}
+	e.current.Version = version
+
+	f, err := newFingerprinter(tx)
+	if err != nil {
+		return e.stats, err
+	}
+	pages, err := f.pages()
+	if err != nil {
+		return e.stats, err
+	}
+
+	for _, p := range pages {
+		err := e.render(p)
+		if errors.Is(err, errNotFound) {
+			continue // Removed while the export was running
+		}
+		if err != nil {
+			return e.stats, err
+		}
+	}
+
+	// Large sites get a sitemap index; its child sitemaps are numbered and
+	// show the same rows as the index
+	for n := 1; ; n++ {
+		err := e.render(page{path: fmt.Sprintf("/sitemaps/%d.xml", n), source: f.site})
+		if errors.Is(err, errNotFound) {
+			break
+		}
+		if err != nil {
+			return e.stats, err
+		}
+	}
+
+	if err := e.copyUploads(); err != nil {
+		return e.stats, err
+	}
+	if err := e.removeStale(); err != nil {
+		return e.stats, err
+	}
+	return e.stats, e.saveManifest()
+}
+
+// renderVersion hashes everything besides the database that goes into a
+// page: the templates and the settings used to render them.
+func renderVersion() (string, error) {
+	settings := struct {
+		Format          int
+		SiteURL         string
+		SiteTitle       string
+		SiteDescription string
+		FeedLimit       int
+		FeedFullContent bool
+		RobotsDisallow  string
+		RobotsTxt       []byte
+		Templates       string
+	}{
+		Format:          1,
+		SiteURL:         config.AppConfig.SiteURL,
+		SiteTitle:       config.AppConfig.SiteTitle,
+		SiteDescription: config.AppConfig.SiteDescription,
+		FeedLimit:       config.AppConfig.FeedLimit,
+		FeedFullContent: config.AppConfig.FeedFullContent,
+		RobotsDisallow:  config.AppConfig.RobotsDisallow,
+		Templates:       templates.Hash(),
+	}
+	if file := config.AppConfig.RobotsTxtFile; file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		settings.RobotsTxt = data
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fingerprinter describes the rows shown on each page by the IDs and
+// updated_at times of the articles (or comments) it lists. Every page also
+// shows category, tag and author names, so changes to those rows count for
+// all pages.
+type fingerprinter struct {
+	tx       *gorm.DB
+	taxonomy string // Categories, tags and users
+	site     string // All published articles
+}
+
+func newFingerprinter(tx *gorm.DB) (*fingerprinter, error) {
+	f := &fingerprinter{tx: tx}
+	var parts []string
+	for _, model := range []interface{}{&models.Category{}, &models.Tag{}, &models.User{}} {
+		sum, err := fingerprint(tx.Model(model), "")
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, sum)
+	}
+	f.taxonomy = strings.Join(parts, "-")
+
+	site, err := f.articles(f.published())
+	if err != nil {
+		return nil, err
+	}
+	f.site = site
+	return f, nil
+}
+
+// published scopes a query to the articles visible to readers.
+func (f *fingerprinter) published() *gorm.DB {
+	return f.tx.Model(&models.Article{}).Where("articles.draft = ?", false)
+}
+
+func (f *fingerprinter) articles(query *gorm.DB) (string, error) {
+	sum, err := fingerprint(query, "articles.")
+	if err != nil {
+		return "", err
+	}
+	return sum + "-" + f.taxonomy, nil
+}
+
+// fingerprint hashes the IDs and updated_at times of the rows matched by
+// query, whose columns are qualified with prefix.
+func fingerprint(query *gorm.DB, prefix string) (string, error) {
+	var rows []struct {
+		ID        uint
+		UpdatedAt time.Time
+	}
+	err := query.Select(prefix + "id, " + prefix + "updated_at").Order(prefix + "id ASC").Scan(&rows).Error
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, row := range rows {
+		fmt.Fprintf(h, "%d:%d\n", row.ID, row.UpdatedAt.UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
+// pages lists every page to export with the fingerprint of its rows. Only
+// published articles and their authors get pages.
+func (f *fingerprinter) pages() ([]page, error) {
+	pages := []page{
+		{path: "/", source: f.site},
+		{path: "/timeline/", source: f.site},
+		{path: "/robots.txt"},
+		{path: "/sitemap.xml", source: f.site},
+	}
+	for _, file := range feedFiles {
+		pages = append(pages, page{path: "/" + file, source: f.site})
+	}
+
+	withFeeds := func(path, feedPrefix, source string) {
+		pages = append(pages, page{path: path, source: source})
+		for _, file := range feedFiles {
+			pages = append(pages, page{path: feedPrefix + "/" + file, source: source})
+		}
+	}
+
+	var articleIDs []uint
+	if err := f.published().Order("id ASC").Pluck("id", &articleIDs).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range articleIDs {
+		article, err := f.articles(f.published().Where("articles.id = ?", id))
+		if err != nil {
+			return nil, err
+		}
+		comments, err := fingerprint(f.tx.Model(&models.Comment{}).Where("article_id = ?", id), "")
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page{path: fmt.Sprintf("/articles/%d/", id), source: article})
+		for _, file := range feedFiles {
+			pages = append(pages, page{path: fmt.Sprintf("/feeds/articles/%d/comments/%s", id, file), source: article + "-" + comments})
+		}
+	}
+
+	var categories []models.Category
+	if err := f.tx.Order("id ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	taxonomy := services.NewTaxonomyService(repository.NewStore(f.tx))
+	for _, category := range categories {
+		if !safeSegment(category.Slug) {
+			continue
+		}
+		ids, err := taxonomy.CategoryDescendants(category.ID)
+		if err != nil {
+			return nil, err
+		}
+		source, err := f.articles(f.published().Where("articles.category_id IN ?", ids))
+		if err != nil {
+			return nil, err
+		}
+		withFeeds("/categories/"+url.PathEscape(category.Slug)+"/", "/feeds/categories/"+url.PathEscape(category.Slug), source)
+	}
+
+	var tags []models.Tag
+	if err := f.tx.Order("id ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if !safeSegment(tag.Slug) {
+			continue
+		}
+		tagged := f.tx.Table("article_tags").Select("article_id").Where("tag_id = ?", tag.ID)
+		source, err := f.articles(f.published().Where("articles.id IN (?)", tagged))
+		if err != nil {
+			return nil, err
+		}
+		withFeeds("/tags/"+url.PathEscape(tag.Slug)+"/", "/feeds/tags/"+url.PathEscape(tag.Slug), source)
+	}
+
+	var authors []models.User
+	err := f.tx.Where("id IN (?)", f.published().Select("author_id")).Order("id ASC").Find(&authors).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, author := range authors {
+		if !safeSegment(author.Username) {
+			log.Printf("Skipping author %q: the name cannot be used as a directory", author.Username)
+			continue
+		}
+		source, err := f.articles(f.published().Where("articles.author_id = ?", author.ID))
+		if err != nil {
+			return nil, err
+		}
+		withFeeds("/authors/"+url.PathEscape(author.Username)+"/", "/feeds/authors/"+url.PathEscape(author.Username), source)
+	}
+
+	return pages, nil
+}
+
+func safeSegment(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `/\`)
+}
+
+// outputPath maps a URL path to the file that serves it.
+func outputPath(page string) string {
+	page, _ = url.PathUnescape(page)
+	if strings.HasSuffix(page, "/") {
+		page += "index.html"
+	}
+	return strings.TrimPrefix(path.Clean(page), "/")
+}
+
+var errNotFound = errors.New("page not found")
+
+// get renders page through the router as if it was requested from the
+// public base URL.
+func (e *exporter) get(page string) ([]byte, error) {
+	req := httptest.NewRequest(http.MethodGet, e.baseURL+page, nil)
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+
+	switch w.Code {
+	case http.StatusOK:
+		return w.Body.Bytes(), nil
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%s: %w", page, errNotFound)
+	default:
+		return nil, fmt.Errorf("%s: unexpected status %d: %s", page, w.Code, w.Body.String())
+	}
+}
+
+// render writes p, unless it shows the same rows as in the previous export
+// and was rendered with the same templates and settings.
+func (e *exporter) render(p page) error {
+	rel := outputPath(p.path)
+	if e.reuse(rel, p.source) {
+		return nil
+	}
+
+	body, err := e.get(p.path)
+	if err != nil {
+		return err
+	}
+	info := pageInfo{Source: p.source}
+	body = e.rewriteUploads(body, &info)
+	e.current.Pages[rel] = info
+	return e.write(rel, body)
+}
+
+// reuse keeps the previous export of rel if nothing it was rendered from
+// changed and the file and the uploads it links to still exist.
+func (e *exporter) reuse(rel, source string) bool {
+	if e.full || e.previous.Version != e.current.Version {
+		return false
+	}
+	info, ok := e.previous.Pages[rel]
+	hash, written := e.previous.Files[rel]
+	if !ok || !written || info.Source != source {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(e.out, filepath.FromSlash(rel))); err != nil {
+		return false
+	}
+	for _, name := range info.Uploads {
+		if _, err := os.Stat(filepath.Join(controllers.UploadDir, name)); err != nil {
+			return false
+		}
+	}
+
+	e.current.Files[rel] = hash
+	e.current.Pages[rel] = info
+	for _, name := range info.Uploads {
+		e.uploads[name] = true
+	}
+	e.stats.Unchanged++
+	return true
+}
+
+// rewriteUploads points links to files in the upload directory at the copy
+// in the export, and remembers which files to copy.
+func (e *exporter) rewriteUploads(body []byte, info *pageInfo) []byte {
+	return controllers.UploadRefPattern.ReplaceAllFunc(body, func(ref []byte) []byte {
+		name := string(controllers.UploadRefPattern.FindSubmatch(ref)[2])
+		if stat, err := os.Stat(filepath.Join(controllers.UploadDir, name)); err != nil || stat.IsDir() {
+			return ref // Not one of ours
+		}
+		if !containsString(info.Uploads, name) {
+			info.Uploads = append(info.Uploads, name)
+		}
+		e.uploads[name] = true
+		return []byte(e.baseURL + "/uploads/" + name)
+	})
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *exporter) copyUploads() error {
+	names := make([]string, 0, len(e.uploads))
+	for name := range e.uploads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		src := filepath.Join(controllers.UploadDir, name)
+		info, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+
+		// Uploads never change in place, so size and mtime identify a version
+		rel := "uploads/" + name
+		hash := fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano())
+		if !e.changed(rel, hash) {
+			continue
+		}
+
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		if err := e.writeFile(rel, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changed records hash for rel and reports whether the file has to be
+// written. Unchanged files are only kept if they still exist.
+func (e *exporter) changed(rel, hash string) bool {
+	e.current.Files[rel] = hash
+	if !e.full && e.previous.Files[rel] == hash {
+		if _, err := os.Stat(filepath.Join(e.out, filepath.FromSlash(rel))); err == nil {
+			e.stats.Unchanged++
+			return false
+		}
+	}
+	return true
+}
+
+func (e *exporter) write(rel string, body []byte) error {
+	sum := sha256.Sum256(body)
+	if !e.changed(rel, hex.EncodeToString(sum[:])) {
+		return nil
+	}
+	return e.writeFile(rel, body)
+}
+
+func (e *exporter) writeFile(rel string, data []byte) error {
+	dst := filepath.Join(e.out, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return err
+	}
+	e.stats.Written++
+	return nil
+}
+
+// removeStale deletes files written by an earlier export that no longer
+// exist, such as pages of deleted articles.
+func (e *exporter) removeStale() error {
+	for rel := range e.previous.Files {
+		if _, ok := e.current.Files[rel]; ok {
+			continue
+		}
+		err := os.Remove(filepath.Join(e.out, filepath.FromSlash(rel)))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		e.stats.Removed++
+	}
+	return nil
+}
+
+func (e *exporter) loadManifest() error {
+	e.previous = manifest{Files: map[string]string{}, Pages: map[string]pageInfo{}}
+	data, err := os.ReadFile(filepath.Join(e.out, manifestName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &e.previous); err != nil {
+		return fmt.Errorf("invalid %s, run with -full: %w", manifestName, err)
+	}
+	if e.previous.Files == nil {
+		e.previous.Files = map[string]string{}
+	}
+	if e.previous.Pages == nil {
+		e.previous.Pages = map[string]pageInfo{}
+	}
+	return nil
+}
+
+func (e *exporter) saveManifest() error {
+	data, err := json.MarshalIndent(e.current, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(e.out, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(e.out, manifestName), data, 0644)
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/your-username/blog-backend/commands"
 	"github.com/your-username/blog-backend/config"
 	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/export"
 	"github.com/your-username/blog-backend/routes"
 )
 
@@ -10,12 +16,74 @@ func main() {
 	// Load Config
 	config.LoadConfig()
 
+	// Schema migrations connect on their own, before the schema check
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
 	// Connect Database
 	database.ConnectDB()
 
+	// Check the schema version
+	database.PrepareSchema()
+
+	// Subcommands, e.g. "blog export"
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
+	ctl := routes.NewControllers(database.DB)
+
+	// Remove abandoned chunked uploads
+	ctl.Uploads.StartJanitor(time.Hour)
+
+	// Purge items that have been in the trash longer than the retention
+	if config.AppConfig.TrashRetentionDays > 0 {
+		ctl.Trash.StartJanitor(time.Duration(config.AppConfig.TrashRetentionDays)*24*time.Hour, time.Hour)
+	}
+
+	// Page analytics: buffered writes, hourly rollups and retention
+	if config.AppConfig.AnalyticsEnabled {
+		ctl.Analytics.Start(config.AppConfig.AnalyticsFlushInterval,
+			time.Duration(config.AppConfig.AnalyticsRetentionDays)*24*time.Hour,
+			time.Duration(config.AppConfig.AnalyticsHourlyRetentionDays)*24*time.Hour)
+	}
+
+	// Scheduled backups with rotation
+	if config.AppConfig.BackupInterval > 0 {
+		ctl.Backups.StartScheduler(config.AppConfig.BackupInterval)
+	}
+
 	// Setup Router
-	r := routes.SetupRouter()
+	r := routes.SetupRouter(ctl)
 
 	// Run Server
 	r.Run(":" + config.AppConfig.ServerPort)
 }
+
+func runCommand(name string, args []string) {
+	var err error
+	switch name {
+	case "export":
+		err = export.Run(args)
+	case "import-markdown":
+		err = commands.ImportMarkdown(args)
+	case "export-markdown":
+		err = commands.ExportMarkdown(args)
+	case "import-wordpress":
+		err = commands.ImportWordPress(args)
+	case "backup":
+		err = commands.Backup(args)
+	case "restore":
+		err = commands.Restore(args)
+	case "migrate":
+		err = commands.Migrate(args)
+	default:
+		log.Fatalf("Unknown command %q (available: migrate, export, import-markdown, export-markdown, import-wordpress, backup, restore)", name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -33,3 +33,27 @@ func JwtAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalJwtAuthMiddleware sets user_id when the request carries a valid
+// token but lets anonymous requests through, for public routes that show
+// signed-in users more.
+func OptionalJwtAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := utils.ExtractToken(c.GetHeader("Authorization"))
+		if tokenString == "" {
+			c.Next()
+			return
+		}
+
+		token, err := utils.ValidateToken(tokenString)
+		if err == nil && token.Valid {
+			if claims, ok := token.Claims.(jwt.MapClaims); ok {
+				if userID, ok := claims["user_id"].(float64); ok {
+					c.Set("user_id", uint(userID))
+				}
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,128 @@
+package migrations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Databases created by AutoMigrate before migrations existed have no
+// schema_migrations table. Depending on the version that created them they
+// may lack tables, columns or indexes of the initial migration, which are
+// added instead of running it.
+
+var (
+	createTablePattern = regexp.MustCompile("^CREATE TABLE ([`\"](\\w+)[`\"]) \\(")
+	createIndexPattern = regexp.MustCompile("^CREATE (?:UNIQUE )?INDEX [`\"](\\w+)[`\"] ON [`\"](\\w+)[`\"]")
+	inlineIndexPattern = regexp.MustCompile("^(UNIQUE )?INDEX ([`\"](\\w+)[`\"]) (\\(.+\\)),?$") // MySQL
+	columnPattern      = regexp.MustCompile("^([`\"](\\w+)[`\"]) (.+?),?$")
+	keyColumnPattern   = regexp.MustCompile(`(?i)PRIMARY KEY|AUTO_?INCREMENT|serial`)
+	defaultPattern     = regexp.MustCompile(` DEFAULT (\S+)`)
+)
+
+// legacyTable is a table created by the initial migration.
+type legacyTable struct {
+	name       string
+	quoted     string
+	statements []string // CREATE TABLE and its CREATE INDEX statements
+	columns    []legacyColumn
+	indexes    []legacyIndex
+}
+
+type legacyColumn struct {
+	name       string
+	definition string // Quoted name, type and constraints
+}
+
+type legacyIndex struct {
+	name      string
+	statement string // CREATE INDEX, also for the indexes MySQL declares inline
+}
+
+// legacySchema reports whether the database has tables that were not created
+// by migrations.
+func legacySchema(db *gorm.DB) bool {
+	return db.Migrator().HasTable("users")
+}
+
+// parseTables reads the tables and columns created by the script of the
+// initial migration, in the order they are created.
+func parseTables(script string) ([]*legacyTable, error) {
+	statements, err := splitScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []*legacyTable
+	byName := map[string]*legacyTable{}
+	for _, stmt := range statements {
+		lines := strings.Split(strings.TrimSpace(stmt), "\n")
+		if m := createTablePattern.FindStringSubmatch(lines[0]); m != nil {
+			table := &legacyTable{name: m[2], quoted: m[1], statements: []string{stmt}}
+			for _, line := range lines[1:] {
+				line = strings.TrimSpace(line)
+				if i := inlineIndexPattern.FindStringSubmatch(line); i != nil {
+					table.indexes = append(table.indexes, legacyIndex{
+						name:      i[3],
+						statement: fmt.Sprintf("CREATE %sINDEX %s ON %s %s;", i[1], i[2], table.quoted, i[4]),
+					})
+				} else if c := columnPattern.FindStringSubmatch(line); c != nil {
+					table.columns = append(table.columns, legacyColumn{name: c[2], definition: c[1] + " " + c[3]})
+				}
+			}
+			tables = append(tables, table)
+			byName[table.name] = table
+			continue
+		}
+		if m := createIndexPattern.FindStringSubmatch(lines[0]); m != nil && byName[m[2]] != nil {
+			table := byName[m[2]]
+			table.statements = append(table.statements, stmt)
+			table.indexes = append(table.indexes, legacyIndex{name: m[1], statement: stmt})
+			continue
+		}
+		return nil, fmt.Errorf("unexpected statement in the initial migration: %s", lines[0])
+	}
+	return tables, nil
+}
+
+// legacyUpgrade compares the live schema with the initial migration and
+// returns the statements that add its missing tables, columns and indexes;
+// none if the schema matches. Added columns are nullable, since existing
+// rows have no value for them, and set to their default if they have one.
+func legacyUpgrade(db *gorm.DB, initial Migration) ([]string, error) {
+	tables, err := parseTables(initial.Up)
+	if err != nil {
+		return nil, err
+	}
+
+	m := db.Migrator()
+	var statements []string
+	for _, table := range tables {
+		if !m.HasTable(table.name) {
+			statements = append(statements, table.statements...)
+			continue
+		}
+		for _, column := range table.columns {
+			if m.HasColumn(table.name, column.name) {
+				continue
+			}
+			if keyColumnPattern.MatchString(column.definition) {
+				return nil, fmt.Errorf("the existing table %s has no %s column and cannot be upgraded", table.name, column.name)
+			}
+			definition := strings.Replace(column.definition, " NOT NULL", "", 1)
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table.quoted, definition))
+			if d := defaultPattern.FindStringSubmatch(definition); d != nil {
+				quoted := strings.SplitN(definition, " ", 2)[0]
+				statements = append(statements, fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s IS NULL;", table.quoted, quoted, d[1], quoted))
+			}
+		}
+		for _, index := range table.indexes {
+			if !m.HasIndex(table.name, index.name) {
+				statements = append(statements, index.statement)
+			}
+		}
+	}
+	return statements, nil
+}
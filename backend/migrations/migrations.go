@@ -0,0 +1,320 @@
+// Package migrations applies the versioned schema migrations embedded in the
+// binary. Every driver has its own directory of files named
+// <version>_<name>.up.sql and <version>_<name>.down.sql; applied versions are
+// recorded in the schema_migrations table.
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Drivers lists the directories migrations are kept in, one per DB_DRIVER.
+var Drivers = []string{"mysql", "postgres", "sqlite"}
+
+var filePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one schema version.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration is a row of schema_migrations.
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// Status describes a migration and whether it has been applied.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load returns the embedded migrations of driver, oldest first.
+func Load(driver string) ([]Migration, error) {
+	return load(files, driver)
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q: %w", dir, err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		m := filePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, m[2])
+		}
+
+		data, err := fs.ReadFile(fsys, dir+"/"+e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if m[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// applied returns the recorded migrations by version, creating the
+// schema_migrations table on first use. A database that was set up by
+// AutoMigrate before migrations existed may already have the initial schema;
+// version 1 is only recorded as applied if every table, column and index of
+// it is there. Otherwise it stays pending and Up upgrades the legacy schema.
+func applied(db *gorm.DB, migrations []Migration) (map[int64]SchemaMigration, error) {
+	m := db.Migrator()
+	if !m.HasTable(&SchemaMigration{}) {
+		if err := m.CreateTable(&SchemaMigration{}); err != nil {
+			return nil, err
+		}
+		if len(migrations) > 0 && migrations[0].Version == 1 && legacySchema(db) {
+			initial := migrations[0]
+			upgrade, err := legacyUpgrade(db, initial)
+			if err != nil {
+				return nil, err
+			}
+			if len(upgrade) > 0 {
+				log.Printf("Existing schema found that lacks tables, columns or indexes of migration 1_%s; run migrate up to add them", initial.Name)
+			} else {
+				log.Printf("Existing schema found, marking migration 1_%s as applied", initial.Name)
+				if err := db.Create(&SchemaMigration{Version: 1, Name: initial.Name, AppliedAt: time.Now()}).Error; err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[int64]SchemaMigration, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row
+	}
+	return done, nil
+}
+
+// Statuses lists every migration of the database's driver with the time it
+// was applied, oldest first.
+func Statuses(db *gorm.DB) ([]Status, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	done, err := applied(db, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(migrations))
+	for i, m := range migrations {
+		statuses[i] = Status{Migration: m}
+		if row, ok := done[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func Pending(db *gorm.DB) ([]Migration, error) {
+	statuses, err := Statuses(db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies at most n pending migrations, all of them if n <= 0, and
+// returns the applied ones. Each migration runs in its own transaction;
+// note that MySQL commits DDL statements immediately regardless.
+func Up(db *gorm.DB, n int) ([]Migration, error) {
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 && n < len(pending) {
+		pending = pending[:n]
+	}
+
+	for i, m := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			script := m.Up
+			if m.Version == 1 && legacySchema(tx) {
+				upgrade, err := legacyUpgrade(tx, m)
+				if err != nil {
+					return err
+				}
+				log.Printf("Upgrading the existing schema to migration 1_%s", m.Name)
+				script = strings.Join(upgrade, "\n")
+			}
+			if err := execScript(tx, script); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return pending[:i], fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+// Down reverts the n most recently applied migrations, newest first, and
+// returns the reverted ones.
+func Down(db *gorm.DB, n int) ([]Migration, error) {
+	statuses, err := Statuses(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(statuses) - 1; i >= 0 && len(reverted) < n; i-- {
+		m := statuses[i]
+		if m.AppliedAt == nil {
+			continue
+		}
+		if m.Down == "" {
+			return reverted, fmt.Errorf("migration %d_%s cannot be reverted: it has no down file", m.Version, m.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, m.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+		reverted = append(reverted, m.Migration)
+	}
+	return reverted, nil
+}
+
+// execScript runs the statements of a migration file one by one, since not
+// every driver accepts several statements in one call. Statements end with a
+// semicolon at the end of a line; lines starting with "--" are comments.
+func execScript(tx *gorm.DB, script string) error {
+	statements, err := splitScript(script)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitScript returns the statements of a migration file.
+func splitScript(script string) ([]string, error) {
+	var statements []string
+	var stmt strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		stmt.WriteString(line)
+		stmt.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, stmt.String())
+			stmt.Reset()
+		}
+	}
+	if strings.TrimSpace(stmt.String()) != "" {
+		return nil, errors.New("the last statement does not end with a semicolon")
+	}
+	return statements, nil
+}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create adds empty up and down files for a new migration to the driver
+// directories below dir and returns their paths. The version follows the
+// highest existing one.
+func Create(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "-", "_"))
+	if !namePattern.MatchString(name) {
+		return nil, errors.New("migration names may only contain letters, digits and underscores")
+	}
+
+	var version int64
+	for _, driver := range Drivers {
+		migrations, err := load(os.DirFS(dir), driver)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for _, m := range migrations {
+			version = max(version, m.Version)
+		}
+	}
+	version++
+
+	var paths []string
+	for _, driver := range Drivers {
+		if err := os.MkdirAll(filepath.Join(dir, driver), 0o755); err != nil {
+			return paths, err
+		}
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, driver, fmt.Sprintf("%04d_%s.%s.sql", version, name, direction))
+			content := fmt.Sprintf("-- %s migration %d_%s (%s)\n", strings.ToUpper(direction[:1])+direction[1:], version, name, driver)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
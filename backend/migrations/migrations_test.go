@@ -0,0 +1,251 @@
+package migrations
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "blog.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createLegacySchema sets db up like AutoMigrate did before migrations
+// existed: the initial migration with every statement passed through edit,
+// which may drop it by returning "". No schema_migrations table is created.
+func createLegacySchema(t *testing.T, db *gorm.DB, edit func(stmt string) string) {
+	t.Helper()
+	migrations, err := Load("sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements, err := splitScript(migrations[0].Up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range statements {
+		if stmt = edit(stmt); stmt == "" {
+			continue
+		}
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("setting up the legacy schema: %v\n%s", err, stmt)
+		}
+	}
+}
+
+// withoutLines removes the lines of a statement that contain any of parts.
+func withoutLines(stmt string, parts ...string) string {
+	var kept []string
+	for _, line := range strings.Split(stmt, "\n") {
+		drop := false
+		for _, part := range parts {
+			drop = drop || strings.Contains(line, part)
+		}
+		if !drop {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func TestUpFreshDatabase(t *testing.T) {
+	db := openSQLite(t)
+	all, err := Load("sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := Up(db, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != len(all) {
+		t.Fatalf("applied %d migrations, want %d", len(done), len(all))
+	}
+	pending, err := Pending(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("%d migrations still pending", len(pending))
+	}
+
+	reverted, err := Down(db, len(all))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != len(all) || db.Migrator().HasTable("articles") {
+		t.Errorf("Down reverted %d migrations and left articles: %v", len(reverted), db.Migrator().HasTable("articles"))
+	}
+}
+
+func TestUpLegacySchema(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(stmt string) string
+		// stamped is set when the legacy schema matches the initial
+		// migration, so it is recorded as applied before Up runs.
+		stamped bool
+	}{
+		{
+			name:    "complete",
+			edit:    func(stmt string) string { return stmt },
+			stamped: true,
+		},
+		{
+			name: "missing table",
+			edit: func(stmt string) string {
+				if strings.Contains(stmt, "`comments`") {
+					return ""
+				}
+				return stmt
+			},
+		},
+		{
+			name: "missing columns",
+			edit: func(stmt string) string {
+				if strings.Contains(stmt, "INDEX `idx_articles_draft`") || strings.Contains(stmt, "INDEX `idx_articles_featured`") {
+					return ""
+				}
+				return withoutLines(stmt, "`friend_links`", "`draft`", "`featured`", "`views`")
+			},
+		},
+		{
+			name: "missing indexes",
+			edit: func(stmt string) string {
+				if strings.HasPrefix(stmt, "CREATE INDEX") || strings.Contains(stmt, "INDEX `idx_categories_name`") {
+					return ""
+				}
+				return stmt
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openSQLite(t)
+			createLegacySchema(t, db, tt.edit)
+			if err := db.Exec("INSERT INTO `users` (`username`, `email`, `password`) VALUES ('admin', 'admin@example.com', 'x')").Error; err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Exec("INSERT INTO `articles` (`title`, `slug`, `content`, `author_id`) VALUES ('Hello', 'hello', 'Hi', 1)").Error; err != nil {
+				t.Fatal(err)
+			}
+
+			pending, err := Pending(db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stamped := len(pending) == 0 || pending[0].Version != 1; stamped != tt.stamped {
+				t.Errorf("initial migration recorded as applied = %v, want %v", stamped, tt.stamped)
+			}
+
+			if _, err := Up(db, 0); err != nil {
+				t.Fatal(err)
+			}
+			if pending, _ := Pending(db); len(pending) != 0 {
+				t.Fatalf("%d migrations still pending", len(pending))
+			}
+
+			initial, err := Load("sqlite")
+			if err != nil {
+				t.Fatal(err)
+			}
+			upgrade, err := legacyUpgrade(db, initial[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 0005 turns idx_articles_slug and friends into unique indexes
+			// under the same names, so nothing of the initial schema is
+			// missing afterwards.
+			if len(upgrade) > 0 {
+				t.Errorf("schema still lacks parts of the initial migration: %v", upgrade)
+			}
+
+			var visible int64
+			err = db.Table("articles").Where("draft = ? AND deleted_at IS NULL", false).Count(&visible).Error
+			if err != nil {
+				t.Fatal(err)
+			}
+			if visible != 1 {
+				t.Errorf("%d published articles after the upgrade, want 1", visible)
+			}
+			var views *int64
+			if err := db.Table("articles").Select("views").Scan(&views).Error; err != nil {
+				t.Fatal(err)
+			}
+			if views == nil || *views != 0 {
+				t.Errorf("views = %v after the upgrade, want 0", views)
+			}
+		})
+	}
+}
+
+func TestParseTablesInlineIndexes(t *testing.T) {
+	tests := []struct {
+		driver string
+		table  string
+		want   []string
+	}{
+		{
+			driver: "mysql",
+			table:  "users",
+			want: []string{
+				"CREATE UNIQUE INDEX `idx_users_username` ON `users` (`username`);",
+				"CREATE UNIQUE INDEX `idx_users_email` ON `users` (`email`);",
+				"CREATE INDEX `idx_users_deleted_at` ON `users` (`deleted_at`);",
+			},
+		},
+		{
+			driver: "postgres",
+			table:  "users",
+			want: []string{
+				`CREATE UNIQUE INDEX "idx_users_username" ON "users" ("username");`,
+				`CREATE UNIQUE INDEX "idx_users_email" ON "users" ("email");`,
+				`CREATE INDEX "idx_users_deleted_at" ON "users" ("deleted_at");`,
+			},
+		},
+		{
+			driver: "sqlite",
+			table:  "article_tags",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			migrations, err := Load(tt.driver)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tables, err := parseTables(migrations[0].Up)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, table := range tables {
+				if table.name != tt.table {
+					continue
+				}
+				for _, index := range table.indexes {
+					got = append(got, strings.TrimSpace(index.statement))
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("indexes of %s:\n%s\nwant:\n%s", tt.table, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+DROP TABLE `comments`;
+DROP TABLE `article_tags`;
+DROP TABLE `articles`;
+DROP TABLE `upload_sessions`;
+DROP TABLE `media`;
+DROP TABLE `series`;
+DROP TABLE `tag_aliases`;
+DROP TABLE `tags`;
+DROP TABLE `categories`;
+DROP TABLE `users`;
@@ -0,0 +1,170 @@
+-- Schema of the tables previously created by AutoMigrate.
+
+CREATE TABLE `users` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`username` varchar(100) NOT NULL,
+	`email` varchar(100) NOT NULL,
+	`password` longtext NOT NULL,
+	`avatar_url` longtext,
+	`bio` text,
+	`social_links` text,
+	`sponsor_links` text,
+	`friend_links` text,
+	`created_at` datetime(3) NULL,
+	`updated_at` datetime(3) NULL,
+	`deleted_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `idx_users_username` (`username`),
+	UNIQUE INDEX `idx_users_email` (`email`),
+	INDEX `idx_users_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE `categories` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`name` varchar(100) NOT NULL,
+	`parent_id` bigint unsigned,
+	`slug` varchar(120),
+	`description` text,
+	`color` varchar(7),
+	`icon` varchar(100),
+	`cover_media_id` bigint unsigned,
+	`cover_url` varchar(512),
+	`created_at` datetime(3) NULL,
+	`updated_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `idx_categories_name` (`name`),
+	INDEX `idx_categories_parent_id` (`parent_id`),
+	INDEX `idx_categories_slug` (`slug`)
+);
+
+CREATE TABLE `tags` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`name` varchar(100) NOT NULL,
+	`slug` varchar(120),
+	`description` text,
+	`color` varchar(7),
+	`icon` varchar(100),
+	`cover_media_id` bigint unsigned,
+	`cover_url` varchar(512),
+	`created_at` datetime(3) NULL,
+	`updated_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `idx_tags_name` (`name`),
+	INDEX `idx_tags_slug` (`slug`)
+);
+
+CREATE TABLE `tag_aliases` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`name` varchar(100) NOT NULL,
+	`tag_id` bigint unsigned NOT NULL,
+	`created_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `idx_tag_aliases_name` (`name`),
+	INDEX `idx_tag_aliases_tag_id` (`tag_id`),
+	CONSTRAINT `fk_tags_aliases` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE `series` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`title` varchar(255) NOT NULL,
+	`slug` varchar(120) NOT NULL,
+	`description` text,
+	`author_id` bigint unsigned NOT NULL,
+	`created_at` datetime(3) NULL,
+	`updated_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `idx_series_slug` (`slug`)
+);
+
+CREATE TABLE `media` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`user_id` bigint unsigned NOT NULL,
+	`filename` varchar(255) NOT NULL,
+	`original_name` varchar(255),
+	`url` varchar(512) NOT NULL,
+	`size` bigint,
+	`mime_type` varchar(100),
+	`checksum` varchar(64),
+	`created_at` datetime(3) NULL,
+	`updated_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	INDEX `idx_media_user_id` (`user_id`),
+	UNIQUE INDEX `idx_media_filename` (`filename`)
+);
+
+CREATE TABLE `upload_sessions` (
+	`id` varchar(64),
+	`user_id` bigint unsigned NOT NULL,
+	`filename` varchar(255) NOT NULL,
+	`extension` varchar(16) NOT NULL,
+	`total_size` bigint NOT NULL,
+	`chunk_size` bigint NOT NULL,
+	`total_chunks` bigint NOT NULL,
+	`checksum` varchar(64),
+	`expires_at` datetime(3) NULL,
+	`created_at` datetime(3) NULL,
+	`updated_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	INDEX `idx_upload_sessions_user_id` (`user_id`),
+	INDEX `idx_upload_sessions_expires_at` (`expires_at`)
+);
+
+CREATE TABLE `articles` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`title` varchar(255) NOT NULL,
+	`slug` varchar(191),
+	`content` text NOT NULL,
+	`content_html` mediumtext,
+	`excerpt` text,
+	`toc` text,
+	`word_count` bigint DEFAULT 0,
+	`reading_time` bigint DEFAULT 0,
+	`author_id` bigint unsigned NOT NULL,
+	`category_id` bigint unsigned,
+	`cover_media_id` bigint unsigned,
+	`cover_url` varchar(512),
+	`pinned` boolean DEFAULT false,
+	`pin_order` bigint DEFAULT 0,
+	`featured` boolean DEFAULT false,
+	`draft` boolean DEFAULT false,
+	`series_id` bigint unsigned,
+	`series_order` bigint DEFAULT 0,
+	`views` bigint unsigned DEFAULT 0,
+	`likes` bigint unsigned DEFAULT 0,
+	`created_at` datetime(3) NULL,
+	`updated_at` datetime(3) NULL,
+	`deleted_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	INDEX `idx_articles_slug` (`slug`),
+	INDEX `idx_articles_pinned` (`pinned`),
+	INDEX `idx_articles_featured` (`featured`),
+	INDEX `idx_articles_draft` (`draft`),
+	INDEX `idx_articles_series_id` (`series_id`),
+	INDEX `idx_articles_deleted_at` (`deleted_at`),
+	CONSTRAINT `fk_series_articles` FOREIGN KEY (`series_id`) REFERENCES `series`(`id`),
+	CONSTRAINT `fk_articles_author` FOREIGN KEY (`author_id`) REFERENCES `users`(`id`),
+	CONSTRAINT `fk_articles_category` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`)
+);
+
+CREATE TABLE `article_tags` (
+	`article_id` bigint unsigned,
+	`tag_id` bigint unsigned,
+	PRIMARY KEY (`article_id`, `tag_id`),
+	CONSTRAINT `fk_article_tags_article` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`),
+	CONSTRAINT `fk_article_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE `comments` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`content` text NOT NULL,
+	`content_html` text,
+	`article_id` bigint unsigned NOT NULL,
+	`user_id` bigint unsigned NOT NULL,
+	`created_at` datetime(3) NULL,
+	`updated_at` datetime(3) NULL,
+	`deleted_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	INDEX `idx_comments_deleted_at` (`deleted_at`),
+	CONSTRAINT `fk_comments_article` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`),
+	CONSTRAINT `fk_comments_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
@@ -0,0 +1,9 @@
+DELETE FROM `article_tags` WHERE `tag_id` IN (SELECT `id` FROM `tags` WHERE `deleted_at` IS NOT NULL);
+DELETE FROM `tags` WHERE `deleted_at` IS NOT NULL;
+DROP INDEX `idx_tags_deleted_at` ON `tags`;
+ALTER TABLE `tags` DROP COLUMN `deleted_at`;
+UPDATE `articles` SET `category_id` = NULL WHERE `category_id` IN (SELECT `id` FROM (SELECT `id` FROM `categories` WHERE `deleted_at` IS NOT NULL) AS `trashed`);
+UPDATE `categories` SET `parent_id` = NULL WHERE `parent_id` IN (SELECT `id` FROM (SELECT `id` FROM `categories` WHERE `deleted_at` IS NOT NULL) AS `trashed`);
+DELETE FROM `categories` WHERE `deleted_at` IS NOT NULL;
+DROP INDEX `idx_categories_deleted_at` ON `categories`;
+ALTER TABLE `categories` DROP COLUMN `deleted_at`;
@@ -0,0 +1,10 @@
+CREATE TABLE `article_events` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`article_id` bigint unsigned NOT NULL,
+	`type` varchar(16) NOT NULL,
+	`referrer` varchar(255),
+	`created_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	INDEX `idx_article_events_article_id` (`article_id`),
+	INDEX `idx_article_events_type_created_at` (`type`, `created_at`)
+);
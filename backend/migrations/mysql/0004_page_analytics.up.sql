@@ -0,0 +1,33 @@
+CREATE TABLE `page_views` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`path` varchar(255) NOT NULL,
+	`referrer` varchar(255),
+	`utm_source` varchar(100),
+	`utm_medium` varchar(100),
+	`utm_campaign` varchar(100),
+	`device` varchar(16),
+	`browser` varchar(32),
+	`os` varchar(32),
+	`visitor_hash` varchar(64) NOT NULL,
+	`created_at` datetime(3) NULL,
+	PRIMARY KEY (`id`),
+	INDEX `idx_page_views_created_at` (`created_at`)
+);
+CREATE TABLE `hourly_page_stats` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`period` datetime(3) NOT NULL,
+	`path` varchar(255) NOT NULL,
+	`views` bigint NOT NULL,
+	`visitors` bigint NOT NULL,
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `idx_hourly_page_stats_period_path` (`period`, `path`)
+);
+CREATE TABLE `daily_page_stats` (
+	`id` bigint unsigned AUTO_INCREMENT,
+	`period` datetime(3) NOT NULL,
+	`path` varchar(255) NOT NULL,
+	`views` bigint NOT NULL,
+	`visitors` bigint NOT NULL,
+	PRIMARY KEY (`id`),
+	UNIQUE INDEX `idx_daily_page_stats_period_path` (`period`, `path`)
+);
@@ -0,0 +1,6 @@
+DROP INDEX `idx_articles_slug` ON `articles`;
+CREATE INDEX `idx_articles_slug` ON `articles` (`slug`);
+DROP INDEX `idx_categories_slug` ON `categories`;
+CREATE INDEX `idx_categories_slug` ON `categories` (`slug`);
+DROP INDEX `idx_tags_slug` ON `tags`;
+CREATE INDEX `idx_tags_slug` ON `tags` (`slug`);
@@ -0,0 +1,24 @@
+UPDATE `articles` SET `slug` = CONCAT('item-', `slug`) WHERE `slug` REGEXP '^[0-9]+$';
+UPDATE `articles` SET `slug` = NULL WHERE `slug` = '' OR `id` NOT IN (SELECT `id` FROM (SELECT MIN(`id`) AS `id` FROM `articles` WHERE `slug` IS NOT NULL GROUP BY `slug`) AS `kept`);
+-- MySQL has no DROP INDEX IF EXISTS, and a legacy schema may lack the old
+-- indexes, so they are only dropped if information_schema lists them
+SET @drop_index = IF((SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'articles' AND index_name = 'idx_articles_slug') > 0, 'DROP INDEX `idx_articles_slug` ON `articles`', 'DO 0');
+PREPARE drop_index FROM @drop_index;
+EXECUTE drop_index;
+DEALLOCATE PREPARE drop_index;
+CREATE UNIQUE INDEX `idx_articles_slug` ON `articles` (`slug`);
+UPDATE `categories` SET `slug` = CONCAT('item-', `slug`) WHERE `slug` REGEXP '^[0-9]+$';
+UPDATE `categories` SET `slug` = NULL WHERE `slug` = '' OR `id` NOT IN (SELECT `id` FROM (SELECT MIN(`id`) AS `id` FROM `categories` WHERE `slug` IS NOT NULL GROUP BY `slug`) AS `kept`);
+SET @drop_index = IF((SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'categories' AND index_name = 'idx_categories_slug') > 0, 'DROP INDEX `idx_categories_slug` ON `categories`', 'DO 0');
+PREPARE drop_index FROM @drop_index;
+EXECUTE drop_index;
+DEALLOCATE PREPARE drop_index;
+CREATE UNIQUE INDEX `idx_categories_slug` ON `categories` (`slug`);
+UPDATE `tags` SET `slug` = CONCAT('item-', `slug`) WHERE `slug` REGEXP '^[0-9]+$';
+UPDATE `tags` SET `slug` = NULL WHERE `slug` = '' OR `id` NOT IN (SELECT `id` FROM (SELECT MIN(`id`) AS `id` FROM `tags` WHERE `slug` IS NOT NULL GROUP BY `slug`) AS `kept`);
+SET @drop_index = IF((SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'tags' AND index_name = 'idx_tags_slug') > 0, 'DROP INDEX `idx_tags_slug` ON `tags`', 'DO 0');
+PREPARE drop_index FROM @drop_index;
+EXECUTE drop_index;
+DEALLOCATE PREPARE drop_index;
+CREATE UNIQUE INDEX `idx_tags_slug` ON `tags` (`slug`);
+UPDATE `series` SET `slug` = CONCAT('item-', `slug`) WHERE `slug` REGEXP '^[0-9]+$';
@@ -0,0 +1 @@
+ALTER TABLE `articles` ADD `import_source` varchar(255);
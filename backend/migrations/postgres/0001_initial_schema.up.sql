@@ -0,0 +1,170 @@
+-- Schema of the tables previously created by AutoMigrate.
+
+CREATE TABLE "users" (
+	"id" bigserial,
+	"username" varchar(100) NOT NULL,
+	"email" varchar(100) NOT NULL,
+	"password" text NOT NULL,
+	"avatar_url" text,
+	"bio" text,
+	"social_links" text,
+	"sponsor_links" text,
+	"friend_links" text,
+	"created_at" timestamptz,
+	"updated_at" timestamptz,
+	"deleted_at" timestamptz,
+	PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_users_username" ON "users" ("username");
+CREATE UNIQUE INDEX "idx_users_email" ON "users" ("email");
+CREATE INDEX "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE "categories" (
+	"id" bigserial,
+	"name" varchar(100) NOT NULL,
+	"parent_id" bigint,
+	"slug" varchar(120),
+	"description" text,
+	"color" varchar(7),
+	"icon" varchar(100),
+	"cover_media_id" bigint,
+	"cover_url" varchar(512),
+	"created_at" timestamptz,
+	"updated_at" timestamptz,
+	PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_categories_name" ON "categories" ("name");
+CREATE INDEX "idx_categories_parent_id" ON "categories" ("parent_id");
+CREATE INDEX "idx_categories_slug" ON "categories" ("slug");
+
+CREATE TABLE "tags" (
+	"id" bigserial,
+	"name" varchar(100) NOT NULL,
+	"slug" varchar(120),
+	"description" text,
+	"color" varchar(7),
+	"icon" varchar(100),
+	"cover_media_id" bigint,
+	"cover_url" varchar(512),
+	"created_at" timestamptz,
+	"updated_at" timestamptz,
+	PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_tags_name" ON "tags" ("name");
+CREATE INDEX "idx_tags_slug" ON "tags" ("slug");
+
+CREATE TABLE "tag_aliases" (
+	"id" bigserial,
+	"name" varchar(100) NOT NULL,
+	"tag_id" bigint NOT NULL,
+	"created_at" timestamptz,
+	PRIMARY KEY ("id"),
+	CONSTRAINT "fk_tags_aliases" FOREIGN KEY ("tag_id") REFERENCES "tags"("id")
+);
+CREATE UNIQUE INDEX "idx_tag_aliases_name" ON "tag_aliases" ("name");
+CREATE INDEX "idx_tag_aliases_tag_id" ON "tag_aliases" ("tag_id");
+
+CREATE TABLE "series" (
+	"id" bigserial,
+	"title" varchar(255) NOT NULL,
+	"slug" varchar(120) NOT NULL,
+	"description" text,
+	"author_id" bigint NOT NULL,
+	"created_at" timestamptz,
+	"updated_at" timestamptz,
+	PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_series_slug" ON "series" ("slug");
+
+CREATE TABLE "media" (
+	"id" bigserial,
+	"user_id" bigint NOT NULL,
+	"filename" varchar(255) NOT NULL,
+	"original_name" varchar(255),
+	"url" varchar(512) NOT NULL,
+	"size" bigint,
+	"mime_type" varchar(100),
+	"checksum" varchar(64),
+	"created_at" timestamptz,
+	"updated_at" timestamptz,
+	PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_media_user_id" ON "media" ("user_id");
+CREATE UNIQUE INDEX "idx_media_filename" ON "media" ("filename");
+
+CREATE TABLE "upload_sessions" (
+	"id" varchar(64),
+	"user_id" bigint NOT NULL,
+	"filename" varchar(255) NOT NULL,
+	"extension" varchar(16) NOT NULL,
+	"total_size" bigint NOT NULL,
+	"chunk_size" bigint NOT NULL,
+	"total_chunks" bigint NOT NULL,
+	"checksum" varchar(64),
+	"expires_at" timestamptz,
+	"created_at" timestamptz,
+	"updated_at" timestamptz,
+	PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_upload_sessions_user_id" ON "upload_sessions" ("user_id");
+CREATE INDEX "idx_upload_sessions_expires_at" ON "upload_sessions" ("expires_at");
+
+CREATE TABLE "articles" (
+	"id" bigserial,
+	"title" varchar(255) NOT NULL,
+	"slug" varchar(191),
+	"content" text NOT NULL,
+	"content_html" text,
+	"excerpt" text,
+	"toc" text,
+	"word_count" bigint DEFAULT 0,
+	"reading_time" bigint DEFAULT 0,
+	"author_id" bigint NOT NULL,
+	"category_id" bigint,
+	"cover_media_id" bigint,
+	"cover_url" varchar(512),
+	"pinned" boolean DEFAULT false,
+	"pin_order" bigint DEFAULT 0,
+	"featured" boolean DEFAULT false,
+	"draft" boolean DEFAULT false,
+	"series_id" bigint,
+	"series_order" bigint DEFAULT 0,
+	"views" bigint DEFAULT 0,
+	"likes" bigint DEFAULT 0,
+	"created_at" timestamptz,
+	"updated_at" timestamptz,
+	"deleted_at" timestamptz,
+	PRIMARY KEY ("id"),
+	CONSTRAINT "fk_series_articles" FOREIGN KEY ("series_id") REFERENCES "series"("id"),
+	CONSTRAINT "fk_articles_author" FOREIGN KEY ("author_id") REFERENCES "users"("id"),
+	CONSTRAINT "fk_articles_category" FOREIGN KEY ("category_id") REFERENCES "categories"("id")
+);
+CREATE INDEX "idx_articles_slug" ON "articles" ("slug");
+CREATE INDEX "idx_articles_pinned" ON "articles" ("pinned");
+CREATE INDEX "idx_articles_featured" ON "articles" ("featured");
+CREATE INDEX "idx_articles_draft" ON "articles" ("draft");
+CREATE INDEX "idx_articles_series_id" ON "articles" ("series_id");
+CREATE INDEX "idx_articles_deleted_at" ON "articles" ("deleted_at");
+
+CREATE TABLE "article_tags" (
+	"article_id" bigint,
+	"tag_id" bigint,
+	PRIMARY KEY ("article_id", "tag_id"),
+	CONSTRAINT "fk_article_tags_article" FOREIGN KEY ("article_id") REFERENCES "articles"("id"),
+	CONSTRAINT "fk_article_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "tags"("id")
+);
+
+CREATE TABLE "comments" (
+	"id" bigserial,
+	"content" text NOT NULL,
+	"content_html" text,
+	"article_id" bigint NOT NULL,
+	"user_id" bigint NOT NULL,
+	"created_at" timestamptz,
+	"updated_at" timestamptz,
+	"deleted_at" timestamptz,
+	PRIMARY KEY ("id"),
+	CONSTRAINT "fk_comments_article" FOREIGN KEY ("article_id") REFERENCES "articles"("id"),
+	CONSTRAINT "fk_comments_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_comments_deleted_at" ON "comments" ("deleted_at");
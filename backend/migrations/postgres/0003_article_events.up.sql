@@ -0,0 +1,10 @@
+CREATE TABLE "article_events" (
+	"id" bigserial,
+	"article_id" bigint NOT NULL,
+	"type" varchar(16) NOT NULL,
+	"referrer" varchar(255),
+	"created_at" timestamptz,
+	PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_article_events_article_id" ON "article_events" ("article_id");
+CREATE INDEX "idx_article_events_type_created_at" ON "article_events" ("type", "created_at");
@@ -0,0 +1,33 @@
+CREATE TABLE "page_views" (
+	"id" bigserial,
+	"path" varchar(255) NOT NULL,
+	"referrer" varchar(255),
+	"utm_source" varchar(100),
+	"utm_medium" varchar(100),
+	"utm_campaign" varchar(100),
+	"device" varchar(16),
+	"browser" varchar(32),
+	"os" varchar(32),
+	"visitor_hash" varchar(64) NOT NULL,
+	"created_at" timestamptz,
+	PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_page_views_created_at" ON "page_views" ("created_at");
+CREATE TABLE "hourly_page_stats" (
+	"id" bigserial,
+	"period" timestamptz NOT NULL,
+	"path" varchar(255) NOT NULL,
+	"views" bigint NOT NULL,
+	"visitors" bigint NOT NULL,
+	PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_hourly_page_stats_period_path" ON "hourly_page_stats" ("period", "path");
+CREATE TABLE "daily_page_stats" (
+	"id" bigserial,
+	"period" timestamptz NOT NULL,
+	"path" varchar(255) NOT NULL,
+	"views" bigint NOT NULL,
+	"visitors" bigint NOT NULL,
+	PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_daily_page_stats_period_path" ON "daily_page_stats" ("period", "path");
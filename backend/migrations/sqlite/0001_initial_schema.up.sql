@@ -0,0 +1,161 @@
+-- Schema of the tables previously created by AutoMigrate.
+
+CREATE TABLE `users` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`username` varchar(100) NOT NULL,
+	`email` varchar(100) NOT NULL,
+	`password` text NOT NULL,
+	`avatar_url` text,
+	`bio` text,
+	`social_links` text,
+	`sponsor_links` text,
+	`friend_links` text,
+	`created_at` datetime,
+	`updated_at` datetime,
+	`deleted_at` datetime
+);
+CREATE UNIQUE INDEX `idx_users_username` ON `users` (`username`);
+CREATE UNIQUE INDEX `idx_users_email` ON `users` (`email`);
+CREATE INDEX `idx_users_deleted_at` ON `users` (`deleted_at`);
+
+CREATE TABLE `categories` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`name` varchar(100) NOT NULL,
+	`parent_id` integer,
+	`slug` varchar(120),
+	`description` text,
+	`color` varchar(7),
+	`icon` varchar(100),
+	`cover_media_id` integer,
+	`cover_url` varchar(512),
+	`created_at` datetime,
+	`updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_categories_name` ON `categories` (`name`);
+CREATE INDEX `idx_categories_parent_id` ON `categories` (`parent_id`);
+CREATE INDEX `idx_categories_slug` ON `categories` (`slug`);
+
+CREATE TABLE `tags` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`name` varchar(100) NOT NULL,
+	`slug` varchar(120),
+	`description` text,
+	`color` varchar(7),
+	`icon` varchar(100),
+	`cover_media_id` integer,
+	`cover_url` varchar(512),
+	`created_at` datetime,
+	`updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_tags_name` ON `tags` (`name`);
+CREATE INDEX `idx_tags_slug` ON `tags` (`slug`);
+
+CREATE TABLE `tag_aliases` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`name` varchar(100) NOT NULL,
+	`tag_id` integer NOT NULL,
+	`created_at` datetime,
+	CONSTRAINT `fk_tags_aliases` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
+CREATE UNIQUE INDEX `idx_tag_aliases_name` ON `tag_aliases` (`name`);
+CREATE INDEX `idx_tag_aliases_tag_id` ON `tag_aliases` (`tag_id`);
+
+CREATE TABLE `series` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`title` varchar(255) NOT NULL,
+	`slug` varchar(120) NOT NULL,
+	`description` text,
+	`author_id` integer NOT NULL,
+	`created_at` datetime,
+	`updated_at` datetime
+);
+CREATE UNIQUE INDEX `idx_series_slug` ON `series` (`slug`);
+
+CREATE TABLE `media` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`user_id` integer NOT NULL,
+	`filename` varchar(255) NOT NULL,
+	`original_name` varchar(255),
+	`url` varchar(512) NOT NULL,
+	`size` integer,
+	`mime_type` varchar(100),
+	`checksum` varchar(64),
+	`created_at` datetime,
+	`updated_at` datetime
+);
+CREATE INDEX `idx_media_user_id` ON `media` (`user_id`);
+CREATE UNIQUE INDEX `idx_media_filename` ON `media` (`filename`);
+
+CREATE TABLE `upload_sessions` (
+	`id` varchar(64) PRIMARY KEY,
+	`user_id` integer NOT NULL,
+	`filename` varchar(255) NOT NULL,
+	`extension` varchar(16) NOT NULL,
+	`total_size` integer NOT NULL,
+	`chunk_size` integer NOT NULL,
+	`total_chunks` integer NOT NULL,
+	`checksum` varchar(64),
+	`expires_at` datetime,
+	`created_at` datetime,
+	`updated_at` datetime
+);
+CREATE INDEX `idx_upload_sessions_user_id` ON `upload_sessions` (`user_id`);
+CREATE INDEX `idx_upload_sessions_expires_at` ON `upload_sessions` (`expires_at`);
+
+CREATE TABLE `articles` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`title` varchar(255) NOT NULL,
+	`slug` varchar(191),
+	`content` text NOT NULL,
+	`content_html` text,
+	`excerpt` text,
+	`toc` text,
+	`word_count` integer DEFAULT 0,
+	`reading_time` integer DEFAULT 0,
+	`author_id` integer NOT NULL,
+	`category_id` integer,
+	`cover_media_id` integer,
+	`cover_url` varchar(512),
+	`pinned` numeric DEFAULT false,
+	`pin_order` integer DEFAULT 0,
+	`featured` numeric DEFAULT false,
+	`draft` numeric DEFAULT false,
+	`series_id` integer,
+	`series_order` integer DEFAULT 0,
+	`views` integer DEFAULT 0,
+	`likes` integer DEFAULT 0,
+	`created_at` datetime,
+	`updated_at` datetime,
+	`deleted_at` datetime,
+	CONSTRAINT `fk_series_articles` FOREIGN KEY (`series_id`) REFERENCES `series`(`id`),
+	CONSTRAINT `fk_articles_author` FOREIGN KEY (`author_id`) REFERENCES `users`(`id`),
+	CONSTRAINT `fk_articles_category` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`)
+);
+CREATE INDEX `idx_articles_slug` ON `articles` (`slug`);
+CREATE INDEX `idx_articles_pinned` ON `articles` (`pinned`);
+CREATE INDEX `idx_articles_featured` ON `articles` (`featured`);
+CREATE INDEX `idx_articles_draft` ON `articles` (`draft`);
+CREATE INDEX `idx_articles_series_id` ON `articles` (`series_id`);
+CREATE INDEX `idx_articles_deleted_at` ON `articles` (`deleted_at`);
+
+CREATE TABLE `article_tags` (
+	`article_id` integer,
+	`tag_id` integer,
+	PRIMARY KEY (`article_id`, `tag_id`),
+	CONSTRAINT `fk_article_tags_article` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`),
+	CONSTRAINT `fk_article_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE `comments` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`content` text NOT NULL,
+	`content_html` text,
+	`article_id` integer NOT NULL,
+	`user_id` integer NOT NULL,
+	`created_at` datetime,
+	`updated_at` datetime,
+	`deleted_at` datetime,
+	CONSTRAINT `fk_comments_article` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`),
+	CONSTRAINT `fk_comments_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_comments_deleted_at` ON `comments` (`deleted_at`);
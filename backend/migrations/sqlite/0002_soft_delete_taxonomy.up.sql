@@ -0,0 +1,4 @@
+ALTER TABLE `categories` ADD `deleted_at` datetime;
+CREATE INDEX `idx_categories_deleted_at` ON `categories` (`deleted_at`);
+ALTER TABLE `tags` ADD `deleted_at` datetime;
+CREATE INDEX `idx_tags_deleted_at` ON `tags` (`deleted_at`);
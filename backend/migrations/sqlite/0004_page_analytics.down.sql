@@ -0,0 +1,3 @@
+DROP TABLE `daily_page_stats`;
+DROP TABLE `hourly_page_stats`;
+DROP TABLE `page_views`;
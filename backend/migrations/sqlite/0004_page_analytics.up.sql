@@ -0,0 +1,30 @@
+CREATE TABLE `page_views` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`path` varchar(255) NOT NULL,
+	`referrer` varchar(255),
+	`utm_source` varchar(100),
+	`utm_medium` varchar(100),
+	`utm_campaign` varchar(100),
+	`device` varchar(16),
+	`browser` varchar(32),
+	`os` varchar(32),
+	`visitor_hash` varchar(64) NOT NULL,
+	`created_at` datetime
+);
+CREATE INDEX `idx_page_views_created_at` ON `page_views` (`created_at`);
+CREATE TABLE `hourly_page_stats` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`period` datetime NOT NULL,
+	`path` varchar(255) NOT NULL,
+	`views` integer NOT NULL,
+	`visitors` integer NOT NULL
+);
+CREATE UNIQUE INDEX `idx_hourly_page_stats_period_path` ON `hourly_page_stats` (`period`, `path`);
+CREATE TABLE `daily_page_stats` (
+	`id` integer PRIMARY KEY AUTOINCREMENT,
+	`period` datetime NOT NULL,
+	`path` varchar(255) NOT NULL,
+	`views` integer NOT NULL,
+	`visitors` integer NOT NULL
+);
+CREATE UNIQUE INDEX `idx_daily_page_stats_period_path` ON `daily_page_stats` (`period`, `path`);
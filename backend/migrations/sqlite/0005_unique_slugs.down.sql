@@ -0,0 +1,6 @@
+DROP INDEX `idx_articles_slug`;
+CREATE INDEX `idx_articles_slug` ON `articles` (`slug`);
+DROP INDEX `idx_categories_slug`;
+CREATE INDEX `idx_categories_slug` ON `categories` (`slug`);
+DROP INDEX `idx_tags_slug`;
+CREATE INDEX `idx_tags_slug` ON `tags` (`slug`);
@@ -0,0 +1,13 @@
+UPDATE `articles` SET `slug` = 'item-' || `slug` WHERE `slug` <> '' AND `slug` NOT GLOB '*[^0-9]*';
+UPDATE `articles` SET `slug` = NULL WHERE `slug` = '' OR `id` NOT IN (SELECT MIN(`id`) FROM `articles` WHERE `slug` IS NOT NULL GROUP BY `slug`);
+DROP INDEX IF EXISTS `idx_articles_slug`;
+CREATE UNIQUE INDEX `idx_articles_slug` ON `articles` (`slug`);
+UPDATE `categories` SET `slug` = 'item-' || `slug` WHERE `slug` <> '' AND `slug` NOT GLOB '*[^0-9]*';
+UPDATE `categories` SET `slug` = NULL WHERE `slug` = '' OR `id` NOT IN (SELECT MIN(`id`) FROM `categories` WHERE `slug` IS NOT NULL GROUP BY `slug`);
+DROP INDEX IF EXISTS `idx_categories_slug`;
+CREATE UNIQUE INDEX `idx_categories_slug` ON `categories` (`slug`);
+UPDATE `tags` SET `slug` = 'item-' || `slug` WHERE `slug` <> '' AND `slug` NOT GLOB '*[^0-9]*';
+UPDATE `tags` SET `slug` = NULL WHERE `slug` = '' OR `id` NOT IN (SELECT MIN(`id`) FROM `tags` WHERE `slug` IS NOT NULL GROUP BY `slug`);
+DROP INDEX IF EXISTS `idx_tags_slug`;
+CREATE UNIQUE INDEX `idx_tags_slug` ON `tags` (`slug`);
+UPDATE `series` SET `slug` = 'item-' || `slug` WHERE `slug` <> '' AND `slug` NOT GLOB '*[^0-9]*';
@@ -7,17 +7,33 @@ import (
 )
 
 type Article struct {
-	ID         uint           `gorm:"primaryKey" json:"id"`
-	Title      string         `gorm:"type:varchar(255);not null" json:"title"`
-	Content    string         `gorm:"type:text;not null" json:"content"`
-	AuthorID   uint           `gorm:"not null" json:"author_id"`
-	Author     User           `gorm:"foreignKey:AuthorID" json:"author"`
-	CategoryID *uint          `json:"category_id"`
-	Category   Category       `gorm:"foreignKey:CategoryID" json:"category"`
-	Tags       []Tag          `gorm:"many2many:article_tags;" json:"tags"`
-	Views      uint           `json:"views"`
-	Likes      uint           `json:"likes"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           uint              `gorm:"primaryKey" json:"id"`
+	Title        string            `gorm:"type:varchar(255);not null" json:"title"`
+	Slug         string            `gorm:"type:varchar(191);uniqueIndex" json:"slug"`
+	Content      string            `gorm:"type:text;not null" json:"content,omitempty"` // Markdown source
+	ContentHTML  string            `gorm:"size:16777215" json:"content_html,omitempty"` // Sanitized render of Content; mediumtext on MySQL
+	Excerpt      string            `gorm:"type:text" json:"excerpt"`
+	TOC          string            `gorm:"column:toc;type:text" json:"toc,omitempty"` // JSON array of headings
+	WordCount    int               `json:"word_count"`
+	ReadingTime  int               `json:"reading_time"` // Minutes
+	AuthorID     uint              `gorm:"not null" json:"author_id"`
+	Author       User              `gorm:"foreignKey:AuthorID" json:"author"`
+	CategoryID   *uint             `json:"category_id"`
+	Category     Category          `gorm:"foreignKey:CategoryID" json:"category"`
+	Tags         []Tag             `gorm:"many2many:article_tags;" json:"tags"`
+	CoverMediaID *uint             `json:"cover_media_id"`
+	CoverURL     string            `gorm:"type:varchar(512)" json:"cover_url"`
+	Pinned       bool              `gorm:"index" json:"pinned"`
+	PinOrder     int               `json:"pin_order"` // Lower values are listed first
+	Featured     bool              `gorm:"index" json:"featured"`
+	Draft        bool              `gorm:"index" json:"draft"` // Hidden from readers until published
+	SeriesID     *uint             `gorm:"index" json:"series_id"`
+	SeriesOrder  int               `json:"series_order"`
+	SeriesNav    *SeriesNavigation `gorm:"-" json:"series_nav,omitempty"` // Filled in by GetArticle
+	ImportSource string            `gorm:"type:varchar(255)" json:"-"`    // Markdown file the article was imported from; only such articles are updated by imports
+	Views        uint              `json:"views"`
+	Likes        uint              `json:"likes"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt    `gorm:"index" json:"-"`
 }
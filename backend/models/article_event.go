@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Types of ArticleEvent.
+const (
+	ArticleEventView = "view"
+	ArticleEventLike = "like"
+)
+
+// ArticleEvent records a single view or like of an article. The counters on
+// Article only hold totals; the events behind the admin statistics keep
+// when each one happened and where the reader came from.
+type ArticleEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ArticleID uint      `gorm:"not null;index" json:"article_id"`
+	Type      string    `gorm:"type:varchar(16);not null;index:idx_article_events_type_created_at,priority:1" json:"type"`
+	Referrer  string    `gorm:"type:varchar(255)" json:"referrer"` // Host of the referring site, empty for direct visits
+	CreatedAt time.Time `gorm:"index:idx_article_events_type_created_at,priority:2" json:"created_at"`
+}
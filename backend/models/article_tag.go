@@ -0,0 +1,8 @@
+package models
+
+// ArticleTag is a row of the article_tags join table behind Article.Tags,
+// for queries on the links themselves.
+type ArticleTag struct {
+	ArticleID uint `gorm:"primaryKey" json:"article_id"`
+	TagID     uint `gorm:"primaryKey" json:"tag_id"`
+}
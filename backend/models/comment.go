@@ -7,13 +7,14 @@ import (
 )
 
 type Comment struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Content   string         `gorm:"type:text;not null" json:"content"`
-	ArticleID uint           `gorm:"not null" json:"article_id"`
-	Article   Article        `gorm:"foreignKey:ArticleID" json:"-"`
-	UserID    uint           `gorm:"not null" json:"user_id"`
-	User      User           `gorm:"foreignKey:UserID" json:"user"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Content     string         `gorm:"type:text;not null" json:"content"`
+	ContentHTML string         `gorm:"type:text" json:"content_html"` // Sanitized render of Content
+	ArticleID   uint           `gorm:"not null" json:"article_id"`
+	Article     Article        `gorm:"foreignKey:ArticleID" json:"-"`
+	UserID      uint           `gorm:"not null" json:"user_id"`
+	User        User           `gorm:"foreignKey:UserID" json:"user"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
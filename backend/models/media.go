@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Media records a file stored in the uploads directory.
+type Media struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;index" json:"user_id"`
+	Filename     string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"filename"` // Name on disk
+	OriginalName string    `gorm:"type:varchar(255)" json:"original_name"`
+	URL          string    `gorm:"type:varchar(512);not null" json:"url"`
+	Size         int64     `json:"size"`
+	MimeType     string    `gorm:"type:varchar(100)" json:"mime_type"`
+	Checksum     string    `gorm:"type:varchar(64)" json:"checksum"` // Hex SHA-256
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// PageView is a raw page view recorded by the analytics beacon. Neither the
+// IP address nor a cookie is stored: VisitorHash is derived from them with a
+// salt that changes every day, so it only tells visitors apart within a day.
+type PageView struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Path        string    `gorm:"type:varchar(255);not null" json:"path"`
+	Referrer    string    `gorm:"type:varchar(255)" json:"referrer"` // Host of the referring site, empty for direct visits
+	UTMSource   string    `gorm:"column:utm_source;type:varchar(100)" json:"utm_source"`
+	UTMMedium   string    `gorm:"column:utm_medium;type:varchar(100)" json:"utm_medium"`
+	UTMCampaign string    `gorm:"column:utm_campaign;type:varchar(100)" json:"utm_campaign"`
+	Device      string    `gorm:"type:varchar(16)" json:"device"` // desktop, mobile or tablet
+	Browser     string    `gorm:"type:varchar(32)" json:"browser"`
+	OS          string    `gorm:"column:os;type:varchar(32)" json:"os"`
+	VisitorHash string    `gorm:"type:varchar(64);not null" json:"-"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
+
+// HourlyPageStat counts the views and unique visitors of a path in one hour,
+// rolled up from PageView. The row with an empty path holds the totals of
+// the whole site.
+type HourlyPageStat struct {
+	ID       uint      `gorm:"primaryKey" json:"-"`
+	Period   time.Time `gorm:"not null;uniqueIndex:idx_hourly_page_stats_period_path,priority:1" json:"period"` // Start of the hour
+	Path     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_hourly_page_stats_period_path,priority:2" json:"path"`
+	Views    int64     `gorm:"not null" json:"views"`
+	Visitors int64     `gorm:"not null" json:"visitors"`
+}
+
+// DailyPageStat is HourlyPageStat for a whole day.
+type DailyPageStat struct {
+	ID       uint      `gorm:"primaryKey" json:"-"`
+	Period   time.Time `gorm:"not null;uniqueIndex:idx_daily_page_stats_period_path,priority:1" json:"period"` // Local midnight
+	Path     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_daily_page_stats_period_path,priority:2" json:"path"`
+	Views    int64     `gorm:"not null" json:"views"`
+	Visitors int64     `gorm:"not null" json:"visitors"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Series groups articles into an ordered multi-part collection.
+type Series struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Title       string    `gorm:"type:varchar(255);not null" json:"title"`
+	Slug        string    `gorm:"type:varchar(120);uniqueIndex;not null" json:"slug"`
+	Description string    `gorm:"type:text" json:"description"`
+	AuthorID    uint      `gorm:"not null" json:"author_id"`
+	Articles    []Article `gorm:"foreignKey:SeriesID" json:"articles,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SeriesArticleRef is a short reference to a neighbouring article.
+type SeriesArticleRef struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+// SeriesNavigation describes where an article sits inside its series.
+type SeriesNavigation struct {
+	ID       uint              `json:"id"`
+	Title    string            `json:"title"`
+	Slug     string            `json:"slug"`
+	Position int               `json:"position"` // 1-based
+	Total    int               `json:"total"`
+	Previous *SeriesArticleRef `json:"previous"`
+	Next     *SeriesArticleRef `json:"next"`
+}
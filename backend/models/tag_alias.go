@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// TagAlias is an alternative spelling that resolves to a canonical tag.
+type TagAlias struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"` // Normalized
+	TagID     uint      `gorm:"not null;index" json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// TaxonomyMeta holds the landing page metadata shared by categories and tags.
+type TaxonomyMeta struct {
+	Slug         string `gorm:"type:varchar(120);uniqueIndex" json:"slug"`
+	Description  string `gorm:"type:text" json:"description"`
+	Color        string `gorm:"type:varchar(7)" json:"color"` // #RRGGBB
+	Icon         string `gorm:"type:varchar(100)" json:"icon"`
+	CoverMediaID *uint  `json:"cover_media_id"`
+	CoverURL     string `gorm:"type:varchar(512)" json:"cover_url"`
+}
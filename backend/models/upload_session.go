@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// UploadSession tracks a resumable chunked upload until it is assembled.
+// Chunks live on disk under upload_chunks/<ID>/ while the session is open.
+type UploadSession struct {
+	ID          string    `gorm:"type:varchar(64);primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	Filename    string    `gorm:"type:varchar(255);not null" json:"filename"`
+	Extension   string    `gorm:"type:varchar(16);not null" json:"extension"`
+	TotalSize   int64     `gorm:"not null" json:"total_size"`
+	ChunkSize   int64     `gorm:"not null" json:"chunk_size"`
+	TotalChunks int       `gorm:"not null" json:"total_chunks"`
+	Checksum    string    `gorm:"type:varchar(64)" json:"checksum"` // Hex SHA-256 of the whole file
+	ExpiresAt   time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
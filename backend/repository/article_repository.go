@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+type gormArticles struct {
+	db *gorm.DB
+}
+
+// scope limits a query to published articles unless drafts are included.
+func (r gormArticles) scope(includeDrafts bool) *gorm.DB {
+	query := r.db.Model(&models.Article{})
+	if !includeDrafts {
+		query = query.Where("articles.draft = ?", false)
+	}
+	return query
+}
+
+func (r gormArticles) List(q ArticleQuery) ([]models.Article, error) {
+	query := r.scope(q.IncludeDrafts)
+	if q.Columns != nil {
+		columns := make([]string, len(q.Columns))
+		for i, col := range q.Columns {
+			columns[i] = "articles." + col
+		}
+		query = query.Select(columns)
+	}
+
+	if q.WithAuthor {
+		query = query.Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "avatar_url")
+		})
+	}
+	if q.WithCategory {
+		query = query.Preload("Category")
+	}
+	if q.WithTags {
+		query = query.Preload("Tags")
+	}
+
+	if q.Search != "" {
+		query = query.Where(database.Contains(q.Search, "articles.title", "articles.content"))
+	}
+	if q.CategoryIDs != nil {
+		query = query.Where("articles.category_id IN ?", q.CategoryIDs)
+	}
+	if q.TagID != 0 {
+		query = query.Joins("JOIN article_tags ON article_tags.article_id = articles.id").Where("article_tags.tag_id = ?", q.TagID)
+	}
+	if q.AuthorID != 0 {
+		query = query.Where("articles.author_id = ?", q.AuthorID)
+	}
+	if q.SeriesID != 0 {
+		query = query.Where("articles.series_id = ?", q.SeriesID)
+	}
+
+	switch {
+	case q.SeriesID != 0:
+		query = query.Order("articles.series_order ASC").Order("articles.id ASC")
+	case q.Newest:
+		query = query.Order("articles.created_at DESC").Order("articles.id DESC")
+	case q.Featured:
+		query = query.Where("articles.featured = ?", true).
+			Order("articles.pin_order ASC").Order("articles.created_at DESC")
+	default:
+		// Pinned articles always come first
+		query = query.Order("articles.pinned DESC").Order("articles.pin_order ASC").Order("articles.id ASC")
+	}
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+
+	var articles []models.Article
+	return articles, query.Find(&articles).Error
+}
+
+func (r gormArticles) Get(id uint, includeDrafts bool) (*models.Article, error) {
+	var article models.Article
+	err := r.scope(includeDrafts).Preload("Author").Preload("Category").Preload("Tags").First(&article, id).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &article, nil
+}
+
+func (r gormArticles) Find(id uint) (*models.Article, error) {
+	var article models.Article
+	if err := r.db.First(&article, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &article, nil
+}
+
+func (r gormArticles) LastChange() (time.Time, error) {
+	return lastChange(r.db, &models.Article{})
+}
+
+func (r gormArticles) Create(article *models.Article) error {
+	return r.db.Create(article).Error
+}
+
+func (r gormArticles) Update(article *models.Article, tags []models.Tag) error {
+	if tags != nil {
+		if err := r.db.Model(article).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+	}
+	return r.db.Save(article).Error
+}
+
+func (r gormArticles) Delete(ids []uint) (int64, error) {
+	result := r.db.Where("id IN ?", ids).Delete(&models.Article{})
+	return result.RowsAffected, result.Error
+}
+
+func (r gormArticles) IncrementLikes(id uint) error {
+	return r.db.Model(&models.Article{ID: id}).UpdateColumn("likes", gorm.Expr("likes + 1")).Error
+}
+
+func (r gormArticles) IncrementViews(id uint) error {
+	return r.db.Model(&models.Article{ID: id}).UpdateColumn("views", gorm.Expr("views + 1")).Error
+}
+
+func (r gormArticles) RecordEvent(event *models.ArticleEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r gormArticles) CountDependents(ids []uint) (comments, tagLinks int64, err error) {
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+	if err = r.db.Unscoped().Model(&models.Comment{}).Where("article_id IN ?", ids).Count(&comments).Error; err != nil {
+		return
+	}
+	err = r.db.Model(&models.ArticleTag{}).Where("article_id IN ?", ids).Count(&tagLinks).Error
+	return
+}
+
+func (r gormArticles) SlugTaken(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Article{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r gormArticles) UniqueSlug(title string, excludeID uint) (string, error) {
+	return database.UniqueSlug(r.db, &models.Article{}, title, excludeID)
+}
+
+func (r gormArticles) NextSeriesOrder(seriesID uint) (int, error) {
+	var max *int
+	err := r.db.Model(&models.Article{}).Where("series_id = ?", seriesID).Select("MAX(series_order)").Scan(&max).Error
+	if err != nil || max == nil {
+		return 1, err
+	}
+	return *max + 1, nil
+}
+
+func (r gormArticles) SeriesParts(seriesID uint) ([]models.SeriesArticleRef, error) {
+	var parts []models.SeriesArticleRef
+	err := r.scope(false).Select("id", "title").
+		Where("series_id = ?", seriesID).
+		Order("series_order ASC").Order("id ASC").
+		Scan(&parts).Error
+	return parts, err
+}
+
+func (r gormArticles) FindMedia(id uint) (*models.Media, error) {
+	var media models.Media
+	if err := r.db.First(&media, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &media, nil
+}
+
+func (r gormArticles) FindAll(ids []uint) ([]models.Article, error) {
+	var articles []models.Article
+	err := r.db.Unscoped().Where("id IN ?", ids).Find(&articles).Error
+	return articles, err
+}
+
+func (r gormArticles) Adjacent(article *models.Article) (prev, next *models.Article, err error) {
+	var before, after []models.Article
+
+	err = r.scope(false).Select("id", "title", "created_at").
+		Where("created_at < ? OR (created_at = ? AND id < ?)", article.CreatedAt, article.CreatedAt, article.ID).
+		Order("created_at DESC").Order("id DESC").Limit(1).Find(&before).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = r.scope(false).Select("id", "title", "created_at").
+		Where("created_at > ? OR (created_at = ? AND id > ?)", article.CreatedAt, article.CreatedAt, article.ID).
+		Order("created_at ASC").Order("id ASC").Limit(1).Find(&after).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(before) > 0 {
+		prev = &before[0]
+	}
+	if len(after) > 0 {
+		next = &after[0]
+	}
+	return prev, next, nil
+}
+
+func (r gormArticles) RelatedCandidates(article *models.Article, limit int) ([]RelatedCandidate, error) {
+	var ownTags []uint
+	if err := r.db.Model(&models.ArticleTag{}).Where("article_id = ?", article.ID).Pluck("tag_id", &ownTags).Error; err != nil {
+		return nil, err
+	}
+	if len(ownTags) == 0 && article.CategoryID == nil {
+		return nil, nil
+	}
+
+	shared := r.db.Model(&models.ArticleTag{}).Select("article_id, COUNT(*) AS shared_tags").
+		Where("tag_id IN ?", ownTags).Group("article_id")
+	query := r.scope(false).
+		Select("articles.id, articles.title, articles.excerpt, articles.cover_url, articles.category_id, articles.created_at, COALESCE(shared.shared_tags, 0) AS shared_tags").
+		Joins("LEFT JOIN (?) AS shared ON shared.article_id = articles.id", shared).
+		Where("articles.id <> ?", article.ID)
+	if article.CategoryID != nil {
+		query = query.Where("shared.article_id IS NOT NULL OR articles.category_id = ?", *article.CategoryID)
+	} else {
+		query = query.Where("shared.article_id IS NOT NULL")
+	}
+
+	var rows []struct {
+		ID         uint
+		Title      string
+		Excerpt    string
+		CoverURL   string
+		CategoryID *uint
+		CreatedAt  time.Time
+		SharedTags int
+	}
+	if err := query.Order("shared_tags DESC").Order("articles.created_at DESC").
+		Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	candidates := make([]RelatedCandidate, len(rows))
+	for i, row := range rows {
+		candidates[i] = RelatedCandidate{
+			Article: models.Article{
+				ID:         row.ID,
+				Title:      row.Title,
+				Excerpt:    row.Excerpt,
+				CoverURL:   row.CoverURL,
+				CategoryID: row.CategoryID,
+				CreatedAt:  row.CreatedAt,
+			},
+			SharedTags: row.SharedTags,
+		}
+	}
+	return candidates, nil
+}
+
+func (r gormArticles) Neighbours(ids []uint) ([]uint, error) {
+	var categoryIDs []uint
+	err := r.db.Unscoped().Model(&models.Article{}).Where("id IN ? AND category_id IS NOT NULL", ids).
+		Pluck("category_id", &categoryIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tags := r.db.Model(&models.ArticleTag{}).Select("tag_id").Where("article_id IN ?", ids)
+	shared := r.db.Model(&models.ArticleTag{}).Select("article_id").Where("tag_id IN (?)", tags)
+	query := r.scope(false).Where("articles.id IN (?)", shared)
+	if len(categoryIDs) > 0 {
+		query = r.scope(false).Where("articles.id IN (?) OR articles.category_id IN ?", shared, categoryIDs)
+	}
+
+	var neighbours []uint
+	err = query.Pluck("articles.id", &neighbours).Error
+	return neighbours, err
+}
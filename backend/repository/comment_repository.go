@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+type gormComments struct {
+	db *gorm.DB
+}
+
+func (r gormComments) ListByArticle(articleID uint) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Where("article_id = ?", articleID).Preload("User").Order("id ASC").Find(&comments).Error
+	return comments, err
+}
+
+func (r gormComments) Recent(articleID uint, limit int) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Where("article_id = ?", articleID).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username")
+		}).
+		Order("created_at DESC").Order("id DESC").
+		Limit(limit).
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r gormComments) LastChange(articleID uint) (time.Time, error) {
+	return lastChange(r.db, &models.Comment{}, "article_id = ?", articleID)
+}
+
+func (r gormComments) Get(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := r.db.Preload("User").First(&comment, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &comment, nil
+}
+
+func (r gormComments) Create(comment *models.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r gormComments) Delete(comment *models.Comment) error {
+	return r.db.Delete(comment).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewStore returns the GORM implementation of Store. db may be a
+// transaction.
+func NewStore(db *gorm.DB) Store {
+	return gormStore{db: db}
+}
+
+func (s gormStore) Articles() ArticleRepository  { return gormArticles{db: s.db} }
+func (s gormStore) Comments() CommentRepository  { return gormComments{db: s.db} }
+func (s gormStore) Users() UserRepository        { return gormUsers{db: s.db} }
+func (s gormStore) Taxonomy() TaxonomyRepository { return gormTaxonomy{db: s.db} }
+func (s gormStore) Series() SeriesRepository     { return gormSeries{db: s.db} }
+func (s gormStore) Media() MediaRepository       { return gormMedia{db: s.db} }
+func (s gormStore) Trash() TrashRepository       { return gormTrash{db: s.db} }
+
+func (s gormStore) Transaction(fn func(tx Store) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(gormStore{db: tx})
+	})
+}
+
+// translate maps GORM's not-found error to ErrNotFound.
+func translate(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// lastChange returns the latest updated_at or deleted_at of the rows of
+// model matching the conditions, trashed ones included.
+func lastChange(db *gorm.DB, model interface{}, conds ...interface{}) (time.Time, error) {
+	var last time.Time
+	for _, column := range []string{"updated_at", "deleted_at"} {
+		var times []time.Time
+		query := db.Unscoped().Model(model)
+		if len(conds) > 0 {
+			query = query.Where(conds[0], conds[1:]...)
+		}
+		err := query.Where(column+" IS NOT NULL").Order(column+" DESC").Limit(1).Pluck(column, &times).Error
+		if err != nil {
+			return time.Time{}, err
+		}
+		if len(times) > 0 && times[0].After(last) {
+			last = times[0]
+		}
+	}
+	return last, nil
+}
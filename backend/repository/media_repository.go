@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+type gormMedia struct {
+	db *gorm.DB
+}
+
+func (r gormMedia) Create(media *models.Media) error {
+	return r.db.Create(media).Error
+}
+
+func (r gormMedia) FindByChecksum(checksum string) (*models.Media, error) {
+	var media models.Media
+	if err := r.db.Where("checksum = ?", checksum).First(&media).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &media, nil
+}
+
+func (r gormMedia) CreateSession(session *models.UploadSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r gormMedia) FindSession(id string, userID uint) (*models.UploadSession, error) {
+	var session models.UploadSession
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &session, nil
+}
+
+func (r gormMedia) TouchSession(id string, expiresAt time.Time) error {
+	return r.db.Model(&models.UploadSession{}).Where("id = ?", id).Update("expires_at", expiresAt).Error
+}
+
+func (r gormMedia) DeleteSession(id string) error {
+	return r.db.Where("id = ?", id).Delete(&models.UploadSession{}).Error
+}
+
+func (r gormMedia) ExpiredSessions(before time.Time) ([]models.UploadSession, error) {
+	var sessions []models.UploadSession
+	err := r.db.Where("expires_at < ?", before).Find(&sessions).Error
+	return sessions, err
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+)
+
+type media struct{ s *Store }
+
+func (r media) Create(m *models.Media) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	m.ID = r.s.nextID()
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	r.s.media[m.ID] = *m
+	return nil
+}
+
+func (r media) FindByChecksum(checksum string) (*models.Media, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, m := range r.s.media {
+		if m.Checksum == checksum {
+			return &m, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r media) CreateSession(session *models.UploadSession) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = session.CreatedAt
+	r.s.sessions[session.ID] = *session
+	return nil
+}
+
+func (r media) FindSession(id string, userID uint) (*models.UploadSession, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	session, ok := r.s.sessions[id]
+	if !ok || session.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &session, nil
+}
+
+func (r media) TouchSession(id string, expiresAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	session, ok := r.s.sessions[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	session.ExpiresAt = expiresAt
+	r.s.sessions[id] = session
+	return nil
+}
+
+func (r media) DeleteSession(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.sessions, id)
+	return nil
+}
+
+func (r media) ExpiredSessions(before time.Time) ([]models.UploadSession, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var sessions []models.UploadSession
+	for _, session := range r.s.sessions {
+		if session.ExpiresAt.Before(before) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
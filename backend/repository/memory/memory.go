@@ -0,0 +1,723 @@
+// Package memory implements the repository interfaces in memory so services
+// can be exercised without a database.
+package memory
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/utils"
+	"gorm.io/gorm"
+)
+
+// Store is an in-memory repository.Store. Records are copied on the way in
+// and out, so callers never share them with the store. Deleted articles,
+// comments, categories and tags stay in their maps with DeletedAt set, like
+// soft-deleted rows.
+type Store struct {
+	mu sync.Mutex
+	records
+}
+
+type records struct {
+	lastID     uint
+	articles   map[uint]models.Article
+	articleTag map[uint][]uint // Article ID to tag IDs
+	comments   map[uint]models.Comment
+	users      map[uint]models.User
+	tags       map[uint]models.Tag
+	aliases    map[uint]models.TagAlias
+	categories map[uint]models.Category
+	series     map[uint]models.Series
+	media      map[uint]models.Media
+	sessions   map[string]models.UploadSession
+	events     []models.ArticleEvent
+}
+
+var _ repository.Store = (*Store)(nil)
+
+func NewStore() *Store {
+	return &Store{records: records{
+		articles:   map[uint]models.Article{},
+		articleTag: map[uint][]uint{},
+		comments:   map[uint]models.Comment{},
+		users:      map[uint]models.User{},
+		tags:       map[uint]models.Tag{},
+		aliases:    map[uint]models.TagAlias{},
+		categories: map[uint]models.Category{},
+		series:     map[uint]models.Series{},
+		media:      map[uint]models.Media{},
+		sessions:   map[string]models.UploadSession{},
+	}}
+}
+
+func (s *Store) Articles() repository.ArticleRepository  { return articles{s} }
+func (s *Store) Comments() repository.CommentRepository  { return comments{s} }
+func (s *Store) Users() repository.UserRepository        { return users{s} }
+func (s *Store) Taxonomy() repository.TaxonomyRepository { return taxonomy{s} }
+func (s *Store) Series() repository.SeriesRepository     { return series{s} }
+func (s *Store) Media() repository.MediaRepository       { return media{s} }
+func (s *Store) Trash() repository.TrashRepository       { return trash{s} }
+
+// Transaction restores the state from before fn when fn fails. Unlike a
+// database transaction it does not hide fn's writes from concurrent callers.
+func (s *Store) Transaction(fn func(tx repository.Store) error) error {
+	s.mu.Lock()
+	saved := s.snapshot()
+	s.mu.Unlock()
+
+	if err := fn(s); err != nil {
+		s.mu.Lock()
+		saved.lastID = s.lastID // IDs are not reused after a rollback either
+		s.records = saved
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// snapshot copies the records of the store. Callers hold s.mu.
+func (s *Store) snapshot() records {
+	articleTag := make(map[uint][]uint, len(s.articleTag))
+	for id, tags := range s.articleTag {
+		articleTag[id] = append([]uint(nil), tags...)
+	}
+	return records{
+		lastID:     s.lastID,
+		articles:   maps.Clone(s.articles),
+		articleTag: articleTag,
+		comments:   maps.Clone(s.comments),
+		users:      maps.Clone(s.users),
+		tags:       maps.Clone(s.tags),
+		aliases:    maps.Clone(s.aliases),
+		categories: maps.Clone(s.categories),
+		series:     maps.Clone(s.series),
+		media:      maps.Clone(s.media),
+		sessions:   maps.Clone(s.sessions),
+		events:     slices.Clone(s.events),
+	}
+}
+
+// nextID hands out IDs shared by all record types, which keeps them unique
+// like auto-increment keys. Callers hold s.mu.
+func (s *Store) nextID() uint {
+	s.lastID++
+	return s.lastID
+}
+
+// Events returns the recorded views and likes, oldest first.
+func (s *Store) Events() []models.ArticleEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.events)
+}
+
+// AddMedia stores an uploaded file that can be used as a cover.
+func (s *Store) AddMedia(media models.Media) uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if media.ID == 0 {
+		media.ID = s.nextID()
+	}
+	s.media[media.ID] = media
+	return media.ID
+}
+
+// uniqueSlug mirrors database.UniqueSlug. taken reports whether a slug is
+// used by a record other than the excluded one.
+func uniqueSlug(name string, taken func(slug string) bool) string {
+	base := utils.Slugify(name)
+	if base == "" {
+		base = "item"
+	} else if utils.NumericSlug(base) {
+		base = "item-" + base
+	}
+	slug := base
+	for i := 2; taken(slug); i++ {
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	return slug
+}
+
+type articles struct{ s *Store }
+
+// load fills in the associations of an article. Callers hold s.mu.
+func (r articles) load(article *models.Article, author, category, tags bool) {
+	if author {
+		article.Author = r.s.users[article.AuthorID]
+	}
+	if category && article.CategoryID != nil {
+		article.Category = r.s.categories[*article.CategoryID]
+	}
+	if tags {
+		article.Tags = []models.Tag{}
+		for _, id := range r.s.articleTag[article.ID] {
+			article.Tags = append(article.Tags, r.s.tags[id])
+		}
+	}
+}
+
+func (r articles) List(q repository.ArticleQuery) ([]models.Article, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	search := strings.ToLower(q.Search)
+	list := []models.Article{}
+	for _, a := range r.s.articles {
+		if a.DeletedAt.Valid || a.Draft && !q.IncludeDrafts || q.Featured && !a.Featured {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(a.Title), search) && !strings.Contains(strings.ToLower(a.Content), search) {
+			continue
+		}
+		if q.CategoryIDs != nil && (a.CategoryID == nil || !contains(q.CategoryIDs, *a.CategoryID)) {
+			continue
+		}
+		if q.TagID != 0 && !contains(r.s.articleTag[a.ID], q.TagID) {
+			continue
+		}
+		if q.AuthorID != 0 && a.AuthorID != q.AuthorID {
+			continue
+		}
+		if q.SeriesID != 0 && (a.SeriesID == nil || *a.SeriesID != q.SeriesID) {
+			continue
+		}
+		r.load(&a, q.WithAuthor, q.WithCategory, q.WithTags)
+		list = append(list, a)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		switch {
+		case q.SeriesID != 0:
+			if a.SeriesOrder != b.SeriesOrder {
+				return a.SeriesOrder < b.SeriesOrder
+			}
+			return a.ID < b.ID
+		case q.Newest:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+			return a.ID > b.ID
+		case q.Featured:
+			if a.PinOrder != b.PinOrder {
+				return a.PinOrder < b.PinOrder
+			}
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		if a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		if a.PinOrder != b.PinOrder {
+			return a.PinOrder < b.PinOrder
+		}
+		return a.ID < b.ID
+	})
+	if q.Limit > 0 && len(list) > q.Limit {
+		list = list[:q.Limit]
+	}
+	return list, nil
+}
+
+func (r articles) Get(id uint, includeDrafts bool) (*models.Article, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	article, ok := r.s.articles[id]
+	if !ok || article.DeletedAt.Valid || article.Draft && !includeDrafts {
+		return nil, repository.ErrNotFound
+	}
+	r.load(&article, true, true, true)
+	return &article, nil
+}
+
+func (r articles) Find(id uint) (*models.Article, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	article, ok := r.s.articles[id]
+	if !ok || article.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	return &article, nil
+}
+
+func (r articles) FindAll(ids []uint) ([]models.Article, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	list := []models.Article{}
+	for _, id := range ids {
+		if article, ok := r.s.articles[id]; ok {
+			list = append(list, article)
+		}
+	}
+	return list, nil
+}
+
+func (r articles) LastChange() (time.Time, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var last time.Time
+	for _, a := range r.s.articles {
+		last = later(last, a.UpdatedAt, a.DeletedAt)
+	}
+	return last, nil
+}
+
+// later returns the latest of last, updated and deleted.
+func later(last, updated time.Time, deleted gorm.DeletedAt) time.Time {
+	if updated.After(last) {
+		last = updated
+	}
+	if deleted.Valid && deleted.Time.After(last) {
+		last = deleted.Time
+	}
+	return last
+}
+
+func (r articles) Create(article *models.Article) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	article.ID = r.s.nextID()
+	article.CreatedAt = time.Now()
+	article.UpdatedAt = article.CreatedAt
+	r.s.articleTag[article.ID] = tagIDs(article.Tags)
+	stored := *article
+	stored.Author, stored.Category, stored.Tags = models.User{}, models.Category{}, nil
+	r.s.articles[article.ID] = stored
+	return nil
+}
+
+func (r articles) Update(article *models.Article, tags []models.Tag) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if stored, ok := r.s.articles[article.ID]; !ok || stored.DeletedAt.Valid {
+		return repository.ErrNotFound
+	}
+	if tags != nil {
+		r.s.articleTag[article.ID] = tagIDs(tags)
+	}
+	article.UpdatedAt = time.Now()
+	stored := *article
+	stored.Author, stored.Category, stored.Tags = models.User{}, models.Category{}, nil
+	r.s.articles[article.ID] = stored
+	return nil
+}
+
+func (r articles) Delete(ids []uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, id := range ids {
+		if article, ok := r.s.articles[id]; ok && !article.DeletedAt.Valid {
+			article.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			r.s.articles[id] = article
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r articles) increment(id uint, counter func(*models.Article)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	article, ok := r.s.articles[id]
+	if !ok || article.DeletedAt.Valid {
+		return repository.ErrNotFound
+	}
+	counter(&article)
+	r.s.articles[id] = article
+	return nil
+}
+
+func (r articles) IncrementLikes(id uint) error {
+	return r.increment(id, func(a *models.Article) { a.Likes++ })
+}
+
+func (r articles) IncrementViews(id uint) error {
+	return r.increment(id, func(a *models.Article) { a.Views++ })
+}
+
+func (r articles) RecordEvent(event *models.ArticleEvent) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if article, ok := r.s.articles[event.ArticleID]; !ok || article.DeletedAt.Valid {
+		return repository.ErrNotFound
+	}
+	event.ID = r.s.nextID()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	r.s.events = append(r.s.events, *event)
+	return nil
+}
+
+func (r articles) CountDependents(ids []uint) (comments, tagLinks int64, err error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, c := range r.s.comments {
+		if contains(ids, c.ArticleID) {
+			comments++
+		}
+	}
+	for _, id := range ids {
+		tagLinks += int64(len(r.s.articleTag[id]))
+	}
+	return comments, tagLinks, nil
+}
+
+// slugTaken reports whether another article, trashed ones included, uses
+// slug. Callers hold s.mu.
+func (r articles) slugTaken(slug string, excludeID uint) bool {
+	for _, a := range r.s.articles {
+		if a.Slug == slug && a.ID != excludeID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r articles) SlugTaken(slug string, excludeID uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.slugTaken(slug, excludeID), nil
+}
+
+func (r articles) UniqueSlug(title string, excludeID uint) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return uniqueSlug(title, func(slug string) bool { return r.slugTaken(slug, excludeID) }), nil
+}
+
+func (r articles) NextSeriesOrder(seriesID uint) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	next := 1
+	for _, a := range r.s.articles {
+		if !a.DeletedAt.Valid && a.SeriesID != nil && *a.SeriesID == seriesID && a.SeriesOrder >= next {
+			next = a.SeriesOrder + 1
+		}
+	}
+	return next, nil
+}
+
+func (r articles) SeriesParts(seriesID uint) ([]models.SeriesArticleRef, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var list []models.Article
+	for _, a := range r.s.articles {
+		if !a.DeletedAt.Valid && !a.Draft && a.SeriesID != nil && *a.SeriesID == seriesID {
+			list = append(list, a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SeriesOrder != list[j].SeriesOrder {
+			return list[i].SeriesOrder < list[j].SeriesOrder
+		}
+		return list[i].ID < list[j].ID
+	})
+
+	parts := make([]models.SeriesArticleRef, len(list))
+	for i, a := range list {
+		parts[i] = models.SeriesArticleRef{ID: a.ID, Title: a.Title}
+	}
+	return parts, nil
+}
+
+// published returns the articles visible to readers. Callers hold s.mu.
+func (r articles) published() []models.Article {
+	var list []models.Article
+	for _, a := range r.s.articles {
+		if !a.DeletedAt.Valid && !a.Draft {
+			list = append(list, a)
+		}
+	}
+	return list
+}
+
+func (r articles) Adjacent(article *models.Article) (prev, next *models.Article, err error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	before := func(a, b models.Article) bool {
+		return a.CreatedAt.Before(b.CreatedAt) || a.CreatedAt.Equal(b.CreatedAt) && a.ID < b.ID
+	}
+	for _, a := range r.published() {
+		ref := models.Article{ID: a.ID, Title: a.Title, CreatedAt: a.CreatedAt}
+		if before(a, *article) && (prev == nil || before(*prev, a)) {
+			prev = &ref
+		}
+		if before(*article, a) && (next == nil || before(a, *next)) {
+			next = &ref
+		}
+	}
+	return prev, next, nil
+}
+
+func (r articles) RelatedCandidates(article *models.Article, limit int) ([]repository.RelatedCandidate, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	ownTags := r.s.articleTag[article.ID]
+
+	var candidates []repository.RelatedCandidate
+	for _, a := range r.published() {
+		if a.ID == article.ID {
+			continue
+		}
+		shared := 0
+		for _, id := range r.s.articleTag[a.ID] {
+			if contains(ownTags, id) {
+				shared++
+			}
+		}
+		sameCategory := article.CategoryID != nil && a.CategoryID != nil && *a.CategoryID == *article.CategoryID
+		if shared == 0 && !sameCategory {
+			continue
+		}
+		candidates = append(candidates, repository.RelatedCandidate{
+			Article: models.Article{
+				ID:         a.ID,
+				Title:      a.Title,
+				Excerpt:    a.Excerpt,
+				CoverURL:   a.CoverURL,
+				CategoryID: a.CategoryID,
+				CreatedAt:  a.CreatedAt,
+			},
+			SharedTags: shared,
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].SharedTags != candidates[j].SharedTags {
+			return candidates[i].SharedTags > candidates[j].SharedTags
+		}
+		return candidates[i].Article.CreatedAt.After(candidates[j].Article.CreatedAt)
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+func (r articles) Neighbours(ids []uint) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var tagIDs, categoryIDs []uint
+	for _, id := range ids {
+		tagIDs = append(tagIDs, r.s.articleTag[id]...)
+		if a, ok := r.s.articles[id]; ok && a.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *a.CategoryID)
+		}
+	}
+
+	neighbours := []uint{}
+	for _, a := range r.published() {
+		shares := a.CategoryID != nil && contains(categoryIDs, *a.CategoryID)
+		for _, id := range r.s.articleTag[a.ID] {
+			shares = shares || contains(tagIDs, id)
+		}
+		if shares {
+			neighbours = append(neighbours, a.ID)
+		}
+	}
+	return neighbours, nil
+}
+
+func (r articles) FindMedia(id uint) (*models.Media, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	media, ok := r.s.media[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &media, nil
+}
+
+type comments struct{ s *Store }
+
+func (r comments) ListByArticle(articleID uint) ([]models.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	list := r.byArticle(articleID)
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// byArticle returns the live comments of an article with their users.
+// Callers hold s.mu.
+func (r comments) byArticle(articleID uint) []models.Comment {
+	list := []models.Comment{}
+	for _, c := range r.s.comments {
+		if c.ArticleID == articleID && !c.DeletedAt.Valid {
+			c.User = r.s.users[c.UserID]
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+func (r comments) Recent(articleID uint, limit int) ([]models.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	list := r.byArticle(articleID)
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].ID > list[j].ID
+	})
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (r comments) LastChange(articleID uint) (time.Time, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var last time.Time
+	for _, c := range r.s.comments {
+		if c.ArticleID == articleID {
+			last = later(last, c.UpdatedAt, c.DeletedAt)
+		}
+	}
+	return last, nil
+}
+
+func (r comments) Get(id uint) (*models.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	comment, ok := r.s.comments[id]
+	if !ok || comment.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	comment.User = r.s.users[comment.UserID]
+	return &comment, nil
+}
+
+func (r comments) Create(comment *models.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	comment.ID = r.s.nextID()
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+	stored := *comment
+	stored.User = models.User{}
+	r.s.comments[comment.ID] = stored
+	return nil
+}
+
+func (r comments) Delete(comment *models.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, ok := r.s.comments[comment.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return repository.ErrNotFound
+	}
+	stored.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.s.comments[comment.ID] = stored
+	return nil
+}
+
+type users struct{ s *Store }
+
+func (r users) Count() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.s.users)), nil
+}
+
+func (r users) Find(id uint) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	user, ok := r.s.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r users) FindByUsername(username string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, user := range r.s.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r users) First() (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var first *models.User
+	for _, user := range r.s.users {
+		if first == nil || user.ID < first.ID {
+			u := user
+			first = &u
+		}
+	}
+	if first == nil {
+		return nil, repository.ErrNotFound
+	}
+	return first, nil
+}
+
+func (r users) Create(user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, u := range r.s.users {
+		if u.Username == user.Username {
+			return fmt.Errorf("username %q is already taken", user.Username)
+		}
+	}
+	user.ID = r.s.nextID()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r users) Save(user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.users[user.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	user.UpdatedAt = time.Now()
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func contains(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// without returns ids without id.
+func without(ids []uint, id uint) []uint {
+	kept := []uint{}
+	for _, v := range ids {
+		if v != id {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+func tagIDs(tags []models.Tag) []uint {
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return ids
+}
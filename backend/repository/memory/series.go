@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+)
+
+type series struct{ s *Store }
+
+func (r series) List() ([]models.Series, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	list := []models.Series{}
+	for _, s := range r.s.series {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].ID > list[j].ID
+	})
+	return list, nil
+}
+
+func (r series) Get(id uint) (*models.Series, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	s, ok := r.s.series[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &s, nil
+}
+
+func (r series) GetBySlug(slug string) (*models.Series, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, s := range r.s.series {
+		if s.Slug == slug {
+			return &s, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r series) Create(s *models.Series) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.slugTaken(s.Slug, 0) {
+		return fmt.Errorf("series slug %q already exists", s.Slug)
+	}
+	s.ID = r.s.nextID()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = s.CreatedAt
+	r.s.series[s.ID] = *s
+	return nil
+}
+
+func (r series) Save(s *models.Series) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.series[s.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	if r.slugTaken(s.Slug, s.ID) {
+		return fmt.Errorf("series slug %q already exists", s.Slug)
+	}
+	s.UpdatedAt = time.Now()
+	r.s.series[s.ID] = *s
+	return nil
+}
+
+func (r series) Delete(s *models.Series) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for id, a := range r.s.articles {
+		if a.SeriesID != nil && *a.SeriesID == s.ID {
+			a.SeriesID, a.SeriesOrder = nil, 0
+			r.s.articles[id] = a
+		}
+	}
+	delete(r.s.series, s.ID)
+	return nil
+}
+
+func (r series) ArticleCounts() (map[uint]int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	counts := map[uint]int64{}
+	for _, a := range r.s.articles {
+		if a.SeriesID != nil && !a.DeletedAt.Valid {
+			counts[*a.SeriesID]++
+		}
+	}
+	return counts, nil
+}
+
+func (r series) SetArticles(seriesID uint, articleIDs []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for id, a := range r.s.articles {
+		if a.SeriesID != nil && *a.SeriesID == seriesID && !a.DeletedAt.Valid {
+			a.SeriesID, a.SeriesOrder = nil, 0
+			r.s.articles[id] = a
+		}
+	}
+	for i, id := range articleIDs {
+		if a, ok := r.s.articles[id]; ok && !a.DeletedAt.Valid {
+			a.SeriesID, a.SeriesOrder = &seriesID, i+1
+			r.s.articles[id] = a
+		}
+	}
+	return nil
+}
+
+// slugTaken reports whether a series other than excludeID uses slug. Callers
+// hold s.mu.
+func (r series) slugTaken(slug string, excludeID uint) bool {
+	for _, s := range r.s.series {
+		if s.Slug == slug && s.ID != excludeID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r series) SlugTaken(slug string, excludeID uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.slugTaken(slug, excludeID), nil
+}
+
+func (r series) UniqueSlug(title string, excludeID uint) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return uniqueSlug(title, func(slug string) bool { return r.slugTaken(slug, excludeID) }), nil
+}
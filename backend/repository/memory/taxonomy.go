@@ -0,0 +1,465 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"gorm.io/gorm"
+)
+
+type taxonomy struct{ s *Store }
+
+// tagAliases returns the aliases of a tag in creation order. Callers hold
+// s.mu.
+func (r taxonomy) tagAliases(tagID uint) []models.TagAlias {
+	var aliases []models.TagAlias
+	for _, alias := range r.s.aliases {
+		if alias.TagID == tagID {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].ID < aliases[j].ID })
+	return aliases
+}
+
+func (r taxonomy) ListTags() ([]models.Tag, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	list := []models.Tag{}
+	for _, tag := range r.s.tags {
+		if !tag.DeletedAt.Valid {
+			tag.Aliases = r.tagAliases(tag.ID)
+			list = append(list, tag)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+func (r taxonomy) GetTag(id uint) (*models.Tag, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	tag, ok := r.s.tags[id]
+	if !ok || tag.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	tag.Aliases = r.tagAliases(tag.ID)
+	return &tag, nil
+}
+
+func (r taxonomy) GetTagBySlug(slug string) (*models.Tag, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, tag := range r.s.tags {
+		if tag.Slug == slug && !tag.DeletedAt.Valid {
+			tag.Aliases = r.tagAliases(tag.ID)
+			return &tag, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// findTag looks a tag up by lowercased name, either among the live tags or
+// among the trashed ones. Callers hold s.mu.
+func (r taxonomy) findTag(key string, trashed bool) (*models.Tag, error) {
+	for _, tag := range r.s.tags {
+		if strings.ToLower(tag.Name) == key && tag.DeletedAt.Valid == trashed {
+			return &tag, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r taxonomy) FindTag(key string) (*models.Tag, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.findTag(key, false)
+}
+
+func (r taxonomy) FindTrashedTag(key string) (*models.Tag, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.findTag(key, true)
+}
+
+func (r taxonomy) FindTagByAlias(key string) (*models.Tag, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, alias := range r.s.aliases {
+		if alias.Name != key {
+			continue
+		}
+		if tag, ok := r.s.tags[alias.TagID]; ok && !tag.DeletedAt.Valid {
+			return &tag, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// slugTaken reports whether a category or tag other than excludeID, trashed
+// ones included, uses slug. Callers hold s.mu.
+func (r taxonomy) slugTaken(kind repository.Kind, slug string, excludeID uint) bool {
+	if kind == repository.KindCategory {
+		for _, c := range r.s.categories {
+			if c.Slug == slug && c.ID != excludeID {
+				return true
+			}
+		}
+		return false
+	}
+	for _, t := range r.s.tags {
+		if t.Slug == slug && t.ID != excludeID {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreTag is RestoreTag for callers holding s.mu.
+func (r taxonomy) restoreTag(id uint) (models.Tag, error) {
+	stored, ok := r.s.tags[id]
+	if !ok {
+		return stored, repository.ErrNotFound
+	}
+	if stored.Slug == "" || r.slugTaken(repository.KindTag, stored.Slug, stored.ID) {
+		stored.Slug = uniqueSlug(stored.Name, func(slug string) bool { return r.slugTaken(repository.KindTag, slug, stored.ID) })
+	}
+	stored.DeletedAt = gorm.DeletedAt{}
+	stored.UpdatedAt = time.Now()
+	r.s.tags[id] = stored
+	return stored, nil
+}
+
+func (r taxonomy) RestoreTag(tag *models.Tag) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, err := r.restoreTag(tag.ID)
+	if err != nil {
+		return err
+	}
+	*tag = stored
+	return nil
+}
+
+func (r taxonomy) CreateTag(tag *models.Tag) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if tag.Slug == "" {
+		tag.Slug = uniqueSlug(tag.Name, func(slug string) bool { return r.slugTaken(repository.KindTag, slug, 0) })
+	}
+	tag.ID = r.s.nextID()
+	tag.CreatedAt = time.Now()
+	tag.UpdatedAt = tag.CreatedAt
+	stored := *tag
+	stored.Aliases = nil
+	r.s.tags[tag.ID] = stored
+	return nil
+}
+
+func (r taxonomy) SaveTag(tag *models.Tag) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.tags[tag.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	tag.UpdatedAt = time.Now()
+	stored := *tag
+	stored.Aliases = nil
+	r.s.tags[tag.ID] = stored
+	return nil
+}
+
+func (r taxonomy) DeleteTag(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	tag, ok := r.s.tags[id]
+	if !ok || tag.DeletedAt.Valid {
+		return repository.ErrNotFound
+	}
+	for aliasID, alias := range r.s.aliases {
+		if alias.TagID == id {
+			delete(r.s.aliases, aliasID)
+		}
+	}
+	tag.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.s.tags[id] = tag
+	return nil
+}
+
+func (r taxonomy) TagUsage() (map[uint]int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	counts := map[uint]int64{}
+	for articleID, tagIDs := range r.s.articleTag {
+		if a, ok := r.s.articles[articleID]; !ok || a.DeletedAt.Valid {
+			continue
+		}
+		for _, id := range tagIDs {
+			counts[id]++
+		}
+	}
+	return counts, nil
+}
+
+func (r taxonomy) TaggedArticles(tagID uint, includeTrashed bool) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var ids []uint
+	for articleID, tagIDs := range r.s.articleTag {
+		a, ok := r.s.articles[articleID]
+		if ok && contains(tagIDs, tagID) && (includeTrashed || !a.DeletedAt.Valid) {
+			ids = append(ids, articleID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r taxonomy) UnlinkTag(tagID uint, articleIDs []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, articleID := range articleIDs {
+		r.s.articleTag[articleID] = without(r.s.articleTag[articleID], tagID)
+	}
+	return nil
+}
+
+func (r taxonomy) MoveTag(fromID, toID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var moved int64
+	for articleID, tagIDs := range r.s.articleTag {
+		if !contains(tagIDs, fromID) {
+			continue
+		}
+		moved++
+		tagIDs = without(tagIDs, fromID)
+		if !contains(tagIDs, toID) {
+			tagIDs = append(tagIDs, toID)
+		}
+		r.s.articleTag[articleID] = tagIDs
+	}
+	for id, alias := range r.s.aliases {
+		if alias.TagID == fromID {
+			alias.TagID = toID
+			r.s.aliases[id] = alias
+		}
+	}
+	return moved, nil
+}
+
+func (r taxonomy) CreateAlias(alias *models.TagAlias) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, a := range r.s.aliases {
+		if a.Name == alias.Name {
+			return fmt.Errorf("alias %q already exists", alias.Name)
+		}
+	}
+	alias.ID = r.s.nextID()
+	alias.CreatedAt = time.Now()
+	r.s.aliases[alias.ID] = *alias
+	return nil
+}
+
+func (r taxonomy) FindAlias(id, tagID uint) (*models.TagAlias, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	alias, ok := r.s.aliases[id]
+	if !ok || alias.TagID != tagID {
+		return nil, repository.ErrNotFound
+	}
+	return &alias, nil
+}
+
+func (r taxonomy) DeleteAlias(alias *models.TagAlias) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.aliases, alias.ID)
+	return nil
+}
+
+func (r taxonomy) ListCategories() ([]models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	list := []models.Category{}
+	for _, c := range r.s.categories {
+		if !c.DeletedAt.Valid {
+			list = append(list, c)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (r taxonomy) GetCategory(id uint) (*models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	category, ok := r.s.categories[id]
+	if !ok || category.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	return &category, nil
+}
+
+func (r taxonomy) GetCategoryBySlug(slug string) (*models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, category := range r.s.categories {
+		if category.Slug == slug && !category.DeletedAt.Valid {
+			return &category, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r taxonomy) FindCategory(name string) (*models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	key := strings.ToLower(name)
+	for _, category := range r.s.categories {
+		if strings.ToLower(category.Name) == key && !category.DeletedAt.Valid {
+			return &category, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r taxonomy) CreateCategory(category *models.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, c := range r.s.categories {
+		if c.Name == category.Name {
+			return fmt.Errorf("category %q already exists", category.Name)
+		}
+	}
+	category.ID = r.s.nextID()
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = category.CreatedAt
+	r.s.categories[category.ID] = *category
+	return nil
+}
+
+func (r taxonomy) SaveCategory(category *models.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.categories[category.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	category.UpdatedAt = time.Now()
+	r.s.categories[category.ID] = *category
+	return nil
+}
+
+func (r taxonomy) SetCategoryParent(id uint, parentID *uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	category, ok := r.s.categories[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	category.ParentID = parentID
+	r.s.categories[id] = category
+	return nil
+}
+
+func (r taxonomy) DeleteCategory(category *models.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, ok := r.s.categories[category.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return repository.ErrNotFound
+	}
+	for id, c := range r.s.categories {
+		if c.ParentID != nil && *c.ParentID == category.ID && !c.DeletedAt.Valid {
+			c.ParentID = stored.ParentID
+			r.s.categories[id] = c
+		}
+	}
+	stored.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.s.categories[category.ID] = stored
+	return nil
+}
+
+func (r taxonomy) CategoryParents() (map[uint]*uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	parents := make(map[uint]*uint, len(r.s.categories))
+	for id, category := range r.s.categories {
+		if !category.DeletedAt.Valid {
+			parents[id] = category.ParentID
+		}
+	}
+	return parents, nil
+}
+
+func (r taxonomy) CategoryUsage() (map[uint]int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	counts := map[uint]int64{}
+	for _, a := range r.s.articles {
+		if a.CategoryID != nil && !a.DeletedAt.Valid {
+			counts[*a.CategoryID]++
+		}
+	}
+	return counts, nil
+}
+
+func (r taxonomy) CategoryArticles(categoryID uint, includeTrashed bool) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var ids []uint
+	for _, a := range r.s.articles {
+		if a.CategoryID != nil && *a.CategoryID == categoryID && (includeTrashed || !a.DeletedAt.Valid) {
+			ids = append(ids, a.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r taxonomy) SetArticleCategory(articleIDs []uint, categoryID *uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, id := range articleIDs {
+		if a, ok := r.s.articles[id]; ok && !a.DeletedAt.Valid {
+			a.CategoryID = categoryID
+			r.s.articles[id] = a
+		}
+	}
+	return nil
+}
+
+func (r taxonomy) SlugTaken(kind repository.Kind, slug string, excludeID uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.slugTaken(kind, slug, excludeID), nil
+}
+
+func (r taxonomy) UniqueSlug(kind repository.Kind, name string, excludeID uint) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return uniqueSlug(name, func(slug string) bool { return r.slugTaken(kind, slug, excludeID) }), nil
+}
+
+func (r taxonomy) TrashedByName(kind repository.Kind, name string) (uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	key := strings.ToLower(name)
+	if kind == repository.KindCategory {
+		for _, c := range r.s.categories {
+			if c.DeletedAt.Valid && strings.ToLower(c.Name) == key {
+				return c.ID, nil
+			}
+		}
+		return 0, repository.ErrNotFound
+	}
+	if tag, err := r.findTag(key, true); err == nil {
+		return tag.ID, nil
+	}
+	return 0, repository.ErrNotFound
+}
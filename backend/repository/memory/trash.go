@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/your-username/blog-backend/repository"
+	"gorm.io/gorm"
+)
+
+type trash struct{ s *Store }
+
+// records returns every soft-deleted record of kind, oldest ID first.
+// Callers hold s.mu.
+func (r trash) records(kind repository.Kind) []repository.TrashedRecord {
+	var list []repository.TrashedRecord
+	switch kind {
+	case repository.KindArticle:
+		for _, a := range r.s.articles {
+			if a.DeletedAt.Valid {
+				list = append(list, repository.TrashedRecord{Kind: kind, ID: a.ID, Title: a.Title,
+					CategoryID: a.CategoryID, DeletedAt: a.DeletedAt.Time})
+			}
+		}
+	case repository.KindComment:
+		for _, c := range r.s.comments {
+			if c.DeletedAt.Valid {
+				articleID := c.ArticleID
+				list = append(list, repository.TrashedRecord{Kind: kind, ID: c.ID, Title: c.Content,
+					ArticleID: &articleID, DeletedAt: c.DeletedAt.Time})
+			}
+		}
+	case repository.KindTag:
+		for _, t := range r.s.tags {
+			if t.DeletedAt.Valid {
+				list = append(list, repository.TrashedRecord{Kind: kind, ID: t.ID, Title: t.Name, DeletedAt: t.DeletedAt.Time})
+			}
+		}
+	case repository.KindCategory:
+		for _, c := range r.s.categories {
+			if c.DeletedAt.Valid {
+				list = append(list, repository.TrashedRecord{Kind: kind, ID: c.ID, Title: c.Name,
+					ParentID: c.ParentID, DeletedAt: c.DeletedAt.Time})
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (r trash) List(kind repository.Kind) ([]repository.TrashedRecord, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.records(kind), nil
+}
+
+func (r trash) Find(kind repository.Kind, id uint) (*repository.TrashedRecord, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, record := range r.records(kind) {
+		if record.ID == id {
+			if kind == repository.KindArticle {
+				record.TagIDs = append([]uint(nil), r.s.articleTag[id]...)
+			}
+			return &record, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r trash) Expired(kind repository.Kind, before time.Time) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var ids []uint
+	for _, record := range r.records(kind) {
+		if record.DeletedAt.Before(before) {
+			ids = append(ids, record.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r trash) Restore(kind repository.Kind, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	switch kind {
+	case repository.KindArticle:
+		a, ok := r.s.articles[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		articles := articles{r.s}
+		if a.Slug == "" || articles.slugTaken(a.Slug, a.ID) {
+			a.Slug = uniqueSlug(a.Title, func(slug string) bool { return articles.slugTaken(slug, a.ID) })
+		}
+		a.DeletedAt = gorm.DeletedAt{}
+		a.UpdatedAt = time.Now()
+		r.s.articles[id] = a
+	case repository.KindComment:
+		c, ok := r.s.comments[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		c.DeletedAt = gorm.DeletedAt{}
+		c.UpdatedAt = time.Now()
+		r.s.comments[id] = c
+	case repository.KindTag:
+		_, err := taxonomy{r.s}.restoreTag(id)
+		return err
+	case repository.KindCategory:
+		c, ok := r.s.categories[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		taxonomy := taxonomy{r.s}
+		if c.Slug == "" || taxonomy.slugTaken(kind, c.Slug, c.ID) {
+			c.Slug = uniqueSlug(c.Name, func(slug string) bool { return taxonomy.slugTaken(kind, slug, c.ID) })
+		}
+		c.DeletedAt = gorm.DeletedAt{}
+		c.UpdatedAt = time.Now()
+		r.s.categories[id] = c
+	}
+	return nil
+}
+
+func (r trash) Purge(kind repository.Kind, ids []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, id := range ids {
+		switch kind {
+		case repository.KindArticle:
+			delete(r.s.articles, id)
+			delete(r.s.articleTag, id)
+			for commentID, c := range r.s.comments {
+				if c.ArticleID == id {
+					delete(r.s.comments, commentID)
+				}
+			}
+			events := r.s.events[:0]
+			for _, e := range r.s.events {
+				if e.ArticleID != id {
+					events = append(events, e)
+				}
+			}
+			r.s.events = events
+		case repository.KindComment:
+			delete(r.s.comments, id)
+		case repository.KindTag:
+			delete(r.s.tags, id)
+			for articleID, tagIDs := range r.s.articleTag {
+				r.s.articleTag[articleID] = without(tagIDs, id)
+			}
+			for aliasID, alias := range r.s.aliases {
+				if alias.TagID == id {
+					delete(r.s.aliases, aliasID)
+				}
+			}
+		case repository.KindCategory:
+			delete(r.s.categories, id)
+			for articleID, a := range r.s.articles {
+				if a.CategoryID != nil && *a.CategoryID == id {
+					a.CategoryID = nil
+					r.s.articles[articleID] = a
+				}
+			}
+			for categoryID, c := range r.s.categories {
+				if c.ParentID != nil && *c.ParentID == id {
+					c.ParentID = nil
+					r.s.categories[categoryID] = c
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+// Package repository defines the storage interfaces of the blog's aggregates
+// (articles, comments, users, taxonomy, series, media and the trash) together with
+// their GORM implementations. Package repository/memory has in-memory fakes of the same
+// interfaces for tests.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+)
+
+// ErrNotFound is returned when the requested record does not exist.
+var ErrNotFound = errors.New("record not found")
+
+// Kind names a table of records that can be trashed or carry a slug. The
+// values double as the record types of the trash API.
+type Kind string
+
+const (
+	KindArticle  Kind = "articles"
+	KindComment  Kind = "comments"
+	KindTag      Kind = "tags"
+	KindCategory Kind = "categories"
+)
+
+// Store gives access to the repositories of one database.
+type Store interface {
+	Articles() ArticleRepository
+	Comments() CommentRepository
+	Users() UserRepository
+	Taxonomy() TaxonomyRepository
+	Series() SeriesRepository
+	Media() MediaRepository
+	Trash() TrashRepository
+	// Transaction runs fn with a Store whose writes are committed together
+	// when fn returns nil and rolled back when it returns an error.
+	Transaction(fn func(tx Store) error) error
+}
+
+// ArticleQuery selects the articles returned by ArticleRepository.List.
+type ArticleQuery struct {
+	IncludeDrafts bool
+	Featured      bool     // Only featured articles, in carousel order
+	Search        string   // Case-insensitive substring of the title or content
+	CategoryIDs   []uint   // Any of these categories; nil for all
+	TagID         uint     // 0 for all
+	AuthorID      uint     // 0 for all
+	SeriesID      uint     // 0 for all; orders by position in the series
+	Newest        bool     // Newest first instead of pinned first
+	Columns       []string // Article columns to load; nil for all
+	WithAuthor    bool     // Only id, username and avatar_url
+	WithCategory  bool
+	WithTags      bool
+	Limit         int // 0 for no limit
+}
+
+// ArticleRepository stores articles and the series and media they refer to.
+type ArticleRepository interface {
+	// List returns the matching articles, pinned ones first unless q sets
+	// another order.
+	List(q ArticleQuery) ([]models.Article, error)
+	// Get returns an article with its author, category and tags.
+	Get(id uint, includeDrafts bool) (*models.Article, error)
+	// Find returns the article row without associations, drafts included.
+	Find(id uint) (*models.Article, error)
+	// FindAll returns the rows of the articles without associations, drafts
+	// and trashed ones included.
+	FindAll(ids []uint) ([]models.Article, error)
+	// LastChange returns when an article, drafts and trashed ones included,
+	// was last updated or moved to the trash.
+	LastChange() (time.Time, error)
+	Create(article *models.Article) error
+	// Update saves article and, unless tags is nil, replaces its tags.
+	Update(article *models.Article, tags []models.Tag) error
+	// Delete soft-deletes the articles and returns how many there were.
+	Delete(ids []uint) (int64, error)
+	// IncrementLikes and IncrementViews bump a counter without touching
+	// updated_at.
+	IncrementLikes(id uint) error
+	IncrementViews(id uint) error
+	// RecordEvent stores a view or like for the statistics.
+	RecordEvent(event *models.ArticleEvent) error
+	// CountDependents counts the comments, trashed ones included, and tag
+	// links of the articles.
+	CountDependents(ids []uint) (comments, tagLinks int64, err error)
+
+	// SlugTaken reports whether another article, trashed ones included, uses
+	// slug.
+	SlugTaken(slug string, excludeID uint) (bool, error)
+	// UniqueSlug derives an unused slug from title.
+	UniqueSlug(title string, excludeID uint) (string, error)
+
+	// NextSeriesOrder returns the position after the last article of a
+	// series.
+	NextSeriesOrder(seriesID uint) (int, error)
+	// SeriesParts returns the published articles of a series in reading
+	// order.
+	SeriesParts(seriesID uint) ([]models.SeriesArticleRef, error)
+
+	// Adjacent returns the published articles written right before and
+	// after article, nil at either end. Only their id, title and created_at
+	// are loaded.
+	Adjacent(article *models.Article) (prev, next *models.Article, err error)
+	// RelatedCandidates returns up to limit published articles other than
+	// article that share a tag or its category with it, most shared tags
+	// first, then newest.
+	RelatedCandidates(article *models.Article, limit int) ([]RelatedCandidate, error)
+	// Neighbours returns the IDs of the published articles sharing a tag or
+	// a category with any of the articles ids.
+	Neighbours(ids []uint) ([]uint, error)
+
+	FindMedia(id uint) (*models.Media, error)
+}
+
+// RelatedCandidate is an article returned by
+// ArticleRepository.RelatedCandidates. Only its id, title, excerpt,
+// cover_url, category_id and created_at are loaded.
+type RelatedCandidate struct {
+	Article    models.Article
+	SharedTags int
+}
+
+// CommentRepository stores comments.
+type CommentRepository interface {
+	// ListByArticle returns the comments of an article with their users,
+	// oldest first.
+	ListByArticle(articleID uint) ([]models.Comment, error)
+	// Recent returns the newest comments of an article with their users.
+	Recent(articleID uint, limit int) ([]models.Comment, error)
+	// LastChange returns when a comment of an article, trashed ones
+	// included, was last updated or moved to the trash.
+	LastChange(articleID uint) (time.Time, error)
+	// Get returns a comment with its user.
+	Get(id uint) (*models.Comment, error)
+	Create(comment *models.Comment) error
+	Delete(comment *models.Comment) error
+}
+
+// UserRepository stores users.
+type UserRepository interface {
+	Count() (int64, error)
+	Find(id uint) (*models.User, error)
+	FindByUsername(username string) (*models.User, error)
+	// First returns the user with the lowest ID.
+	First() (*models.User, error)
+	Create(user *models.User) error
+	Save(user *models.User) error
+}
+
+// TaxonomyRepository stores categories, tags and tag aliases. Unless noted,
+// methods only see records that are not in the trash.
+type TaxonomyRepository interface {
+	// ListTags returns all tags with their aliases in creation order.
+	ListTags() ([]models.Tag, error)
+	// GetTag returns a tag with its aliases.
+	GetTag(id uint) (*models.Tag, error)
+	GetTagBySlug(slug string) (*models.Tag, error)
+	// FindTag looks a tag up by its lowercased name.
+	FindTag(key string) (*models.Tag, error)
+	// FindTagByAlias returns the tag a lowercased alias resolves to.
+	FindTagByAlias(key string) (*models.Tag, error)
+	// FindTrashedTag looks a soft-deleted tag up by its lowercased name.
+	FindTrashedTag(key string) (*models.Tag, error)
+	// CreateTag stores a new tag, deriving an unused slug from its name when
+	// it has none.
+	CreateTag(tag *models.Tag) error
+	SaveTag(tag *models.Tag) error
+	// RestoreTag takes a soft-deleted tag out of the trash, deriving a new
+	// slug if a live tag took its slug in the meantime.
+	RestoreTag(tag *models.Tag) error
+	// DeleteTag soft-deletes a tag and removes its aliases.
+	DeleteTag(id uint) error
+	// TagUsage returns the number of live articles per tag ID.
+	TagUsage() (map[uint]int64, error)
+	// TaggedArticles returns the IDs of the articles carrying a tag. Articles
+	// in the trash are included when includeTrashed is set.
+	TaggedArticles(tagID uint, includeTrashed bool) ([]uint, error)
+	// UnlinkTag removes a tag from the articles.
+	UnlinkTag(tagID uint, articleIDs []uint) error
+	// MoveTag relinks every article and alias of fromID to toID and returns
+	// how many article links fromID had. Articles that already carry toID
+	// just lose the fromID link.
+	MoveTag(fromID, toID uint) (int64, error)
+
+	CreateAlias(alias *models.TagAlias) error
+	// FindAlias returns an alias of the tag tagID.
+	FindAlias(id, tagID uint) (*models.TagAlias, error)
+	DeleteAlias(alias *models.TagAlias) error
+
+	// ListCategories returns all categories ordered by name.
+	ListCategories() ([]models.Category, error)
+	GetCategory(id uint) (*models.Category, error)
+	GetCategoryBySlug(slug string) (*models.Category, error)
+	// FindCategory returns the live category called name, compared
+	// case-insensitively.
+	FindCategory(name string) (*models.Category, error)
+	CreateCategory(category *models.Category) error
+	SaveCategory(category *models.Category) error
+	// SetCategoryParent moves a category, trashed or not, below parentID
+	// (nil for the top level).
+	SetCategoryParent(id uint, parentID *uint) error
+	// DeleteCategory soft-deletes a category. Its children move up to its
+	// parent.
+	DeleteCategory(category *models.Category) error
+	// CategoryParents returns the parent of every category, nil for roots.
+	CategoryParents() (map[uint]*uint, error)
+	// CategoryUsage returns the number of live articles per category ID.
+	CategoryUsage() (map[uint]int64, error)
+	// CategoryArticles returns the IDs of the articles in a category.
+	// Articles in the trash are included when includeTrashed is set.
+	CategoryArticles(categoryID uint, includeTrashed bool) ([]uint, error)
+	// SetArticleCategory moves the articles to categoryID (nil for none).
+	SetArticleCategory(articleIDs []uint, categoryID *uint) error
+
+	// SlugTaken reports whether a category or tag other than excludeID uses
+	// slug. Slugs are unique across the trash.
+	SlugTaken(kind Kind, slug string, excludeID uint) (bool, error)
+	// UniqueSlug derives an unused category or tag slug from name.
+	UniqueSlug(kind Kind, name string, excludeID uint) (string, error)
+	// TrashedByName returns the ID of the soft-deleted category or tag
+	// called name, compared case-insensitively.
+	TrashedByName(kind Kind, name string) (uint, error)
+}
+
+// SeriesRepository stores series.
+type SeriesRepository interface {
+	// List returns all series, newest first.
+	List() ([]models.Series, error)
+	Get(id uint) (*models.Series, error)
+	GetBySlug(slug string) (*models.Series, error)
+	Create(series *models.Series) error
+	Save(series *models.Series) error
+	// Delete removes a series. Its articles, trashed ones included, leave
+	// the series.
+	Delete(series *models.Series) error
+	// ArticleCounts returns the number of live articles per series ID.
+	ArticleCounts() (map[uint]int64, error)
+	// SetArticles makes articleIDs the articles of a series, in that order.
+	// Other articles leave the series.
+	SetArticles(seriesID uint, articleIDs []uint) error
+
+	SlugTaken(slug string, excludeID uint) (bool, error)
+	// UniqueSlug derives an unused slug from title.
+	UniqueSlug(title string, excludeID uint) (string, error)
+}
+
+// MediaRepository stores uploaded files and the sessions of chunked uploads
+// that have not been assembled yet.
+type MediaRepository interface {
+	Create(media *models.Media) error
+	// FindByChecksum returns an upload with the given hex SHA-256.
+	FindByChecksum(checksum string) (*models.Media, error)
+
+	CreateSession(session *models.UploadSession) error
+	// FindSession returns a session of the user userID.
+	FindSession(id string, userID uint) (*models.UploadSession, error)
+	// TouchSession moves the expiry of a session.
+	TouchSession(id string, expiresAt time.Time) error
+	DeleteSession(id string) error
+	// ExpiredSessions returns the sessions that expired before the cutoff.
+	ExpiredSessions(before time.Time) ([]models.UploadSession, error)
+}
+
+// TrashedRecord is a soft-deleted article, comment, tag or category.
+type TrashedRecord struct {
+	Kind       Kind
+	ID         uint
+	Title      string // Title of articles, content of comments, name otherwise
+	ArticleID  *uint  // Comments: the article they belong to
+	CategoryID *uint  // Articles: their category
+	ParentID   *uint  // Categories: their parent
+	TagIDs     []uint // Articles: their tags; only filled in by Find
+	DeletedAt  time.Time
+}
+
+// TrashRepository reads, restores and purges soft-deleted records.
+type TrashRepository interface {
+	List(kind Kind) ([]TrashedRecord, error)
+	Find(kind Kind, id uint) (*TrashedRecord, error)
+	// Expired returns the IDs of the records deleted before the cutoff.
+	Expired(kind Kind, before time.Time) ([]uint, error)
+	// Restore takes a record out of the trash. Articles, categories and tags
+	// get a new slug if a live record took theirs in the meantime.
+	Restore(kind Kind, id uint) error
+	// Purge permanently deletes records, trashed or not, with what depends
+	// on them: articles with their tag links, comments and events, tags with
+	// their links and aliases. Articles and categories referring to a purged
+	// category lose the reference.
+	Purge(kind Kind, ids []uint) error
+}
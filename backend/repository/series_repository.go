@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+type gormSeries struct {
+	db *gorm.DB
+}
+
+func (r gormSeries) List() ([]models.Series, error) {
+	var series []models.Series
+	err := r.db.Order("created_at DESC").Order("id DESC").Find(&series).Error
+	return series, err
+}
+
+func (r gormSeries) Get(id uint) (*models.Series, error) {
+	var series models.Series
+	if err := r.db.First(&series, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &series, nil
+}
+
+func (r gormSeries) GetBySlug(slug string) (*models.Series, error) {
+	var series models.Series
+	if err := r.db.Where("slug = ?", slug).First(&series).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &series, nil
+}
+
+func (r gormSeries) Create(series *models.Series) error {
+	return r.db.Create(series).Error
+}
+
+func (r gormSeries) Save(series *models.Series) error {
+	return r.db.Save(series).Error
+}
+
+func (r gormSeries) Delete(series *models.Series) error {
+	if err := r.db.Unscoped().Model(&models.Article{}).Where("series_id = ?", series.ID).
+		Updates(map[string]interface{}{"series_id": nil, "series_order": 0}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(series).Error
+}
+
+func (r gormSeries) ArticleCounts() (map[uint]int64, error) {
+	var rows []struct {
+		SeriesID uint
+		Count    int64
+	}
+	err := r.db.Model(&models.Article{}).Select("series_id, COUNT(*) AS count").
+		Where("series_id IS NOT NULL").Group("series_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.SeriesID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r gormSeries) SetArticles(seriesID uint, articleIDs []uint) error {
+	if err := r.db.Model(&models.Article{}).Where("series_id = ?", seriesID).
+		Updates(map[string]interface{}{"series_id": nil, "series_order": 0}).Error; err != nil {
+		return err
+	}
+	for i, id := range articleIDs {
+		if err := r.db.Model(&models.Article{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"series_id": seriesID, "series_order": i + 1}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r gormSeries) SlugTaken(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Series{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r gormSeries) UniqueSlug(title string, excludeID uint) (string, error) {
+	return database.UniqueSlug(r.db, &models.Series{}, title, excludeID)
+}
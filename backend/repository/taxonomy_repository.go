@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+// kindModel returns the model of the table kind names.
+func kindModel(kind Kind) interface{} {
+	switch kind {
+	case KindArticle:
+		return &models.Article{}
+	case KindComment:
+		return &models.Comment{}
+	case KindTag:
+		return &models.Tag{}
+	case KindCategory:
+		return &models.Category{}
+	}
+	panic(fmt.Sprintf("repository: unknown kind %q", kind))
+}
+
+type gormTaxonomy struct {
+	db *gorm.DB
+}
+
+func (r gormTaxonomy) ListTags() ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Preload("Aliases").Order("id ASC").Find(&tags).Error
+	return tags, err
+}
+
+func (r gormTaxonomy) GetTag(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Preload("Aliases").First(&tag, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &tag, nil
+}
+
+func (r gormTaxonomy) GetTagBySlug(slug string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Preload("Aliases").Where("slug = ?", slug).First(&tag).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &tag, nil
+}
+
+func (r gormTaxonomy) FindTag(key string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Where("LOWER(name) = ?", key).First(&tag).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &tag, nil
+}
+
+func (r gormTaxonomy) FindTagByAlias(key string) (*models.Tag, error) {
+	var alias models.TagAlias
+	if err := r.db.Where("name = ?", key).First(&alias).Error; err != nil {
+		return nil, translate(err)
+	}
+	var tag models.Tag
+	if err := r.db.First(&tag, alias.TagID).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &tag, nil
+}
+
+func (r gormTaxonomy) FindTrashedTag(key string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Unscoped().Where("LOWER(name) = ? AND deleted_at IS NOT NULL", key).First(&tag).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &tag, nil
+}
+
+func (r gormTaxonomy) RestoreTag(tag *models.Tag) error {
+	slug, err := database.Restore(r.db, &models.Tag{}, tag.ID, tag.Name, tag.Slug)
+	if err != nil {
+		return err
+	}
+	tag.Slug = slug
+	tag.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+func (r gormTaxonomy) CreateTag(tag *models.Tag) error {
+	if tag.Slug == "" {
+		slug, err := database.UniqueSlug(r.db, &models.Tag{}, tag.Name, 0)
+		if err != nil {
+			return err
+		}
+		tag.Slug = slug
+	}
+	return r.db.Create(tag).Error
+}
+
+func (r gormTaxonomy) SaveTag(tag *models.Tag) error {
+	return r.db.Omit("Aliases").Save(tag).Error
+}
+
+func (r gormTaxonomy) DeleteTag(id uint) error {
+	if err := r.db.Where("tag_id = ?", id).Delete(&models.TagAlias{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&models.Tag{}, id).Error
+}
+
+func (r gormTaxonomy) TagUsage() (map[uint]int64, error) {
+	var rows []struct {
+		TagID uint
+		Count int64
+	}
+	err := r.db.Model(&models.ArticleTag{}).
+		Select("article_tags.tag_id, COUNT(*) AS count").
+		Joins("JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL").
+		Group("article_tags.tag_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r gormTaxonomy) TaggedArticles(tagID uint, includeTrashed bool) ([]uint, error) {
+	links := r.db.Model(&models.ArticleTag{}).Where("tag_id = ?", tagID)
+	if !includeTrashed {
+		links = links.Where("article_id IN (?)", r.db.Model(&models.Article{}).Select("id"))
+	}
+	var ids []uint
+	err := links.Pluck("article_id", &ids).Error
+	return ids, err
+}
+
+func (r gormTaxonomy) UnlinkTag(tagID uint, articleIDs []uint) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("tag_id = ? AND article_id IN ?", tagID, articleIDs).Delete(&models.ArticleTag{}).Error
+}
+
+func (r gormTaxonomy) MoveTag(fromID, toID uint) (int64, error) {
+	var moved int64
+	if err := r.db.Model(&models.ArticleTag{}).Where("tag_id = ?", fromID).Count(&moved).Error; err != nil {
+		return 0, err
+	}
+
+	var alreadyLinked []uint
+	if err := r.db.Model(&models.ArticleTag{}).Where("tag_id = ?", toID).Pluck("article_id", &alreadyLinked).Error; err != nil {
+		return 0, err
+	}
+	if len(alreadyLinked) > 0 {
+		if err := r.db.Where("tag_id = ? AND article_id IN ?", fromID, alreadyLinked).Delete(&models.ArticleTag{}).Error; err != nil {
+			return 0, err
+		}
+	}
+	if err := r.db.Model(&models.ArticleTag{}).Where("tag_id = ?", fromID).Update("tag_id", toID).Error; err != nil {
+		return 0, err
+	}
+	return moved, r.db.Model(&models.TagAlias{}).Where("tag_id = ?", fromID).Update("tag_id", toID).Error
+}
+
+func (r gormTaxonomy) CreateAlias(alias *models.TagAlias) error {
+	return r.db.Create(alias).Error
+}
+
+func (r gormTaxonomy) FindAlias(id, tagID uint) (*models.TagAlias, error) {
+	var alias models.TagAlias
+	if err := r.db.Where("id = ? AND tag_id = ?", id, tagID).First(&alias).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &alias, nil
+}
+
+func (r gormTaxonomy) DeleteAlias(alias *models.TagAlias) error {
+	return r.db.Delete(alias).Error
+}
+
+func (r gormTaxonomy) ListCategories() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r gormTaxonomy) GetCategory(id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.First(&category, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &category, nil
+}
+
+func (r gormTaxonomy) GetCategoryBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &category, nil
+}
+
+func (r gormTaxonomy) FindCategory(name string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("LOWER(name) = ?", strings.ToLower(name)).First(&category).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &category, nil
+}
+
+func (r gormTaxonomy) CreateCategory(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r gormTaxonomy) SaveCategory(category *models.Category) error {
+	return r.db.Save(category).Error
+}
+
+func (r gormTaxonomy) SetCategoryParent(id uint, parentID *uint) error {
+	return r.db.Unscoped().Model(&models.Category{}).Where("id = ?", id).Update("parent_id", parentID).Error
+}
+
+func (r gormTaxonomy) DeleteCategory(category *models.Category) error {
+	if err := r.db.Model(&models.Category{}).Where("parent_id = ?", category.ID).Update("parent_id", category.ParentID).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(category).Error
+}
+
+func (r gormTaxonomy) CategoryParents() (map[uint]*uint, error) {
+	var categories []models.Category
+	if err := r.db.Select("id", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	parents := make(map[uint]*uint, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+	return parents, nil
+}
+
+func (r gormTaxonomy) CategoryUsage() (map[uint]int64, error) {
+	var rows []struct {
+		CategoryID uint
+		Count      int64
+	}
+	err := r.db.Model(&models.Article{}).Select("category_id, COUNT(*) AS count").
+		Where("category_id IS NOT NULL").Group("category_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r gormTaxonomy) CategoryArticles(categoryID uint, includeTrashed bool) ([]uint, error) {
+	query := r.db.Model(&models.Article{})
+	if includeTrashed {
+		query = query.Unscoped()
+	}
+	var ids []uint
+	err := query.Where("category_id = ?", categoryID).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r gormTaxonomy) SetArticleCategory(articleIDs []uint, categoryID *uint) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Article{}).Where("id IN ?", articleIDs).Update("category_id", categoryID).Error
+}
+
+func (r gormTaxonomy) SlugTaken(kind Kind, slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(kindModel(kind)).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r gormTaxonomy) UniqueSlug(kind Kind, name string, excludeID uint) (string, error) {
+	return database.UniqueSlug(r.db, kindModel(kind), name, excludeID)
+}
+
+func (r gormTaxonomy) TrashedByName(kind Kind, name string) (uint, error) {
+	var ids []uint
+	err := r.db.Unscoped().Model(kindModel(kind)).
+		Where("deleted_at IS NOT NULL AND LOWER(name) = ?", strings.ToLower(name)).
+		Limit(1).Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, ErrNotFound
+	}
+	return ids[0], nil
+}
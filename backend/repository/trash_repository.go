@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/your-username/blog-backend/database"
+	"github.com/your-username/blog-backend/models"
+	"gorm.io/gorm"
+)
+
+type gormTrash struct {
+	db *gorm.DB
+}
+
+// trashed scopes a query to the soft-deleted rows of kind.
+func (r gormTrash) trashed(kind Kind) *gorm.DB {
+	return r.db.Unscoped().Model(kindModel(kind)).Where("deleted_at IS NOT NULL")
+}
+
+// trashColumns selects the columns of a TrashedRecord of kind.
+func trashColumns(kind Kind) []string {
+	switch kind {
+	case KindArticle:
+		return []string{"id", "title", "category_id", "deleted_at"}
+	case KindComment:
+		return []string{"id", "content AS title", "article_id", "deleted_at"}
+	case KindCategory:
+		return []string{"id", "name AS title", "parent_id", "deleted_at"}
+	}
+	return []string{"id", "name AS title", "deleted_at"}
+}
+
+// scan reads the trashed records of kind matching query.
+func (r gormTrash) scan(kind Kind, query *gorm.DB) ([]TrashedRecord, error) {
+	var rows []struct {
+		ID         uint
+		Title      string
+		ArticleID  *uint
+		CategoryID *uint
+		ParentID   *uint
+		DeletedAt  time.Time
+	}
+	if err := query.Select(trashColumns(kind)).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]TrashedRecord, len(rows))
+	for i, row := range rows {
+		records[i] = TrashedRecord{
+			Kind:       kind,
+			ID:         row.ID,
+			Title:      row.Title,
+			ArticleID:  row.ArticleID,
+			CategoryID: row.CategoryID,
+			ParentID:   row.ParentID,
+			DeletedAt:  row.DeletedAt,
+		}
+	}
+	return records, nil
+}
+
+func (r gormTrash) List(kind Kind) ([]TrashedRecord, error) {
+	return r.scan(kind, r.trashed(kind))
+}
+
+func (r gormTrash) Find(kind Kind, id uint) (*TrashedRecord, error) {
+	records, err := r.scan(kind, r.trashed(kind).Where("id = ?", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrNotFound
+	}
+
+	record := &records[0]
+	if kind == KindArticle {
+		err := r.db.Model(&models.ArticleTag{}).Where("article_id = ?", id).Pluck("tag_id", &record.TagIDs).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+func (r gormTrash) Expired(kind Kind, before time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.trashed(kind).Where("deleted_at < ?", before).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r gormTrash) Restore(kind Kind, id uint) error {
+	switch kind {
+	case KindComment:
+		return r.db.Unscoped().Model(&models.Comment{}).Where("id = ?", id).
+			UpdateColumns(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()}).Error
+	case KindArticle:
+		var article models.Article
+		if err := r.db.Unscoped().First(&article, id).Error; err != nil {
+			return translate(err)
+		}
+		_, err := database.Restore(r.db, &models.Article{}, article.ID, article.Title, article.Slug)
+		return err
+	case KindCategory:
+		var category models.Category
+		if err := r.db.Unscoped().First(&category, id).Error; err != nil {
+			return translate(err)
+		}
+		_, err := database.Restore(r.db, &models.Category{}, category.ID, category.Name, category.Slug)
+		return err
+	}
+
+	var tag models.Tag
+	if err := r.db.Unscoped().First(&tag, id).Error; err != nil {
+		return translate(err)
+	}
+	return gormTaxonomy{db: r.db}.RestoreTag(&tag)
+}
+
+func (r gormTrash) Purge(kind Kind, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	switch kind {
+	case KindArticle:
+		return r.purgeArticles(ids)
+	case KindComment:
+		return r.db.Unscoped().Delete(&models.Comment{}, ids).Error
+	case KindTag:
+		if err := r.db.Where("tag_id IN ?", ids).Delete(&models.ArticleTag{}).Error; err != nil {
+			return err
+		}
+		if err := r.db.Where("tag_id IN ?", ids).Delete(&models.TagAlias{}).Error; err != nil {
+			return err
+		}
+		return r.db.Unscoped().Delete(&models.Tag{}, ids).Error
+	}
+
+	if err := r.db.Unscoped().Model(&models.Article{}).Where("category_id IN ?", ids).UpdateColumn("category_id", nil).Error; err != nil {
+		return err
+	}
+	if err := r.db.Unscoped().Model(&models.Category{}).Where("parent_id IN ?", ids).UpdateColumn("parent_id", nil).Error; err != nil {
+		return err
+	}
+	return r.db.Unscoped().Delete(&models.Category{}, ids).Error
+}
+
+func (r gormTrash) purgeArticles(ids []uint) error {
+	if err := r.db.Where("article_id IN ?", ids).Delete(&models.ArticleTag{}).Error; err != nil {
+		return fmt.Errorf("failed to delete article tags: %w", err)
+	}
+	if err := r.db.Unscoped().Where("article_id IN ?", ids).Delete(&models.Comment{}).Error; err != nil {
+		return fmt.Errorf("failed to delete article comments: %w", err)
+	}
+	if err := r.db.Where("article_id IN ?", ids).Delete(&models.ArticleEvent{}).Error; err != nil {
+		return fmt.Errorf("failed to delete article events: %w", err)
+	}
+	if err := r.db.Unscoped().Delete(&models.Article{}, ids).Error; err != nil {
+		return fmt.Errorf("failed to delete articles: %w", err)
+	}
+	return nil
+}
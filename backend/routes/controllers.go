@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"github.com/your-username/blog-backend/controllers"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/services"
+	"gorm.io/gorm"
+)
+
+// Controllers holds the handlers with the repositories and services they
+// use. Only the handlers of infrastructure that works on the database as a
+// whole (statistics, analytics, backups, imports and exports, the sitemap
+// and prerendered pages) receive db itself.
+type Controllers struct {
+	Articles  *controllers.ArticleController
+	Related   *controllers.RelatedController
+	Comments  *controllers.CommentController
+	Users     *controllers.UserController
+	Taxonomy  *controllers.TaxonomyController
+	Series    *controllers.SeriesController
+	Feeds     *controllers.FeedController
+	Trash     *controllers.TrashController
+	Uploads   *controllers.UploadController
+	Markdown  *controllers.MarkdownController
+	Backups   *controllers.BackupController
+	Stats     *controllers.StatsController
+	Analytics *controllers.AnalyticsController
+	Sitemap   *controllers.SitemapController
+	Frontend  *controllers.FrontendController
+}
+
+// NewControllers wires the repositories and services of db into the
+// controllers.
+func NewControllers(db *gorm.DB) Controllers {
+	store := repository.NewStore(db)
+	articles := services.NewArticleService(store)
+	related := services.NewRelatedService(store)
+	comments := services.NewCommentService(store)
+	users := services.NewUserService(store)
+	taxonomy := services.NewTaxonomyService(store)
+	return Controllers{
+		Articles:  controllers.NewArticleController(articles, related),
+		Related:   controllers.NewRelatedController(related),
+		Comments:  controllers.NewCommentController(comments),
+		Users:     controllers.NewUserController(users),
+		Taxonomy:  controllers.NewTaxonomyController(taxonomy, related),
+		Series:    controllers.NewSeriesController(services.NewSeriesService(store), articles),
+		Feeds:     controllers.NewFeedController(articles, taxonomy, comments, users),
+		Trash:     controllers.NewTrashController(services.NewTrashService(store), related),
+		Uploads:   controllers.NewUploadController(store.Media()),
+		Markdown:  controllers.NewMarkdownController(db, related),
+		Backups:   controllers.NewBackupController(db, related),
+		Stats:     controllers.NewStatsController(db),
+		Analytics: controllers.NewAnalyticsController(db),
+		Sitemap:   controllers.NewSitemapController(db),
+		Frontend:  controllers.NewFrontendController(db),
+	}
+}
@@ -1,14 +1,30 @@
 package routes
 
 import (
+	"log"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	"github.com/your-username/blog-backend/config"
 	"github.com/your-username/blog-backend/controllers"
 	"github.com/your-username/blog-backend/middlewares"
 )
 
-func SetupRouter() *gin.Engine {
+func SetupRouter(ctl Controllers) *gin.Engine {
 	r := gin.Default()
 
+	// Client IPs (analytics visitors, logs) come from X-Forwarded-For only
+	// when the request passed through one of TRUSTED_PROXIES
+	var proxies []string
+	for _, proxy := range strings.Split(config.AppConfig.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// CORS Middleware
 	r.Use(middlewares.CORSMiddleware())
 
@@ -19,72 +35,156 @@ func SetupRouter() *gin.Engine {
 		})
 	})
 
+	// Search engines
+	r.GET("/robots.txt", controllers.GetRobotsTxt)
+	r.GET("/sitemap.xml", ctl.Sitemap.GetSitemap)
+	r.GET("/sitemaps/:page", ctl.Sitemap.GetSitemapPage)
+
+	// Feeds (RSS 2.0 feed.xml, Atom atom.xml, JSON Feed feed.json)
+	for _, format := range []string{"feed.xml", "atom.xml", "feed.json"} {
+		r.GET("/"+format, ctl.Feeds.GetFeed)
+	}
+	feeds := r.Group("/feeds")
+	{
+		feeds.GET("/categories/:id/:format", ctl.Feeds.GetCategoryFeed)
+		feeds.GET("/tags/:id/:format", ctl.Feeds.GetTagFeed)
+		feeds.GET("/authors/:id/:format", ctl.Feeds.GetAuthorFeed)
+		feeds.GET("/articles/:id/comments/:format", ctl.Feeds.GetCommentFeed)
+	}
+
 	api := r.Group("/api")
 	{
 		v1 := api.Group("/v1")
 		{
 			auth := v1.Group("/auth")
 			{
-				auth.POST("/register", controllers.Register)
-				auth.POST("/login", controllers.Login)
+				auth.POST("/register", ctl.Users.Register)
+				auth.POST("/login", ctl.Users.Login)
 			}
 
 			user := v1.Group("/user")
 			user.Use(middlewares.JwtAuthMiddleware())
 			{
-				user.GET("/profile", controllers.GetProfile)
-				user.PUT("/profile", controllers.UpdateProfile)
+				user.GET("/profile", ctl.Users.GetProfile)
+				user.PUT("/profile", ctl.Users.UpdateProfile)
 			}
 
 			// Public User Routes
-			v1.GET("/author-profile", controllers.GetAuthorProfile)
-			v1.GET("/registration-status", controllers.CheckRegistrationStatus)
+			v1.GET("/author-profile", ctl.Users.GetAuthorProfile)
+			v1.GET("/registration-status", ctl.Users.CheckRegistrationStatus)
 
-			// Public Article Routes
-			v1.GET("/articles", controllers.GetArticles)
-			v1.GET("/articles/:id", controllers.GetArticle)
-			v1.POST("/articles/:id/view", controllers.ViewArticle)
+			// Public Article Routes (signed-in users also see drafts)
+			v1.GET("/articles", middlewares.OptionalJwtAuthMiddleware(), ctl.Articles.GetArticles)
+			v1.GET("/articles/featured", ctl.Articles.GetFeaturedArticles)
+			v1.GET("/articles/:id", middlewares.OptionalJwtAuthMiddleware(), ctl.Articles.GetArticle)
+			v1.GET("/articles/:id/related", ctl.Related.GetArticleContext)
+			v1.POST("/articles/:id/view", ctl.Articles.ViewArticle)
 
 			// Protected Article Routes
 			articles := v1.Group("/articles")
 			articles.Use(middlewares.JwtAuthMiddleware())
 			{
-				articles.POST("/", controllers.CreateArticle)
-				articles.PUT("/:id", controllers.UpdateArticle)
-				articles.DELETE("/:id", controllers.DeleteArticle)
-				articles.POST("/batch-delete", controllers.BatchDeleteArticles)
-				articles.POST("/:id/comments", controllers.CreateComment)
-				articles.POST("/:id/like", controllers.LikeArticle)
+				articles.POST("/", ctl.Articles.CreateArticle)
+				articles.PUT("/:id", ctl.Articles.UpdateArticle)
+				articles.DELETE("/:id", ctl.Articles.DeleteArticle)
+				articles.POST("/batch-delete", ctl.Articles.BatchDeleteArticles)
+				articles.POST("/:id/comments", ctl.Comments.CreateComment)
+				articles.POST("/:id/like", ctl.Articles.LikeArticle)
 			}
 
 			// Public Comment Routes
-			v1.GET("/articles/:id/comments", controllers.GetComments)
+			v1.GET("/articles/:id/comments", ctl.Comments.GetComments)
 
 			// Protected Comment Routes
 			comments := v1.Group("/comments")
 			comments.Use(middlewares.JwtAuthMiddleware())
 			{
-				comments.DELETE("/:id", controllers.DeleteComment)
+				comments.DELETE("/:id", ctl.Comments.DeleteComment)
 			}
 
 			// Upload Route
-			v1.POST("/upload", middlewares.JwtAuthMiddleware(), controllers.UploadFile)
+			v1.POST("/upload", middlewares.JwtAuthMiddleware(), ctl.Uploads.UploadFile)
+
+			// Resumable Chunked Upload Routes
+			uploads := v1.Group("/uploads")
+			uploads.Use(middlewares.JwtAuthMiddleware())
+			{
+				uploads.POST("/", ctl.Uploads.InitUpload)
+				uploads.GET("/:id", ctl.Uploads.GetUploadStatus)
+				uploads.PUT("/:id/chunks/:index", ctl.Uploads.UploadChunk)
+				uploads.POST("/:id/complete", ctl.Uploads.CompleteUpload)
+				uploads.DELETE("/:id", ctl.Uploads.AbortUpload)
+			}
 
 			// Category Routes
-			v1.GET("/categories", controllers.GetCategories)
-			v1.POST("/categories", middlewares.JwtAuthMiddleware(), controllers.CreateCategory)
-			v1.PUT("/categories/:id", middlewares.JwtAuthMiddleware(), controllers.UpdateCategory)
-			v1.DELETE("/categories/:id", middlewares.JwtAuthMiddleware(), controllers.DeleteCategory)
+			v1.GET("/categories", ctl.Taxonomy.GetCategories)
+			v1.GET("/categories/:id", ctl.Taxonomy.GetCategory)
+			v1.POST("/categories", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.CreateCategory)
+			v1.PUT("/categories/:id", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.UpdateCategory)
+			v1.POST("/categories/:id/move", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.MoveCategory)
+			v1.DELETE("/categories/:id", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.DeleteCategory)
+
+			// Series Routes
+			v1.GET("/series", ctl.Series.GetSeriesList)
+			v1.GET("/series/:id", middlewares.OptionalJwtAuthMiddleware(), ctl.Series.GetSeries)
+			series := v1.Group("/series")
+			series.Use(middlewares.JwtAuthMiddleware())
+			{
+				series.POST("", ctl.Series.CreateSeries)
+				series.PUT("/:id", ctl.Series.UpdateSeries)
+				series.DELETE("/:id", ctl.Series.DeleteSeries)
+				series.PUT("/:id/articles", ctl.Series.ReorderSeriesArticles)
+			}
+
+			// Markdown Import and Export
+			v1.POST("/import/markdown", middlewares.JwtAuthMiddleware(), ctl.Markdown.ImportMarkdown)
+			v1.GET("/export/markdown", middlewares.JwtAuthMiddleware(), ctl.Markdown.ExportMarkdown)
+
+			// Backup Routes
+			backups := v1.Group("/admin/backups")
+			backups.Use(middlewares.JwtAuthMiddleware())
+			{
+				backups.GET("", ctl.Backups.GetBackups)
+				backups.POST("", ctl.Backups.CreateBackup)
+				backups.POST("/restore", ctl.Backups.RestoreBackup)
+				backups.GET("/:name", ctl.Backups.DownloadBackup)
+				backups.DELETE("/:name", ctl.Backups.DeleteBackup)
+			}
+
+			// Dashboard Statistics and Page Analytics
+			v1.GET("/admin/stats", middlewares.JwtAuthMiddleware(), ctl.Stats.GetStats)
+			v1.GET("/admin/analytics", middlewares.JwtAuthMiddleware(), ctl.Analytics.GetAnalytics)
+			v1.POST("/analytics/collect", ctl.Analytics.CollectPageView)
+
+			// Trash Routes (soft-deleted articles, comments, tags and categories)
+			trash := v1.Group("/admin/trash")
+			trash.Use(middlewares.JwtAuthMiddleware())
+			{
+				trash.GET("", ctl.Trash.GetTrash)
+				trash.DELETE("", ctl.Trash.EmptyTrash)
+				trash.POST("/:type/:id/restore", ctl.Trash.RestoreTrashItem)
+				trash.DELETE("/:type/:id", ctl.Trash.PurgeTrashItem)
+			}
 
 			// Tag Routes
-			v1.GET("/tags", controllers.GetTags)
-			v1.PUT("/tags/:id", middlewares.JwtAuthMiddleware(), controllers.UpdateTag)
-			v1.DELETE("/tags/:id", middlewares.JwtAuthMiddleware(), controllers.DeleteTag)
+			v1.GET("/tags", ctl.Taxonomy.GetTags)
+			v1.GET("/tags/:id", ctl.Taxonomy.GetTag)
+			v1.POST("/tags", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.CreateTag)
+			v1.PUT("/tags/:id", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.UpdateTag)
+			v1.DELETE("/tags/:id", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.DeleteTag)
+			v1.POST("/tags/:id/merge", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.MergeTag)
+			v1.POST("/tags/:id/aliases", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.CreateTagAlias)
+			v1.DELETE("/tags/:id/aliases/:alias_id", middlewares.JwtAuthMiddleware(), ctl.Taxonomy.DeleteTagAlias)
 		}
 	}
 
 	// Serve static files
 	r.Static("/uploads", "./uploads")
 
+	// Frontend with prerendered pages for crawlers and link previews
+	if config.AppConfig.SSREnabled {
+		r.NoRoute(ctl.Frontend.ServeFrontend)
+	}
+
 	return r
 }
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/utils"
+)
+
+var (
+	ErrSlugUnavailable = errors.New("slug is empty, purely numeric or already in use")
+	ErrSeriesNotFound  = errors.New("series not found")
+	ErrCoverNotFound   = errors.New("cover media not found")
+	ErrRenderFailed    = errors.New("failed to render content")
+)
+
+// ArticleService holds the rules for writing and reading articles.
+type ArticleService struct {
+	store repository.Store
+}
+
+func NewArticleService(store repository.Store) *ArticleService {
+	return &ArticleService{store: store}
+}
+
+type CreateArticleInput struct {
+	Title        string   `json:"title" binding:"required"`
+	Content      string   `json:"content" binding:"required"`
+	Slug         string   `json:"slug"` // Derived from the title when empty
+	Draft        bool     `json:"draft"`
+	CategoryID   *uint    `json:"category_id"`
+	Tags         []string `json:"tags"`           // List of tag names
+	CoverMediaID *uint    `json:"cover_media_id"` // Takes precedence over cover_url
+	CoverURL     string   `json:"cover_url"`
+	Pinned       bool     `json:"pinned"`
+	PinOrder     int      `json:"pin_order"`
+	Featured     bool     `json:"featured"`
+	SeriesID     *uint    `json:"series_id"`
+	SeriesOrder  int      `json:"series_order"` // 0 appends to the series
+}
+
+// UpdateArticleInput changes the fields that are set; empty strings and nil
+// leave the current value.
+type UpdateArticleInput struct {
+	Title        string   `json:"title"`
+	Content      string   `json:"content"`
+	Slug         *string  `json:"slug"`
+	Draft        *bool    `json:"draft"`
+	CategoryID   *uint    `json:"category_id"`
+	Tags         []string `json:"tags"`
+	CoverMediaID *uint    `json:"cover_media_id"` // 0 removes the cover
+	CoverURL     *string  `json:"cover_url"`      // "" removes the cover
+	Pinned       *bool    `json:"pinned"`
+	PinOrder     *int     `json:"pin_order"`
+	Featured     *bool    `json:"featured"`
+	SeriesID     *uint    `json:"series_id"` // 0 removes the article from its series
+	SeriesOrder  *int     `json:"series_order"`
+}
+
+// ArticleListOptions selects the articles returned by List.
+type ArticleListOptions struct {
+	repository.ArticleQuery
+	CategoryID         uint // 0 for all
+	IncludeDescendants bool // Also match the subcategories of CategoryID
+}
+
+// SetArticleContent stores the Markdown source together with everything
+// derived from it: the rendered HTML, table of contents, counters and excerpt.
+func SetArticleContent(article *models.Article, content string) error {
+	contentHTML, err := utils.RenderArticleMarkdown(content)
+	if err != nil {
+		return err
+	}
+
+	meta := utils.AnalyzeMarkdown(content)
+	article.Content = content
+	article.ContentHTML = contentHTML
+	article.TOC = meta.TOCJSON()
+	article.WordCount = meta.WordCount
+	article.ReadingTime = meta.ReadingTime
+	article.Excerpt = meta.Excerpt
+	return nil
+}
+
+func (s *ArticleService) List(opts ArticleListOptions) ([]models.Article, error) {
+	q := opts.ArticleQuery
+	if opts.CategoryID != 0 {
+		q.CategoryIDs = []uint{opts.CategoryID}
+		if opts.IncludeDescendants {
+			ids, err := NewTaxonomyService(s.store).CategoryDescendants(opts.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			q.CategoryIDs = ids
+		}
+	}
+	return s.store.Articles().List(q)
+}
+
+// LastChange returns when any article last changed, including edits to
+// drafts and moves to the trash, which can take an article out of a list.
+func (s *ArticleService) LastChange() (time.Time, error) {
+	return s.store.Articles().LastChange()
+}
+
+// Get returns an article with its associations and series navigation.
+// Drafts are only returned when includeDrafts is set.
+func (s *ArticleService) Get(id uint, includeDrafts bool) (*models.Article, error) {
+	article, err := s.store.Articles().Get(id, includeDrafts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadSeriesNav(article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+func (s *ArticleService) Create(authorID uint, input CreateArticleInput) (*models.Article, error) {
+	article := &models.Article{Title: input.Title, AuthorID: authorID}
+	changes := UpdateArticleInput{
+		Content:      input.Content,
+		Slug:         &input.Slug,
+		Draft:        &input.Draft,
+		CategoryID:   input.CategoryID,
+		Tags:         input.Tags,
+		CoverMediaID: input.CoverMediaID,
+		CoverURL:     &input.CoverURL,
+		Pinned:       &input.Pinned,
+		PinOrder:     &input.PinOrder,
+		Featured:     &input.Featured,
+		SeriesID:     input.SeriesID,
+		SeriesOrder:  &input.SeriesOrder,
+	}
+	if changes.Tags == nil {
+		changes.Tags = []string{}
+	}
+
+	var created *models.Article
+	err := s.store.Transaction(func(tx repository.Store) error {
+		tags, err := s.in(tx).apply(article, changes)
+		if err != nil {
+			return err
+		}
+		article.Tags = tags
+		if err := tx.Articles().Create(article); err != nil {
+			return err
+		}
+		created, err = tx.Articles().Get(article.ID, true)
+		return err
+	})
+	return created, err
+}
+
+func (s *ArticleService) Update(id uint, input UpdateArticleInput) (*models.Article, error) {
+	var updated *models.Article
+	err := s.store.Transaction(func(tx repository.Store) error {
+		article, err := tx.Articles().Find(id)
+		if err != nil {
+			return err
+		}
+
+		tags, err := s.in(tx).apply(article, input)
+		if err != nil {
+			return err
+		}
+		if err := tx.Articles().Update(article, tags); err != nil {
+			return err
+		}
+		updated, err = tx.Articles().Get(article.ID, true)
+		return err
+	})
+	return updated, err
+}
+
+// in returns the service working on tx.
+func (s *ArticleService) in(tx repository.Store) *ArticleService {
+	return &ArticleService{store: tx}
+}
+
+// apply copies the set fields of input onto article and resolves its tags,
+// which are nil when input leaves them unchanged. Create and Update share it
+// so both enforce the same rules; it runs inside their transaction, so tags
+// it creates are rolled back when the article cannot be saved.
+func (s *ArticleService) apply(article *models.Article, input UpdateArticleInput) ([]models.Tag, error) {
+	var tags []models.Tag
+	if input.Tags != nil {
+		var err error
+		if tags, err = NewTaxonomyService(s.store).ResolveTags(input.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Title != "" {
+		article.Title = input.Title
+	}
+	if input.Content != "" {
+		if err := SetArticleContent(article, input.Content); err != nil {
+			return nil, ErrRenderFailed
+		}
+	}
+	if input.Slug != nil {
+		if err := s.applySlug(article, *input.Slug); err != nil {
+			return nil, err
+		}
+	}
+	if input.CategoryID != nil {
+		article.CategoryID = input.CategoryID
+	}
+	if input.CoverMediaID != nil || input.CoverURL != nil {
+		url := ""
+		if input.CoverURL != nil {
+			url = *input.CoverURL
+		}
+		if err := s.applyCover(article, input.CoverMediaID, url); err != nil {
+			return nil, err
+		}
+	}
+	if input.Pinned != nil {
+		article.Pinned = *input.Pinned
+	}
+	if input.PinOrder != nil {
+		article.PinOrder = *input.PinOrder
+	}
+	if input.Featured != nil {
+		article.Featured = *input.Featured
+	}
+	if input.Draft != nil {
+		article.Draft = *input.Draft
+	}
+	if input.SeriesID != nil || input.SeriesOrder != nil {
+		seriesID := article.SeriesID
+		if input.SeriesID != nil {
+			seriesID = input.SeriesID
+		}
+		order := 0
+		if input.SeriesOrder != nil {
+			order = *input.SeriesOrder
+		}
+		if err := s.assignSeries(article, seriesID, order); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// applySlug sets the slug requested for article, or derives one from the
+// title when slug is empty and the article has none yet.
+func (s *ArticleService) applySlug(article *models.Article, slug string) error {
+	if slug != "" {
+		slug = utils.Slugify(slug)
+		taken, err := s.store.Articles().SlugTaken(slug, article.ID)
+		if err != nil {
+			return err
+		}
+		if slug == "" || utils.NumericSlug(slug) || taken {
+			return ErrSlugUnavailable
+		}
+		article.Slug = slug
+		return nil
+	}
+
+	if article.Slug == "" {
+		derived, err := s.store.Articles().UniqueSlug(article.Title, article.ID)
+		if err != nil {
+			return err
+		}
+		article.Slug = derived
+	}
+	return nil
+}
+
+// applyCover sets the cover to an uploaded media item, or to the plain URL
+// when mediaID is nil or 0.
+func (s *ArticleService) applyCover(article *models.Article, mediaID *uint, url string) error {
+	if mediaID == nil || *mediaID == 0 {
+		article.CoverMediaID = nil
+		article.CoverURL = url
+		return nil
+	}
+
+	media, err := s.store.Articles().FindMedia(*mediaID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrCoverNotFound
+	}
+	if err != nil {
+		return err
+	}
+	article.CoverMediaID = &media.ID
+	article.CoverURL = media.URL
+	return nil
+}
+
+// assignSeries puts an article into a series (nil or 0 removes it). An order
+// of 0 appends the article at the end.
+func (s *ArticleService) assignSeries(article *models.Article, seriesID *uint, order int) error {
+	if seriesID == nil || *seriesID == 0 {
+		article.SeriesID = nil
+		article.SeriesOrder = 0
+		return nil
+	}
+
+	series, err := s.store.Series().Get(*seriesID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrSeriesNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if order <= 0 {
+		if article.SeriesID != nil && *article.SeriesID == series.ID && article.SeriesOrder > 0 {
+			order = article.SeriesOrder
+		} else if order, err = s.store.Articles().NextSeriesOrder(series.ID); err != nil {
+			return err
+		}
+	}
+
+	article.SeriesID = &series.ID
+	article.SeriesOrder = order
+	return nil
+}
+
+// loadSeriesNav fills in the previous/next navigation of an article that
+// belongs to a series.
+func (s *ArticleService) loadSeriesNav(article *models.Article) error {
+	if article.SeriesID == nil {
+		return nil
+	}
+
+	series, err := s.store.Series().Get(*article.SeriesID)
+	if err != nil {
+		return err
+	}
+	parts, err := s.store.Articles().SeriesParts(series.ID)
+	if err != nil {
+		return err
+	}
+
+	nav := &models.SeriesNavigation{ID: series.ID, Title: series.Title, Slug: series.Slug, Total: len(parts)}
+	for i, part := range parts {
+		if part.ID != article.ID {
+			continue
+		}
+		nav.Position = i + 1
+		if i > 0 {
+			prev := parts[i-1]
+			nav.Previous = &prev
+		}
+		if i+1 < len(parts) {
+			next := parts[i+1]
+			nav.Next = &next
+		}
+	}
+	article.SeriesNav = nav
+	return nil
+}
+
+// Delete soft-deletes an article.
+func (s *ArticleService) Delete(id uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		if _, err := tx.Articles().Find(id); err != nil {
+			return err
+		}
+		_, err := tx.Articles().Delete([]uint{id})
+		return err
+	})
+}
+
+// DeleteMany soft-deletes articles and returns how many were deleted.
+func (s *ArticleService) DeleteMany(ids []uint) (int64, error) {
+	return s.store.Articles().Delete(ids)
+}
+
+// Like counts a like and returns the new total. In a real app, you'd track
+// *who* liked it to prevent duplicates.
+func (s *ArticleService) Like(id uint) (uint, error) {
+	article, err := s.count(id, repository.ArticleRepository.IncrementLikes,
+		models.ArticleEvent{Type: models.ArticleEventLike})
+	if err != nil {
+		return 0, err
+	}
+	return article.Likes, nil
+}
+
+// View counts a page view and returns the new total. Counters are not
+// edits: they leave updated_at alone, which feeds and caches rely on.
+// referrer is the host of the site the reader came from, empty for direct
+// visits.
+func (s *ArticleService) View(id uint, referrer string) (uint, error) {
+	article, err := s.count(id, repository.ArticleRepository.IncrementViews,
+		models.ArticleEvent{Type: models.ArticleEventView, Referrer: referrer})
+	if err != nil {
+		return 0, err
+	}
+	return article.Views, nil
+}
+
+// count bumps a counter of an article, records event for the statistics and
+// returns the article as it is afterwards, so the returned total includes
+// this increment.
+func (s *ArticleService) count(id uint, increment func(repository.ArticleRepository, uint) error, event models.ArticleEvent) (*models.Article, error) {
+	var article *models.Article
+	err := s.store.Transaction(func(tx repository.Store) error {
+		if _, err := tx.Articles().Find(id); err != nil {
+			return err
+		}
+		if err := increment(tx.Articles(), id); err != nil {
+			return err
+		}
+		event.ArticleID = id
+		if err := tx.Articles().RecordEvent(&event); err != nil {
+			return err
+		}
+		var err error
+		article, err = tx.Articles().Find(id)
+		return err
+	})
+	return article, err
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/utils"
+)
+
+var (
+	ErrArticleNotFound = errors.New("article not found")
+	ErrNotCommentOwner = errors.New("you are not the author of this comment")
+)
+
+// CommentService holds the rules for article comments.
+type CommentService struct {
+	store repository.Store
+}
+
+func NewCommentService(store repository.Store) *CommentService {
+	return &CommentService{store: store}
+}
+
+type CreateCommentInput struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// Create adds a comment by userID to an article and returns it with its
+// user.
+func (s *CommentService) Create(articleID, userID uint, input CreateCommentInput) (*models.Comment, error) {
+	if _, err := s.store.Articles().Find(articleID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	contentHTML, err := utils.RenderCommentMarkdown(input.Content)
+	if err != nil {
+		return nil, ErrRenderFailed
+	}
+
+	comment := &models.Comment{
+		Content:     input.Content,
+		ContentHTML: contentHTML,
+		ArticleID:   articleID,
+		UserID:      userID,
+	}
+	if err := s.store.Comments().Create(comment); err != nil {
+		return nil, err
+	}
+	return s.store.Comments().Get(comment.ID)
+}
+
+// List returns the comments of an article.
+func (s *CommentService) List(articleID uint) ([]models.Comment, error) {
+	return s.store.Comments().ListByArticle(articleID)
+}
+
+// Recent returns the newest comments of an article.
+func (s *CommentService) Recent(articleID uint, limit int) ([]models.Comment, error) {
+	return s.store.Comments().Recent(articleID, limit)
+}
+
+// LastChange returns when a comment of an article last changed or was
+// deleted.
+func (s *CommentService) LastChange(articleID uint) (time.Time, error) {
+	return s.store.Comments().LastChange(articleID)
+}
+
+// Delete removes a comment. Only its author may delete it.
+func (s *CommentService) Delete(id, userID uint) error {
+	comment, err := s.store.Comments().Get(id)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		return ErrNotCommentOwner
+	}
+	return s.store.Comments().Delete(comment)
+}
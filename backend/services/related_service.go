@@ -0,0 +1,234 @@
+package services
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/utils"
+)
+
+const (
+	MaxRelatedArticles   = 20
+	maxRelatedCandidates = 200
+	relatedCacheTTL      = time.Hour
+
+	// Ranking weights: a shared tag counts more than the category, text
+	// similarity (0..1) breaks ties between otherwise equal candidates.
+	sharedTagWeight    = 3.0
+	sameCategoryWeight = 2.0
+	similarityWeight   = 4.0
+)
+
+// ArticleRef is a short reference to another article.
+type ArticleRef struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RelatedArticle is a related article with the score it was ranked by.
+type RelatedArticle struct {
+	ArticleRef
+	Excerpt    string  `json:"excerpt"`
+	CoverURL   string  `json:"cover_url"`
+	SharedTags int     `json:"shared_tags"`
+	Score      float64 `json:"score"`
+}
+
+// ArticleContext is the navigation shown around a single article.
+type ArticleContext struct {
+	Previous *ArticleRef      `json:"previous"`
+	Next     *ArticleRef      `json:"next"`
+	Related  []RelatedArticle `json:"related"`
+}
+
+type relatedCacheEntry struct {
+	context ArticleContext
+	expires time.Time
+}
+
+// RelatedService computes the context of published articles and keeps it
+// per article. A write to an article drops the entries it can appear in; see
+// Invalidate.
+type RelatedService struct {
+	store repository.Store
+
+	mu      sync.RWMutex
+	entries map[uint]relatedCacheEntry
+}
+
+func NewRelatedService(store repository.Store) *RelatedService {
+	return &RelatedService{store: store, entries: map[uint]relatedCacheEntry{}}
+}
+
+// Context returns the previous and next articles and up to
+// MaxRelatedArticles related articles of the published article id.
+func (s *RelatedService) Context(id uint) (ArticleContext, error) {
+	article, err := s.store.Articles().Get(id, false)
+	if err != nil {
+		return ArticleContext{}, err
+	}
+
+	s.mu.RLock()
+	entry, ok := s.entries[article.ID]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.context, nil
+	}
+
+	context, err := s.build(article)
+	if err != nil {
+		return ArticleContext{}, err
+	}
+
+	s.mu.Lock()
+	s.entries[article.ID] = relatedCacheEntry{context: context, expires: time.Now().Add(relatedCacheTTL)}
+	s.mu.Unlock()
+	return context, nil
+}
+
+// Reset drops all cached contexts, after writes that touch many articles at
+// once such as imports or taxonomy changes.
+func (s *RelatedService) Reset() {
+	s.mu.Lock()
+	s.entries = map[uint]relatedCacheEntry{}
+	s.mu.Unlock()
+}
+
+// Invalidate drops the cached contexts a write to the articles ids can
+// change: their own, those listing them, those of articles sharing a
+// category or tag with them, and those whose previous and next articles
+// enclose them in time.
+func (s *RelatedService) Invalidate(ids ...uint) {
+	if len(ids) == 0 {
+		return
+	}
+
+	changed, err := s.store.Articles().FindAll(ids)
+	var neighbours []uint
+	if err == nil {
+		neighbours, err = s.store.Articles().Neighbours(ids)
+	}
+	if err != nil {
+		log.Printf("Invalidating related articles of %v failed, clearing the cache: %v", ids, err)
+		s.Reset()
+		return
+	}
+
+	drop := map[uint]bool{}
+	for _, id := range append(neighbours, ids...) {
+		drop[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, entry := range s.entries {
+		if drop[id] || entry.context.mentions(drop) || entry.context.encloses(changed) {
+			delete(s.entries, id)
+		}
+	}
+}
+
+// mentions reports whether the context links to any of ids.
+func (ctx ArticleContext) mentions(ids map[uint]bool) bool {
+	if (ctx.Previous != nil && ids[ctx.Previous.ID]) || (ctx.Next != nil && ids[ctx.Next.ID]) {
+		return true
+	}
+	for _, r := range ctx.Related {
+		if ids[r.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// encloses reports whether one of articles was written between the previous
+// and next article of the context, so it may have become one of them.
+func (ctx ArticleContext) encloses(articles []models.Article) bool {
+	for _, a := range articles {
+		afterPrevious := ctx.Previous == nil || !a.CreatedAt.Before(ctx.Previous.CreatedAt)
+		beforeNext := ctx.Next == nil || !a.CreatedAt.After(ctx.Next.CreatedAt)
+		if afterPrevious && beforeNext {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *RelatedService) build(article *models.Article) (ArticleContext, error) {
+	prev, next, err := s.store.Articles().Adjacent(article)
+	if err != nil {
+		return ArticleContext{}, err
+	}
+	related, err := s.rank(article, MaxRelatedArticles)
+	if err != nil {
+		return ArticleContext{}, err
+	}
+	return ArticleContext{Previous: articleRef(prev), Next: articleRef(next), Related: related}, nil
+}
+
+func articleRef(article *models.Article) *ArticleRef {
+	if article == nil {
+		return nil
+	}
+	return &ArticleRef{ID: article.ID, Title: article.Title, CreatedAt: article.CreatedAt}
+}
+
+func termSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for term := range a {
+		if b[term] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// rank scores the articles sharing a tag or the category with article by
+// shared tags, category and title/excerpt similarity and returns the best
+// ones. Only the maxRelatedCandidates articles with the most shared tags,
+// newest first, are scored.
+func (s *RelatedService) rank(article *models.Article, limit int) ([]RelatedArticle, error) {
+	candidates, err := s.store.Articles().RelatedCandidates(article, maxRelatedCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	ownTerms := utils.Terms(article.Title + " " + article.Excerpt)
+
+	related := []RelatedArticle{}
+	for _, cand := range candidates {
+		a := cand.Article
+		score := float64(cand.SharedTags) * sharedTagWeight
+		if article.CategoryID != nil && a.CategoryID != nil && *article.CategoryID == *a.CategoryID {
+			score += sameCategoryWeight
+		}
+		score += termSimilarity(ownTerms, utils.Terms(a.Title+" "+a.Excerpt)) * similarityWeight
+
+		related = append(related, RelatedArticle{
+			ArticleRef: ArticleRef{ID: a.ID, Title: a.Title, CreatedAt: a.CreatedAt},
+			Excerpt:    a.Excerpt,
+			CoverURL:   a.CoverURL,
+			SharedTags: cand.SharedTags,
+			Score:      score,
+		})
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].CreatedAt.After(related[j].CreatedAt)
+	})
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
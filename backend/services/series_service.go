@@ -0,0 +1,169 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/utils"
+)
+
+var (
+	ErrTitleRequired   = errors.New("title is required")
+	ErrUnknownArticles = errors.New("article_ids contains unknown or duplicate articles")
+)
+
+// SeriesService holds the rules for series of articles.
+type SeriesService struct {
+	store repository.Store
+}
+
+func NewSeriesService(store repository.Store) *SeriesService {
+	return &SeriesService{store: store}
+}
+
+type SeriesInput struct {
+	Title       string  `json:"title"`
+	Slug        *string `json:"slug"`
+	Description *string `json:"description"`
+}
+
+type ReorderSeriesInput struct {
+	ArticleIDs []uint `json:"article_ids" binding:"required"` // In reading order
+}
+
+// SeriesSummary is a series with the number of articles in it.
+type SeriesSummary struct {
+	models.Series
+	ArticleCount int64 `json:"article_count"`
+}
+
+// List returns all series, newest first, with their article counts.
+func (s *SeriesService) List() ([]SeriesSummary, error) {
+	series, err := s.store.Series().List()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.store.Series().ArticleCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SeriesSummary, 0, len(series))
+	for _, item := range series {
+		result = append(result, SeriesSummary{Series: item, ArticleCount: counts[item.ID]})
+	}
+	return result, nil
+}
+
+// Get looks a series up by ID or slug.
+func (s *SeriesService) Get(param string) (*models.Series, error) {
+	if id, slug := idOrSlug(param); slug == "" {
+		return s.store.Series().Get(id)
+	}
+	return s.store.Series().GetBySlug(param)
+}
+
+func (s *SeriesService) Create(authorID uint, input SeriesInput) (*models.Series, error) {
+	if input.Title == "" {
+		return nil, ErrTitleRequired
+	}
+
+	series := &models.Series{Title: input.Title, AuthorID: authorID}
+	err := s.store.Transaction(func(tx repository.Store) error {
+		if err := s.in(tx).apply(series, input); err != nil {
+			return err
+		}
+		return tx.Series().Create(series)
+	})
+	return series, err
+}
+
+func (s *SeriesService) Update(id uint, input SeriesInput) (*models.Series, error) {
+	var series *models.Series
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if series, err = tx.Series().Get(id); err != nil {
+			return err
+		}
+		if input.Title != "" {
+			series.Title = input.Title
+		}
+		if err := s.in(tx).apply(series, input); err != nil {
+			return err
+		}
+		return tx.Series().Save(series)
+	})
+	return series, err
+}
+
+// Delete removes a series. Its articles are kept and detached.
+func (s *SeriesService) Delete(id uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		series, err := tx.Series().Get(id)
+		if err != nil {
+			return err
+		}
+		return tx.Series().Delete(series)
+	})
+}
+
+// Reorder sets the articles of a series to exactly articleIDs, in that
+// order. Articles left out are removed from the series.
+func (s *SeriesService) Reorder(id uint, articleIDs []uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		series, err := tx.Series().Get(id)
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[uint]bool, len(articleIDs))
+		for _, articleID := range articleIDs {
+			if seen[articleID] {
+				return ErrUnknownArticles
+			}
+			seen[articleID] = true
+			if _, err := tx.Articles().Find(articleID); errors.Is(err, repository.ErrNotFound) {
+				return ErrUnknownArticles
+			} else if err != nil {
+				return err
+			}
+		}
+		return tx.Series().SetArticles(series.ID, articleIDs)
+	})
+}
+
+// in returns the service working on tx.
+func (s *SeriesService) in(tx repository.Store) *SeriesService {
+	return &SeriesService{store: tx}
+}
+
+// apply copies description and slug onto series, deriving the slug from the
+// title when none is set yet.
+func (s *SeriesService) apply(series *models.Series, input SeriesInput) error {
+	if input.Description != nil {
+		series.Description = *input.Description
+	}
+
+	if input.Slug != nil && *input.Slug != "" {
+		slug := utils.Slugify(*input.Slug)
+		taken, err := s.store.Series().SlugTaken(slug, series.ID)
+		if err != nil {
+			return err
+		}
+		if slug == "" || utils.NumericSlug(slug) || taken {
+			return ErrSlugUnavailable
+		}
+		series.Slug = slug
+		return nil
+	}
+
+	if series.Slug == "" {
+		slug, err := s.store.Series().UniqueSlug(series.Title, series.ID)
+		if err != nil {
+			return err
+		}
+		series.Slug = slug
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+)
+
+// Deletion modes for categories and tags.
+const (
+	DeleteModeDetach   = "detach"   // Unlink articles, keep them
+	DeleteModeReassign = "reassign" // Move articles to TargetID
+	DeleteModeCascade  = "cascade"  // Move the articles to the trash as well
+)
+
+// DeleteOptions selects how a category or tag deletion treats its articles.
+type DeleteOptions struct {
+	Mode     string
+	TargetID uint // Reassign only
+	DryRun   bool // Only report what would be touched
+}
+
+// DeletePreview reports what a category or tag deletion touches.
+type DeletePreview struct {
+	Mode     string `json:"mode"`
+	TargetID uint   `json:"target_id,omitempty"`
+	DryRun   bool   `json:"dry_run"`
+	Articles int64  `json:"articles"`
+	Comments int64  `json:"comments"`
+	TagLinks int64  `json:"tag_links"`
+}
+
+// DeleteTag removes a tag and returns what it touched, or with DryRun what
+// it would touch. The preview is computed in the same transaction as the
+// deletion so it matches what was actually changed.
+func (s *TaxonomyService) DeleteTag(id uint, opts DeleteOptions) (DeletePreview, error) {
+	preview := DeletePreview{Mode: opts.Mode, TargetID: opts.TargetID, DryRun: opts.DryRun}
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		tag, err := tx.Taxonomy().GetTag(id)
+		if err != nil {
+			return err
+		}
+
+		var target *models.Tag
+		if opts.Mode == DeleteModeReassign {
+			if target, err = tx.Taxonomy().GetTag(opts.TargetID); errors.Is(err, repository.ErrNotFound) {
+				return ErrTargetNotFound
+			} else if err != nil {
+				return err
+			}
+		}
+
+		// Articles in the trash keep the link unless it is reassigned:
+		// restoring them also restores the tag
+		articleIDs, err := tx.Taxonomy().TaggedArticles(tag.ID, opts.Mode == DeleteModeReassign)
+		if err != nil {
+			return err
+		}
+		preview.Articles = int64(len(articleIDs))
+		preview.TagLinks = int64(len(articleIDs))
+		if opts.Mode == DeleteModeCascade {
+			// The trashed articles take their comments and other tags along
+			if preview.Comments, preview.TagLinks, err = tx.Articles().CountDependents(articleIDs); err != nil {
+				return err
+			}
+		}
+
+		if opts.DryRun {
+			return nil
+		}
+
+		switch opts.Mode {
+		case DeleteModeDetach:
+			if err := tx.Taxonomy().UnlinkTag(tag.ID, articleIDs); err != nil {
+				return err
+			}
+		case DeleteModeReassign:
+			if _, err := tx.Taxonomy().MoveTag(tag.ID, target.ID); err != nil {
+				return err
+			}
+		case DeleteModeCascade:
+			if _, err := tx.Articles().Delete(articleIDs); err != nil {
+				return err
+			}
+		}
+		return tx.Taxonomy().DeleteTag(tag.ID)
+	})
+	return preview, err
+}
+
+// DeleteCategory removes a category and returns what it touched, or with
+// DryRun what it would touch. Its children move up to its parent.
+func (s *TaxonomyService) DeleteCategory(id uint, opts DeleteOptions) (DeletePreview, error) {
+	preview := DeletePreview{Mode: opts.Mode, TargetID: opts.TargetID, DryRun: opts.DryRun}
+
+	err := s.store.Transaction(func(tx repository.Store) error {
+		category, err := tx.Taxonomy().GetCategory(id)
+		if err != nil {
+			return err
+		}
+
+		if opts.Mode == DeleteModeReassign {
+			if _, err := tx.Taxonomy().GetCategory(opts.TargetID); errors.Is(err, repository.ErrNotFound) {
+				return ErrTargetNotFound
+			} else if err != nil {
+				return err
+			}
+		}
+
+		// Articles in the trash keep the category, as do the ones a cascade
+		// moves there: restoring them also restores the category
+		articleIDs, err := tx.Taxonomy().CategoryArticles(category.ID, false)
+		if err != nil {
+			return err
+		}
+		preview.Articles = int64(len(articleIDs))
+		if opts.Mode == DeleteModeCascade {
+			if preview.Comments, preview.TagLinks, err = tx.Articles().CountDependents(articleIDs); err != nil {
+				return err
+			}
+		}
+
+		if opts.DryRun {
+			return nil
+		}
+
+		switch opts.Mode {
+		case DeleteModeDetach:
+			if err := tx.Taxonomy().SetArticleCategory(articleIDs, nil); err != nil {
+				return err
+			}
+		case DeleteModeReassign:
+			if err := tx.Taxonomy().SetArticleCategory(articleIDs, &opts.TargetID); err != nil {
+				return err
+			}
+		case DeleteModeCascade:
+			if _, err := tx.Articles().Delete(articleIDs); err != nil {
+				return err
+			}
+		}
+		return tx.Taxonomy().DeleteCategory(category)
+	})
+	return preview, err
+}
@@ -0,0 +1,602 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/utils"
+)
+
+var (
+	ErrNameRequired   = errors.New("name must not be empty")
+	ErrInvalidColor   = errors.New("color must be a hex value like #1e90ff")
+	ErrParentNotFound = errors.New("parent category not found")
+	ErrCategoryCycle  = errors.New("a category cannot be moved below itself or one of its descendants")
+	ErrTargetNotFound = errors.New("target not found")
+	ErrMergeIntoSelf  = errors.New("cannot merge a tag into itself")
+)
+
+// ConflictError reports a name that already belongs to another record.
+type ConflictError struct {
+	Message string
+	ID      uint // The record using the name
+	Trashed bool // The record is in the trash
+}
+
+func (e *ConflictError) Error() string { return e.Message }
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// TaxonomyService holds the rules for categories and tags.
+type TaxonomyService struct {
+	store repository.Store
+}
+
+func NewTaxonomyService(store repository.Store) *TaxonomyService {
+	return &TaxonomyService{store: store}
+}
+
+// TaxonomyMetaInput is the optional metadata accepted when creating or
+// updating a category or tag. Nil fields are left unchanged.
+type TaxonomyMetaInput struct {
+	Slug         *string `json:"slug"`
+	Description  *string `json:"description"`
+	Color        *string `json:"color"`
+	Icon         *string `json:"icon"`
+	CoverMediaID *uint   `json:"cover_media_id"` // 0 removes the cover
+	CoverURL     *string `json:"cover_url"`      // "" removes the cover
+}
+
+type CreateTagInput struct {
+	Name string `json:"name" binding:"required"`
+	TaxonomyMetaInput
+}
+
+type UpdateTagInput struct {
+	Name string `json:"name"`
+	TaxonomyMetaInput
+}
+
+type CreateCategoryInput struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID *uint  `json:"parent_id"`
+	TaxonomyMetaInput
+}
+
+type UpdateCategoryInput struct {
+	Name string `json:"name"`
+	TaxonomyMetaInput
+}
+
+// TagWithUsage is a tag with the number of articles using it and its tag
+// cloud weight (1-5).
+type TagWithUsage struct {
+	models.Tag
+	UsageCount int64 `json:"usage_count"`
+	Weight     int   `json:"weight"`
+}
+
+// CategoryNode is a category with its children and article counts.
+type CategoryNode struct {
+	models.Category
+	ArticleCount int64           `json:"article_count"` // Articles directly in this category
+	TotalCount   int64           `json:"total_count"`   // Including all descendants
+	Children     []*CategoryNode `json:"children"`
+}
+
+// NormalizeTagName trims and collapses whitespace. The display form keeps its
+// case; TagKey is used for case-insensitive comparison.
+func NormalizeTagName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func TagKey(name string) string {
+	return strings.ToLower(NormalizeTagName(name))
+}
+
+// in returns the service working on tx.
+func (s *TaxonomyService) in(tx repository.Store) *TaxonomyService {
+	return &TaxonomyService{store: tx}
+}
+
+// idOrSlug splits a path parameter into a numeric ID or a slug.
+func idOrSlug(param string) (uint, string) {
+	if id, err := strconv.ParseUint(param, 10, 32); err == nil {
+		return uint(id), ""
+	}
+	return 0, param
+}
+
+// FindTag looks a tag up by normalized name or alias.
+func (s *TaxonomyService) FindTag(name string) (*models.Tag, error) {
+	key := TagKey(name)
+	tag, err := s.store.Taxonomy().FindTag(key)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return tag, err
+	}
+	return s.store.Taxonomy().FindTagByAlias(key)
+}
+
+// ResolveTags maps free-text tag names to canonical tags, creating the ones
+// that do not exist yet. Duplicates after normalization are dropped.
+func (s *TaxonomyService) ResolveTags(names []string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	seen := map[uint]bool{}
+
+	for _, name := range names {
+		name = NormalizeTagName(name)
+		if name == "" {
+			continue
+		}
+
+		tag, err := s.FindTag(name)
+		if errors.Is(err, repository.ErrNotFound) {
+			tag, err = s.restoreOrCreateTag(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tags = append(tags, *tag)
+		}
+	}
+	return tags, nil
+}
+
+// restoreOrCreateTag takes a deleted tag of that name out of the trash, or
+// creates a new one. Tag names stay unique across the trash.
+func (s *TaxonomyService) restoreOrCreateTag(name string) (*models.Tag, error) {
+	tag, err := s.store.Taxonomy().FindTrashedTag(TagKey(name))
+	if err == nil {
+		return tag, s.store.Taxonomy().RestoreTag(tag)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	tag = &models.Tag{Name: name}
+	return tag, s.store.Taxonomy().CreateTag(tag)
+}
+
+// Tags lists all tags with their usage counts in creation order.
+func (s *TaxonomyService) Tags() ([]TagWithUsage, error) {
+	tags, err := s.store.Taxonomy().ListTags()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.store.Taxonomy().TagUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	var min, max int64
+	for _, count := range counts {
+		if min == 0 || count < min {
+			min = count
+		}
+		if count > max {
+			max = count
+		}
+	}
+
+	result := make([]TagWithUsage, 0, len(tags))
+	for _, tag := range tags {
+		count := counts[tag.ID]
+		result = append(result, TagWithUsage{Tag: tag, UsageCount: count, Weight: tagCloudWeight(count, min, max)})
+	}
+	return result, nil
+}
+
+// tagCloudWeight maps a usage count to a 1-5 weight on a logarithmic scale so
+// a few very popular tags do not flatten the rest.
+func tagCloudWeight(count, min, max int64) int {
+	if count <= 0 || max <= min {
+		if count > 0 {
+			return 3
+		}
+		return 1
+	}
+	ratio := (math.Log(float64(count)) - math.Log(float64(min))) / (math.Log(float64(max)) - math.Log(float64(min)))
+	return 1 + int(math.Round(ratio*4))
+}
+
+// GetTag looks a tag up by ID or slug and returns it with its aliases.
+func (s *TaxonomyService) GetTag(param string) (*models.Tag, error) {
+	if id, slug := idOrSlug(param); slug == "" {
+		return s.store.Taxonomy().GetTag(id)
+	}
+	return s.store.Taxonomy().GetTagBySlug(param)
+}
+
+// checkTagName fails when name already resolves to a tag other than id, or
+// belongs to a tag in the trash.
+func (s *TaxonomyService) checkTagName(name string, id uint) error {
+	existing, err := s.FindTag(name)
+	if err == nil && existing.ID != id {
+		if id == 0 {
+			return &ConflictError{Message: "Tag already exists", ID: existing.ID}
+		}
+		// Renaming onto another tag's name or alias should be a merge instead
+		return &ConflictError{Message: "Another tag already uses this name, merge the tags instead", ID: existing.ID}
+	}
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return s.checkTrashedName(repository.KindTag, name)
+}
+
+// checkTrashedName fails when a deleted item of kind uses name. Names stay
+// unique across the trash, so such a name cannot be reused until the item is
+// restored or purged.
+func (s *TaxonomyService) checkTrashedName(kind repository.Kind, name string) error {
+	id, err := s.store.Taxonomy().TrashedByName(kind, name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &ConflictError{Message: "An item with this name is in the trash, restore or purge it first", ID: id, Trashed: true}
+}
+
+func (s *TaxonomyService) CreateTag(input CreateTagInput) (*models.Tag, error) {
+	name := NormalizeTagName(input.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+
+	tag := &models.Tag{Name: name}
+	err := s.store.Transaction(func(tx repository.Store) error {
+		if err := s.in(tx).checkTagName(name, 0); err != nil {
+			return err
+		}
+		if err := s.in(tx).applyMeta(repository.KindTag, 0, name, &tag.TaxonomyMeta, input.TaxonomyMetaInput); err != nil {
+			return err
+		}
+		return tx.Taxonomy().CreateTag(tag)
+	})
+	return tag, err
+}
+
+func (s *TaxonomyService) UpdateTag(id uint, input UpdateTagInput) (*models.Tag, error) {
+	var tag *models.Tag
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if tag, err = tx.Taxonomy().GetTag(id); err != nil {
+			return err
+		}
+
+		if input.Name != "" {
+			tag.Name = NormalizeTagName(input.Name)
+		}
+		if tag.Name == "" {
+			return ErrNameRequired
+		}
+		if err := s.in(tx).checkTagName(tag.Name, tag.ID); err != nil {
+			return err
+		}
+		if err := s.in(tx).applyMeta(repository.KindTag, tag.ID, tag.Name, &tag.TaxonomyMeta, input.TaxonomyMetaInput); err != nil {
+			return err
+		}
+		return tx.Taxonomy().SaveTag(tag)
+	})
+	return tag, err
+}
+
+// MergeTag moves every article link of tag id to targetID and removes id
+// for good.
+// The old name and its aliases become aliases of the target, which is
+// returned together with the number of moved links.
+func (s *TaxonomyService) MergeTag(id, targetID uint) (*models.Tag, int64, error) {
+	if id == targetID {
+		return nil, 0, ErrMergeIntoSelf
+	}
+
+	var target *models.Tag
+	var moved int64
+	err := s.store.Transaction(func(tx repository.Store) error {
+		source, err := tx.Taxonomy().GetTag(id)
+		if err != nil {
+			return err
+		}
+		if target, err = tx.Taxonomy().GetTag(targetID); errors.Is(err, repository.ErrNotFound) {
+			return ErrTargetNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if moved, err = tx.Taxonomy().MoveTag(source.ID, target.ID); err != nil {
+			return err
+		}
+		// The source lives on as an alias, so it is not put in the trash
+		// where restoring it would clash with that alias.
+		if err := tx.Trash().Purge(repository.KindTag, []uint{source.ID}); err != nil {
+			return err
+		}
+		if TagKey(source.Name) != TagKey(target.Name) {
+			if err := tx.Taxonomy().CreateAlias(&models.TagAlias{Name: TagKey(source.Name), TagID: target.ID}); err != nil {
+				return err
+			}
+		}
+		target, err = tx.Taxonomy().GetTag(target.ID)
+		return err
+	})
+	return target, moved, err
+}
+
+// CreateTagAlias makes name resolve to the tag tagID.
+func (s *TaxonomyService) CreateTagAlias(tagID uint, name string) (*models.TagAlias, error) {
+	key := TagKey(name)
+	if key == "" {
+		return nil, ErrNameRequired
+	}
+
+	alias := &models.TagAlias{Name: key, TagID: tagID}
+	err := s.store.Transaction(func(tx repository.Store) error {
+		if _, err := tx.Taxonomy().GetTag(tagID); err != nil {
+			return err
+		}
+		existing, err := s.in(tx).FindTag(key)
+		if err == nil {
+			return &ConflictError{Message: "This name already resolves to a tag", ID: existing.ID}
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		return tx.Taxonomy().CreateAlias(alias)
+	})
+	return alias, err
+}
+
+func (s *TaxonomyService) DeleteTagAlias(tagID, aliasID uint) error {
+	alias, err := s.store.Taxonomy().FindAlias(aliasID, tagID)
+	if err != nil {
+		return err
+	}
+	return s.store.Taxonomy().DeleteAlias(alias)
+}
+
+func (s *TaxonomyService) Categories() ([]models.Category, error) {
+	return s.store.Taxonomy().ListCategories()
+}
+
+// GetCategory looks a category up by ID or slug.
+func (s *TaxonomyService) GetCategory(param string) (*models.Category, error) {
+	if id, slug := idOrSlug(param); slug == "" {
+		return s.store.Taxonomy().GetCategory(id)
+	}
+	return s.store.Taxonomy().GetCategoryBySlug(param)
+}
+
+// checkCategoryName fails when name belongs to a category other than id,
+// live or in the trash.
+func (s *TaxonomyService) checkCategoryName(name string, id uint) error {
+	existing, err := s.store.Taxonomy().FindCategory(name)
+	if err == nil && existing.ID != id {
+		return &ConflictError{Message: "Category already exists", ID: existing.ID}
+	}
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return s.checkTrashedName(repository.KindCategory, name)
+}
+
+func (s *TaxonomyService) CreateCategory(input CreateCategoryInput) (*models.Category, error) {
+	category := &models.Category{Name: input.Name, ParentID: input.ParentID}
+	err := s.store.Transaction(func(tx repository.Store) error {
+		if err := s.in(tx).validateParent(0, input.ParentID); err != nil {
+			return err
+		}
+		if err := s.in(tx).checkCategoryName(input.Name, 0); err != nil {
+			return err
+		}
+		if err := s.in(tx).applyMeta(repository.KindCategory, 0, category.Name, &category.TaxonomyMeta, input.TaxonomyMetaInput); err != nil {
+			return err
+		}
+		return tx.Taxonomy().CreateCategory(category)
+	})
+	return category, err
+}
+
+func (s *TaxonomyService) UpdateCategory(id uint, input UpdateCategoryInput) (*models.Category, error) {
+	var category *models.Category
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if category, err = tx.Taxonomy().GetCategory(id); err != nil {
+			return err
+		}
+
+		if input.Name != "" {
+			if err := s.in(tx).checkCategoryName(input.Name, category.ID); err != nil {
+				return err
+			}
+			category.Name = input.Name
+		}
+		if err := s.in(tx).applyMeta(repository.KindCategory, category.ID, category.Name, &category.TaxonomyMeta, input.TaxonomyMetaInput); err != nil {
+			return err
+		}
+		return tx.Taxonomy().SaveCategory(category)
+	})
+	return category, err
+}
+
+// MoveCategory moves a category, together with its subtree, below another
+// parent (nil for the top level).
+func (s *TaxonomyService) MoveCategory(id uint, parentID *uint) (*models.Category, error) {
+	var category *models.Category
+	err := s.store.Transaction(func(tx repository.Store) error {
+		var err error
+		if category, err = tx.Taxonomy().GetCategory(id); err != nil {
+			return err
+		}
+		if err := s.in(tx).validateParent(category.ID, parentID); err != nil {
+			return err
+		}
+		category.ParentID = parentID
+		return tx.Taxonomy().SetCategoryParent(category.ID, parentID)
+	})
+	return category, err
+}
+
+// validateParent checks that parentID exists and that making it the parent
+// of id would not create a cycle. id is 0 for new categories.
+func (s *TaxonomyService) validateParent(id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	if _, err := s.store.Taxonomy().GetCategory(*parentID); errors.Is(err, repository.ErrNotFound) {
+		return ErrParentNotFound
+	} else if err != nil {
+		return err
+	}
+	if id == 0 {
+		return nil
+	}
+
+	descendants, err := s.CategoryDescendants(id)
+	if err != nil {
+		return err
+	}
+	for _, d := range descendants {
+		if d == *parentID {
+			return ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+// CategoryDescendants returns id followed by the IDs of all its descendants.
+func (s *TaxonomyService) CategoryDescendants(id uint) ([]uint, error) {
+	parents, err := s.store.Taxonomy().CategoryParents()
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[uint][]uint{}
+	for child, parent := range parents {
+		if parent != nil {
+			children[*parent] = append(children[*parent], child)
+		}
+	}
+	for _, ids := range children {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+
+	ids := []uint{id}
+	seen := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// CategoryTree nests categories under their parents and fills in the
+// article counts.
+func (s *TaxonomyService) CategoryTree() ([]*CategoryNode, error) {
+	categories, err := s.store.Taxonomy().ListCategories()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.store.Taxonomy().CategoryUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uint]*CategoryNode, len(categories))
+	for _, cat := range categories {
+		nodes[cat.ID] = &CategoryNode{Category: cat, ArticleCount: counts[cat.ID], Children: []*CategoryNode{}}
+	}
+
+	roots := []*CategoryNode{}
+	for _, cat := range categories {
+		if cat.ParentID != nil {
+			if parent, ok := nodes[*cat.ParentID]; ok {
+				parent.Children = append(parent.Children, nodes[cat.ID])
+				continue
+			}
+		}
+		roots = append(roots, nodes[cat.ID])
+	}
+
+	for _, root := range roots {
+		sumCategoryCounts(root)
+	}
+	return roots, nil
+}
+
+func sumCategoryCounts(node *CategoryNode) int64 {
+	node.TotalCount = node.ArticleCount
+	for _, child := range node.Children {
+		node.TotalCount += sumCategoryCounts(child)
+	}
+	return node.TotalCount
+}
+
+// applyMeta validates input and copies it onto meta. kind is the table the
+// slug must be unique in, id the row being updated (0 when new).
+func (s *TaxonomyService) applyMeta(kind repository.Kind, id uint, name string, meta *models.TaxonomyMeta, input TaxonomyMetaInput) error {
+	if input.Color != nil {
+		if *input.Color != "" && !colorPattern.MatchString(*input.Color) {
+			return ErrInvalidColor
+		}
+		meta.Color = *input.Color
+	}
+	if input.Description != nil {
+		meta.Description = *input.Description
+	}
+	if input.Icon != nil {
+		meta.Icon = *input.Icon
+	}
+
+	if input.CoverMediaID != nil || input.CoverURL != nil {
+		meta.CoverMediaID, meta.CoverURL = nil, ""
+		if input.CoverURL != nil {
+			meta.CoverURL = *input.CoverURL
+		}
+		if input.CoverMediaID != nil && *input.CoverMediaID != 0 {
+			media, err := s.store.Articles().FindMedia(*input.CoverMediaID)
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrCoverNotFound
+			}
+			if err != nil {
+				return err
+			}
+			meta.CoverMediaID, meta.CoverURL = &media.ID, media.URL
+		}
+	}
+
+	switch {
+	case input.Slug != nil && *input.Slug != "":
+		slug := utils.Slugify(*input.Slug)
+		taken, err := s.store.Taxonomy().SlugTaken(kind, slug, id)
+		if err != nil {
+			return err
+		}
+		if slug == "" || utils.NumericSlug(slug) || taken {
+			return ErrSlugUnavailable
+		}
+		meta.Slug = slug
+	case meta.Slug == "":
+		// Slugs stay stable across renames unless set explicitly
+		slug, err := s.store.Taxonomy().UniqueSlug(kind, name, id)
+		if err != nil {
+			return err
+		}
+		meta.Slug = slug
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/repository/memory"
+	"github.com/your-username/blog-backend/services"
+)
+
+func strptr(s string) *string { return &s }
+
+func createTag(t *testing.T, svc *services.TaxonomyService, name string) *models.Tag {
+	t.Helper()
+	tag, err := svc.CreateTag(services.CreateTagInput{Name: name})
+	if err != nil {
+		t.Fatalf("CreateTag(%q): %v", name, err)
+	}
+	return tag
+}
+
+func TestResolveTagsNormalizesAndDedupes(t *testing.T) {
+	svc := services.NewTaxonomyService(memory.NewStore())
+
+	tags, err := svc.ResolveTags([]string{"  Go ", "go", "Web   Dev", "", "GO"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("got %d tags, want 2: %+v", len(tags), tags)
+	}
+	if tags[0].Name != "Go" || tags[0].Slug != "go" {
+		t.Errorf("first tag = %q (%q), want Go (go)", tags[0].Name, tags[0].Slug)
+	}
+	if tags[1].Name != "Web Dev" || tags[1].Slug != "web-dev" {
+		t.Errorf("second tag = %q (%q), want Web Dev (web-dev)", tags[1].Name, tags[1].Slug)
+	}
+
+	again, err := svc.ResolveTags([]string{"WEB DEV"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 1 || again[0].ID != tags[1].ID {
+		t.Errorf("resolving an existing name created a new tag: %+v", again)
+	}
+}
+
+func TestResolveTagsFollowsAliases(t *testing.T) {
+	svc := services.NewTaxonomyService(memory.NewStore())
+	js := createTag(t, svc, "JavaScript")
+	if _, err := svc.CreateTagAlias(js.ID, "JS"); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := svc.ResolveTags([]string{"js", "javascript"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].ID != js.ID {
+		t.Fatalf("got %+v, want only tag %d", tags, js.ID)
+	}
+
+	var conflict *services.ConflictError
+	if _, err := svc.CreateTag(services.CreateTagInput{Name: "js"}); !errors.As(err, &conflict) || conflict.ID != js.ID {
+		t.Errorf("creating a tag named like an alias: got %v, want a conflict with tag %d", err, js.ID)
+	}
+}
+
+func TestResolveTagsRestoresTrashedTag(t *testing.T) {
+	svc := services.NewTaxonomyService(memory.NewStore())
+	rust := createTag(t, svc, "Rust")
+	if _, err := svc.DeleteTag(rust.ID, services.DeleteOptions{Mode: services.DeleteModeDetach}); err != nil {
+		t.Fatal(err)
+	}
+
+	var conflict *services.ConflictError
+	if _, err := svc.CreateTag(services.CreateTagInput{Name: "rust"}); !errors.As(err, &conflict) || !conflict.Trashed || conflict.ID != rust.ID {
+		t.Errorf("creating a tag named like a trashed one: got %v, want a trash conflict with tag %d", err, rust.ID)
+	}
+
+	tags, err := svc.ResolveTags([]string{"RUST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].ID != rust.ID {
+		t.Fatalf("got %+v, want the trashed tag %d back", tags, rust.ID)
+	}
+	if _, err := svc.GetTag("rust"); err != nil {
+		t.Errorf("restored tag is not found by slug: %v", err)
+	}
+}
+
+func TestTagSlugs(t *testing.T) {
+	svc := services.NewTaxonomyService(memory.NewStore())
+	first := createTag(t, svc, "C#")
+	second := createTag(t, svc, "C")
+	if first.Slug != "c" || second.Slug != "c-2" {
+		t.Errorf("slugs = %q, %q, want c, c-2", first.Slug, second.Slug)
+	}
+
+	for _, slug := range []string{"c", "!!!"} {
+		_, err := svc.UpdateTag(second.ID, services.UpdateTagInput{TaxonomyMetaInput: services.TaxonomyMetaInput{Slug: strptr(slug)}})
+		if !errors.Is(err, services.ErrSlugUnavailable) {
+			t.Errorf("slug %q: got %v, want ErrSlugUnavailable", slug, err)
+		}
+	}
+
+	renamed, err := svc.UpdateTag(second.ID, services.UpdateTagInput{Name: "C Language"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Slug != "c-2" {
+		t.Errorf("renaming changed the slug to %q", renamed.Slug)
+	}
+
+	custom, err := svc.UpdateTag(second.ID, services.UpdateTagInput{TaxonomyMetaInput: services.TaxonomyMetaInput{Slug: strptr("C Lang")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if custom.Slug != "c-lang" {
+		t.Errorf("explicit slug = %q, want c-lang", custom.Slug)
+	}
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	store := memory.NewStore()
+	svc := services.NewTaxonomyService(store)
+
+	failed := errors.New("failed")
+	err := store.Transaction(func(tx repository.Store) error {
+		if _, err := services.NewTaxonomyService(tx).ResolveTags([]string{"Temporary"}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("got %v, want the error of fn", err)
+	}
+	if _, err := svc.FindTag("Temporary"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("tag created in a failed transaction still exists: %v", err)
+	}
+}
+
+func TestCreateArticleRollsBackTags(t *testing.T) {
+	store := memory.NewStore()
+	missing := uint(99)
+
+	_, err := services.NewArticleService(store).Create(1, services.CreateArticleInput{
+		Title:    "Hello",
+		Content:  "Hello world",
+		Tags:     []string{"Brand New"},
+		SeriesID: &missing,
+	})
+	if !errors.Is(err, services.ErrSeriesNotFound) {
+		t.Fatalf("got %v, want ErrSeriesNotFound", err)
+	}
+	if _, err := services.NewTaxonomyService(store).FindTag("brand new"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("tag of the failed article was kept: %v", err)
+	}
+}
+
+func TestDeleteTagPreviewMatchesDeletion(t *testing.T) {
+	store := memory.NewStore()
+	svc := services.NewTaxonomyService(store)
+	for _, title := range []string{"One", "Two"} {
+		if _, err := services.NewArticleService(store).Create(1, services.CreateArticleInput{Title: title, Content: "x", Tags: []string{"Go"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tag, err := svc.FindTag("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dry, err := svc.DeleteTag(tag.ID, services.DeleteOptions{Mode: services.DeleteModeDetach, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetTag("go"); err != nil {
+		t.Fatalf("dry run deleted the tag: %v", err)
+	}
+
+	done, err := svc.DeleteTag(tag.ID, services.DeleteOptions{Mode: services.DeleteModeDetach})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dry.Articles != 2 || done.Articles != dry.Articles || done.TagLinks != dry.TagLinks {
+		t.Errorf("dry run %+v does not match deletion %+v", dry, done)
+	}
+}
+
+func TestCascadeMovesArticlesToTrash(t *testing.T) {
+	store := memory.NewStore()
+	svc := services.NewTaxonomyService(store)
+	article, err := services.NewArticleService(store).Create(1, services.CreateArticleInput{Title: "One", Content: "x", Tags: []string{"Go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.DeleteTag(article.Tags[0].ID, services.DeleteOptions{Mode: services.DeleteModeCascade}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := services.NewTrashService(store).List([]repository.Kind{repository.KindArticle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].ID != article.ID {
+		t.Fatalf("trash = %+v, want article %d", records, article.ID)
+	}
+
+	if err := services.NewTrashService(store).Restore(repository.KindArticle, article.ID); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := services.NewArticleService(store).Get(article.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored.Tags) != 1 || restored.Tags[0].Name != "Go" {
+		t.Errorf("restored article lost its tag: %+v", restored.Tags)
+	}
+}
+
+func TestSlugsAreNeverNumericAndStayReservedInTrash(t *testing.T) {
+	svc := services.NewTaxonomyService(memory.NewStore())
+	year := createTag(t, svc, "2024")
+	if year.Slug != "item-2024" {
+		t.Errorf("derived slug = %q, want item-2024", year.Slug)
+	}
+	if _, err := svc.UpdateTag(year.ID, services.UpdateTagInput{TaxonomyMetaInput: services.TaxonomyMetaInput{Slug: strptr("2024")}}); !errors.Is(err, services.ErrSlugUnavailable) {
+		t.Errorf("numeric slug: got %v, want ErrSlugUnavailable", err)
+	}
+
+	old := createTag(t, svc, "Old")
+	if _, err := svc.DeleteTag(old.ID, services.DeleteOptions{Mode: services.DeleteModeDetach}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UpdateTag(year.ID, services.UpdateTagInput{TaxonomyMetaInput: services.TaxonomyMetaInput{Slug: strptr("old")}}); !errors.Is(err, services.ErrSlugUnavailable) {
+		t.Errorf("slug of a trashed tag: got %v, want ErrSlugUnavailable", err)
+	}
+	if tag := createTag(t, svc, "Old News"); tag.Slug != "old-news" {
+		t.Errorf("slug = %q, want old-news", tag.Slug)
+	}
+}
+
+func TestCategoryNamesAreUnique(t *testing.T) {
+	svc := services.NewTaxonomyService(memory.NewStore())
+	golang, err := svc.CreateCategory(services.CreateCategoryInput{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rust, err := svc.CreateCategory(services.CreateCategoryInput{Name: "Rust"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var conflict *services.ConflictError
+	if _, err := svc.CreateCategory(services.CreateCategoryInput{Name: "go"}); !errors.As(err, &conflict) || conflict.ID != golang.ID {
+		t.Errorf("creating a duplicate category: got %v, want a conflict with category %d", err, golang.ID)
+	}
+	if _, err := svc.UpdateCategory(rust.ID, services.UpdateCategoryInput{Name: "Go"}); !errors.As(err, &conflict) || conflict.ID != golang.ID {
+		t.Errorf("renaming onto another category: got %v, want a conflict with category %d", err, golang.ID)
+	}
+	if _, err := svc.UpdateCategory(golang.ID, services.UpdateCategoryInput{Name: "Go"}); err != nil {
+		t.Errorf("keeping the name of a category: %v", err)
+	}
+}
+
+func TestMergedTagsSkipTheTrash(t *testing.T) {
+	store := memory.NewStore()
+	svc := services.NewTaxonomyService(store)
+	golang := createTag(t, svc, "Golang")
+	target := createTag(t, svc, "Go")
+
+	if _, _, err := svc.MergeTag(golang.ID, target.ID); err != nil {
+		t.Fatal(err)
+	}
+	records, err := services.NewTrashService(store).List([]repository.Kind{repository.KindTag})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("trash = %+v, want no tags", records)
+	}
+	if tag, err := svc.FindTag("golang"); err != nil || tag.ID != target.ID {
+		t.Errorf("merged name resolves to %+v, %v, want tag %d", tag, err, target.ID)
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/your-username/blog-backend/repository"
+)
+
+var ErrParentTrashed = errors.New("the article of this comment is in the trash, restore the article first")
+
+// TrashKinds lists the kinds of trashed records in purge order: purging
+// articles also removes their comments.
+var TrashKinds = []repository.Kind{repository.KindArticle, repository.KindComment, repository.KindTag, repository.KindCategory}
+
+// TrashService restores and permanently deletes soft-deleted records.
+type TrashService struct {
+	store repository.Store
+}
+
+func NewTrashService(store repository.Store) *TrashService {
+	return &TrashService{store: store}
+}
+
+// List returns the trashed records of kinds, most recently deleted first.
+func (s *TrashService) List(kinds []repository.Kind) ([]repository.TrashedRecord, error) {
+	records := []repository.TrashedRecord{}
+	for _, kind := range kinds {
+		list, err := s.store.Trash().List(kind)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, list...)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].DeletedAt.After(records[j].DeletedAt) })
+	return records, nil
+}
+
+// Restore takes a record out of the trash. Restoring an article also
+// restores its category and tags if they were deleted since, so it comes
+// back as it was. Comments can only be restored while their article is not
+// in the trash.
+func (s *TrashService) Restore(kind repository.Kind, id uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		return s.in(tx).restore(kind, id)
+	})
+}
+
+// in returns the service working on tx.
+func (s *TrashService) in(tx repository.Store) *TrashService {
+	return &TrashService{store: tx}
+}
+
+func (s *TrashService) restore(kind repository.Kind, id uint) error {
+	record, err := s.store.Trash().Find(kind, id)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case repository.KindArticle:
+		if record.CategoryID != nil {
+			if err := s.restoreIfTrashed(repository.KindCategory, *record.CategoryID); err != nil {
+				return err
+			}
+		}
+		for _, tagID := range record.TagIDs {
+			if err := s.restoreIfTrashed(repository.KindTag, tagID); err != nil {
+				return err
+			}
+		}
+	case repository.KindComment:
+		if _, err := s.store.Articles().Find(*record.ArticleID); errors.Is(err, repository.ErrNotFound) {
+			return ErrParentTrashed
+		} else if err != nil {
+			return err
+		}
+	case repository.KindCategory:
+		// The category moves to the top level when its parent is gone
+		if record.ParentID != nil {
+			_, err := s.store.Taxonomy().GetCategory(*record.ParentID)
+			if errors.Is(err, repository.ErrNotFound) {
+				err = s.store.Taxonomy().SetCategoryParent(record.ID, nil)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return s.store.Trash().Restore(kind, id)
+}
+
+// restoreIfTrashed restores a record that is in the trash and leaves live
+// records alone.
+func (s *TrashService) restoreIfTrashed(kind repository.Kind, id uint) error {
+	err := s.restore(kind, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Purge permanently deletes a record from the trash.
+func (s *TrashService) Purge(kind repository.Kind, id uint) error {
+	return s.store.Transaction(func(tx repository.Store) error {
+		if _, err := tx.Trash().Find(kind, id); err != nil {
+			return err
+		}
+		return tx.Trash().Purge(kind, []uint{id})
+	})
+}
+
+// Empty permanently deletes the records of kinds that were deleted before
+// the cutoff and returns how many there were per kind.
+func (s *TrashService) Empty(kinds []repository.Kind, before time.Time) (map[repository.Kind]int, error) {
+	counts := map[repository.Kind]int{}
+	err := s.store.Transaction(func(tx repository.Store) error {
+		for _, kind := range kinds {
+			ids, err := tx.Trash().Expired(kind, before)
+			if err != nil {
+				return err
+			}
+			if err := tx.Trash().Purge(kind, ids); err != nil {
+				return err
+			}
+			counts[kind] = len(ids)
+		}
+		return nil
+	})
+	return counts, err
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/your-username/blog-backend/models"
+	"github.com/your-username/blog-backend/repository"
+	"github.com/your-username/blog-backend/utils"
+)
+
+var (
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrInvalidPassword    = errors.New("invalid password")
+)
+
+// UserService holds the rules for the blog owner's account. The blog has a
+// single user: only the first registration succeeds.
+type UserService struct {
+	store repository.Store
+}
+
+func NewUserService(store repository.Store) *UserService {
+	return &UserService{store: store}
+}
+
+type RegisterInput struct {
+	Username string `json:"username" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type LoginInput struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type UpdateProfileInput struct {
+	AvatarURL    string `json:"avatar_url"` // Empty keeps the current avatar
+	Bio          string `json:"bio"`
+	SocialLinks  string `json:"social_links"`
+	SponsorLinks string `json:"sponsor_links"`
+	FriendLinks  string `json:"friend_links"`
+}
+
+// RegistrationAllowed reports whether nobody has registered yet, along with
+// the number of users.
+func (s *UserService) RegistrationAllowed() (bool, int64, error) {
+	count, err := s.store.Users().Count()
+	return count == 0, count, err
+}
+
+func (s *UserService) Register(input RegisterInput) (*models.User, error) {
+	allowed, _, err := s.RegistrationAllowed()
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrRegistrationClosed
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{Username: input.Username, Email: input.Email, Password: hashedPassword}
+	return user, s.store.Users().Create(user)
+}
+
+// Login checks the credentials and returns a new JWT.
+func (s *UserService) Login(input LoginInput) (string, error) {
+	user, err := s.store.Users().FindByUsername(input.Username)
+	if err != nil {
+		return "", err
+	}
+	if err := utils.CheckPassword(input.Password, user.Password); err != nil {
+		return "", ErrInvalidPassword
+	}
+	return utils.GenerateToken(user.ID)
+}
+
+func (s *UserService) Profile(id uint) (*models.User, error) {
+	return s.store.Users().Find(id)
+}
+
+// Author looks a user up by ID or username.
+func (s *UserService) Author(param string) (*models.User, error) {
+	if id, username := idOrSlug(param); username == "" {
+		return s.store.Users().Find(id)
+	}
+	return s.store.Users().FindByUsername(param)
+}
+
+func (s *UserService) UpdateProfile(id uint, input UpdateProfileInput) (*models.User, error) {
+	user, err := s.store.Users().Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.AvatarURL != "" {
+		user.AvatarURL = input.AvatarURL
+	}
+	user.Bio = input.Bio
+	user.SocialLinks = input.SocialLinks
+	user.SponsorLinks = input.SponsorLinks
+	user.FriendLinks = input.FriendLinks
+
+	return user, s.store.Users().Save(user)
+}
+
+// SiteAuthor returns the author shown on the about page: the user with ID 1,
+// or the first user when that one does not exist.
+func (s *UserService) SiteAuthor() (*models.User, error) {
+	user, err := s.store.Users().Find(1)
+	if errors.Is(err, repository.ErrNotFound) {
+		return s.store.Users().First()
+	}
+	return user, err
+}
@@ -0,0 +1,46 @@
+// Package templates holds the html/template files used to prerender pages
+// for crawlers and link previews.
+package templates
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"time"
+)
+
+//go:embed *.tmpl
+var files embed.FS
+
+// Pages contains the "head" template with the page metadata, one body
+// template per page type ("article", "list" and "author") and "document", the
+// page shell used when no frontend build is served.
+var Pages = template.Must(template.New("pages").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+	"iso":  func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).ParseFS(files, "*.tmpl"))
+
+// Hash identifies the current template files. It changes whenever a page
+// rendered from them might look different.
+func Hash() string {
+	h := sha256.New()
+	err := fs.WalkDir(files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := files.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s %d\n", name, len(data))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		panic(err) // The files are embedded, reading them cannot fail
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// MoreMarker splits a manual excerpt from the rest of an article.
+const MoreMarker = "<!--more-->"
+
+const (
+	excerptLength     = 200 // Runes in an automatic excerpt
+	wordsPerMinute    = 200 // Space separated languages
+	cjkCharsPerMinute = 300
+)
+
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+type ContentMeta struct {
+	TOC         []TOCEntry
+	WordCount   int
+	ReadingTime int // Minutes, at least 1
+	Excerpt     string
+}
+
+// TOCJSON encodes the table of contents for storage.
+func (m ContentMeta) TOCJSON() string {
+	b, err := json.Marshal(m.TOC)
+	if err != nil || len(m.TOC) == 0 {
+		return "[]"
+	}
+	return string(b)
+}
+
+// AnalyzeMarkdown derives the table of contents, word count, reading time and
+// excerpt of a Markdown document. Heading IDs match those in the rendered HTML.
+// Text before a MoreMarker becomes the excerpt; otherwise the opening text is
+// truncated.
+func AnalyzeMarkdown(source string) ContentMeta {
+	body, toc := extractText(source)
+
+	words, cjk := countWords(body)
+	minutes := float64(words)/wordsPerMinute + float64(cjk)/cjkCharsPerMinute
+
+	meta := ContentMeta{
+		TOC:         toc,
+		WordCount:   words + cjk,
+		ReadingTime: int(math.Max(1, math.Ceil(minutes))),
+	}
+
+	if before, _, found := strings.Cut(source, MoreMarker); found {
+		excerpt, _ := extractText(before)
+		meta.Excerpt = collapseSpace(excerpt)
+	} else {
+		meta.Excerpt = truncateRunes(collapseSpace(body), excerptLength)
+	}
+
+	return meta
+}
+
+// extractText returns the prose of a document, skipping code and raw HTML,
+// together with its headings.
+func extractText(source string) (string, []TOCEntry) {
+	src := []byte(source)
+	doc := markdown.Parser().Parse(text.NewReader(src))
+
+	toc := []TOCEntry{}
+	var body strings.Builder
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			entry := TOCEntry{Level: node.Level, Text: nodeText(node, src)}
+			if id, ok := node.AttributeString("id"); ok {
+				if b, ok := id.([]byte); ok {
+					entry.ID = string(b)
+				}
+			}
+			toc = append(toc, entry)
+			return ast.WalkSkipChildren, nil
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph, *ast.TextBlock:
+			body.WriteString(nodeText(node, src))
+			body.WriteString("\n")
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return body.String(), toc
+}
+
+// nodeText concatenates the inline text below n.
+func nodeText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := child.(type) {
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			sb.Write(node.Segment.Value(src))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				sb.WriteString(" ")
+			}
+		case *ast.String:
+			sb.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// countWords counts space separated words and CJK characters separately;
+// every CJK character counts as one word.
+func countWords(s string) (words, cjk int) {
+	inWord := false
+	for _, r := range s {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		case r == '\'' || r == '-' || r == '_':
+			// Keep contractions and hyphenated words together
+		default:
+			inWord = false
+		}
+	}
+	return words, cjk
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
+
+// Terms splits text into lowercase words for similarity comparisons. CJK text
+// has no spaces, so it contributes overlapping character bigrams instead.
+func Terms(s string) map[string]bool {
+	terms := map[string]bool{}
+	var word []rune
+	var prevCJK rune
+
+	flush := func() {
+		if len(word) > 1 {
+			terms[string(word)] = true
+		}
+		word = word[:0]
+	}
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case isCJK(r):
+			flush()
+			if prevCJK != 0 {
+				terms[string([]rune{prevCJK, r})] = true
+			}
+			prevCJK = r
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flush()
+		}
+		prevCJK = 0
+	}
+	flush()
+	return terms
+}
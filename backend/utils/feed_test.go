@@ -0,0 +1,163 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/your-username/blog-backend/utils"
+)
+
+func sampleFeed() utils.Feed {
+	published := time.Date(2024, 3, 1, 8, 0, 0, 0, time.FixedZone("CET", 3600))
+	return utils.Feed{
+		Title:       "Blog",
+		Description: "Notes & links",
+		Link:        "https://example.com/",
+		FeedURL:     "https://api.example.com/feed.xml",
+		Updated:     published.Add(48 * time.Hour),
+		Items: []utils.FeedItem{
+			{
+				ID:          "https://example.com/articles/2",
+				URL:         "https://example.com/articles/2",
+				Title:       "Second <post>",
+				Summary:     "Short",
+				ContentHTML: "<p>Full &amp; rich</p>",
+				Author:      "admin",
+				Published:   published.Add(24 * time.Hour),
+				Updated:     published.Add(48 * time.Hour),
+				Categories:  []string{"Go", "web"},
+			},
+			{
+				ID:        "tag:example.com,2024:1",
+				URL:       "https://example.com/articles/1",
+				Title:     "First",
+				Summary:   "Only an excerpt",
+				Published: published,
+				Updated:   published,
+			},
+		},
+	}
+}
+
+func TestFeedFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		encode  func(utils.Feed) ([]byte, error)
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "rss",
+			encode: utils.Feed.RSS,
+			want: []string{
+				`<rss version="2.0"`,
+				`<atom:link href="https://api.example.com/feed.xml" rel="self" type="application/rss+xml"></atom:link>`,
+				`<lastBuildDate>Sun, 03 Mar 2024 07:00:00 +0000</lastBuildDate>`,
+				`<title>Second &lt;post&gt;</title>`,
+				`<guid isPermaLink="true">https://example.com/articles/2</guid>`,
+				`<guid isPermaLink="false">tag:example.com,2024:1</guid>`,
+				`<content:encoded>&lt;p&gt;Full &amp;amp; rich&lt;/p&gt;</content:encoded>`,
+				`<dc:creator>admin</dc:creator>`,
+				`<pubDate>Sat, 02 Mar 2024 07:00:00 +0000</pubDate>`,
+				`<category>Go</category>`,
+				`<category>web</category>`,
+			},
+			notWant: []string{`<dc:creator></dc:creator>`},
+		},
+		{
+			name:   "atom",
+			encode: utils.Feed.Atom,
+			want: []string{
+				`<feed xmlns="http://www.w3.org/2005/Atom">`,
+				`<id>https://api.example.com/feed.xml</id>`,
+				`<updated>2024-03-03T07:00:00Z</updated>`,
+				`<link href="https://example.com/" rel="alternate" type="text/html"></link>`,
+				`<published>2024-03-02T07:00:00Z</published>`,
+				`<content type="html">&lt;p&gt;Full &amp;amp; rich&lt;/p&gt;</content>`,
+				`<summary type="text">Only an excerpt</summary>`,
+				`<category term="Go"></category>`,
+			},
+			notWant: []string{`<content type="html"></content>`, `<name></name>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.encode(sampleFeed())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := xml.Unmarshal(body, new(struct{})); err != nil {
+				t.Fatalf("output is not well-formed XML: %v", err)
+			}
+			out := string(body)
+			if !strings.HasPrefix(out, xml.Header) {
+				t.Errorf("output does not start with the XML header")
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("output lacks %s\n%s", s, out)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("output contains %s", s)
+				}
+			}
+		})
+	}
+}
+
+func TestFeedJSON(t *testing.T) {
+	body, err := sampleFeed().JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feed struct {
+		Version string `json:"version"`
+		FeedURL string `json:"feed_url"`
+		Items   []struct {
+			ID            string              `json:"id"`
+			ContentHTML   string              `json:"content_html"`
+			ContentText   string              `json:"content_text"`
+			DatePublished string              `json:"date_published"`
+			Authors       []map[string]string `json:"authors"`
+			Tags          []string            `json:"tags"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &feed); err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.Version != "https://jsonfeed.org/version/1.1" || feed.FeedURL != "https://api.example.com/feed.xml" {
+		t.Errorf("version %q, feed_url %q", feed.Version, feed.FeedURL)
+	}
+	if len(feed.Items) != 2 {
+		t.Fatalf("%d items, want 2", len(feed.Items))
+	}
+
+	tests := []struct {
+		id          string
+		contentHTML string
+		contentText string
+		published   string
+		authors     int
+		tags        []string
+	}{
+		{"https://example.com/articles/2", "<p>Full &amp; rich</p>", "", "2024-03-02T07:00:00Z", 1, []string{"Go", "web"}},
+		// Without HTML the summary is the text content, as every item
+		// needs one of them.
+		{"tag:example.com,2024:1", "", "Only an excerpt", "2024-03-01T07:00:00Z", 0, nil},
+	}
+	for i, want := range tests {
+		got := feed.Items[i]
+		if got.ID != want.id || got.ContentHTML != want.contentHTML || got.ContentText != want.contentText ||
+			got.DatePublished != want.published || len(got.Authors) != want.authors ||
+			strings.Join(got.Tags, ",") != strings.Join(want.tags, ",") {
+			t.Errorf("item %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
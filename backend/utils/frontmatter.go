@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// FrontMatter is the article metadata at the top of a Markdown file, as
+// written by Hugo, Jekyll, Hexo and similar generators.
+type FrontMatter struct {
+	Title      string
+	Date       time.Time // Zero when missing
+	Slug       string
+	Tags       []string
+	Categories []string
+	Draft      bool
+}
+
+// frontMatterDateLayouts are tried in order for dates given as strings.
+var frontMatterDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseFrontMatter splits a Markdown document into its front matter and body.
+// YAML front matter is delimited by "---" lines and TOML by "+++" lines; a
+// document without either has empty metadata.
+func ParseFrontMatter(source []byte) (FrontMatter, string, error) {
+	text := strings.TrimPrefix(string(source), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	delimiter := ""
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delimiter = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delimiter = "+++"
+	default:
+		return FrontMatter{}, text, nil
+	}
+
+	rest := text[len(delimiter)+1:]
+	header, body, closed := "", "", false
+	offset := 0
+	for _, line := range strings.SplitAfter(rest, "\n") {
+		if strings.TrimRight(line, " \t\n") == delimiter {
+			header, body, closed = rest[:offset], rest[offset+len(line):], true
+			break
+		}
+		offset += len(line)
+	}
+	if !closed {
+		return FrontMatter{}, "", fmt.Errorf("front matter is not closed by %q", delimiter)
+	}
+
+	raw := map[string]interface{}{}
+	var err error
+	if delimiter == "---" {
+		err = yaml.Unmarshal([]byte(header), &raw)
+	} else {
+		err = toml.Unmarshal([]byte(header), &raw)
+	}
+	if err != nil {
+		return FrontMatter{}, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	fields := make(map[string]interface{}, len(raw))
+	for key, value := range raw {
+		fields[strings.ToLower(key)] = value
+	}
+
+	var fm FrontMatter
+	fm.Title = strings.TrimSpace(stringValue(fields["title"]))
+	fm.Slug = strings.TrimSpace(stringValue(fields["slug"]))
+	if fm.Date, err = timeValue(fields["date"]); err != nil {
+		return FrontMatter{}, "", err
+	}
+	fm.Tags = listValue(fields["tags"])
+	fm.Categories = listValue(fields["categories"])
+	if len(fm.Categories) == 0 {
+		fm.Categories = listValue(fields["category"]) // Jekyll
+	}
+	fm.Draft = boolValue(fields["draft"])
+	if published, ok := fields["published"]; ok && !boolValue(published) {
+		fm.Draft = true // Jekyll and Hexo
+	}
+
+	return fm, strings.TrimLeft(body, "\n"), nil
+}
+
+// FormatFrontMatter writes body with fm as YAML front matter, the format
+// understood by ParseFrontMatter and most static site generators.
+func FormatFrontMatter(fm FrontMatter, body string) ([]byte, error) {
+	header := struct {
+		Title      string     `yaml:"title"`
+		Date       *time.Time `yaml:"date,omitempty"`
+		Slug       string     `yaml:"slug,omitempty"`
+		Draft      bool       `yaml:"draft"`
+		Tags       []string   `yaml:"tags,omitempty"`
+		Categories []string   `yaml:"categories,omitempty"`
+	}{Title: fm.Title, Slug: fm.Slug, Draft: fm.Draft, Tags: fm.Tags, Categories: fm.Categories}
+	if !fm.Date.IsZero() {
+		date := fm.Date.UTC()
+		header.Date = &date
+	}
+
+	data, err := yaml.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(data)
+	buf.WriteString("---\n\n")
+	buf.WriteString(strings.TrimLeft(body, "\n"))
+	if !strings.HasSuffix(body, "\n") {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+func stringValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// listValue accepts both a list and a single string. Strings are split on
+// commas, or on whitespace as Jekyll does when there are none.
+func listValue(v interface{}) []string {
+	var items []string
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, stringValue(item))
+		}
+	case string:
+		if strings.Contains(v, ",") {
+			items = strings.Split(v, ",")
+		} else {
+			items = strings.Fields(v)
+		}
+	default:
+		items = []string{stringValue(v)}
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func boolValue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(strings.TrimSpace(v))
+		return b
+	default:
+		return false
+	}
+}
+
+func timeValue(v interface{}) (time.Time, error) {
+	switch v := v.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v, nil
+	}
+
+	// Strings, and TOML local dates and times which print in RFC 3339 form
+	s := strings.TrimSpace(stringValue(v))
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range frontMatterDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/commonmark"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/strikethrough"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/table"
+)
+
+// htmlConverter produces the Markdown dialect RenderArticleMarkdown reads,
+// including GFM tables and strikethrough.
+var htmlConverter = converter.NewConverter(
+	converter.WithPlugins(
+		base.NewBasePlugin(),
+		commonmark.NewCommonmarkPlugin(),
+		strikethrough.NewStrikethroughPlugin(),
+		table.NewTablePlugin(),
+	),
+)
+
+// HTMLToMarkdown converts an HTML fragment to Markdown. Relative links are
+// resolved against baseURL when it is set.
+func HTMLToMarkdown(html, baseURL string) (string, error) {
+	var opts []converter.ConvertOptionFunc
+	if baseURL != "" {
+		opts = append(opts, converter.WithDomain(baseURL))
+	}
+	markdown, err := htmlConverter.ConvertString(html, opts...)
+	return strings.TrimSpace(markdown), err
+}
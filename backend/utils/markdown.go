@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM, // Tables, strikethrough, autolinks and task lists
+		extension.Footnote,
+	),
+	goldmark.WithParserOptions(
+		parser.WithAutoHeadingID(),
+	),
+	goldmark.WithRendererOptions(
+		// Raw HTML is passed through here and cleaned by the sanitizer below
+		html.WithUnsafe(),
+	),
+)
+
+var (
+	articlePolicy = newArticlePolicy()
+	commentPolicy = newCommentPolicy()
+)
+
+func newArticlePolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	// Code fences: <code class="language-go"> for client-side highlighting
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+
+	// Heading anchors and footnote references
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\w:-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6", "li", "sup")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^footnote(s|-ref|-backref)?$`)).OnElements("a", "div", "sup")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|endnotes|backlink)$`)).OnElements("a", "div")
+
+	// Task list checkboxes
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	// Table cell alignment
+	p.AllowAttrs("style").Matching(regexp.MustCompile(`^text-align:(left|right|center)$`)).OnElements("th", "td")
+
+	p.RequireNoFollowOnLinks(false)
+	return p
+}
+
+func newCommentPolicy() *bluemonday.Policy {
+	// Text formatting and links only: comments must not embed images or tables
+	p := bluemonday.NewPolicy()
+	p.AllowStandardURLs()
+	p.AllowAttrs("href").OnElements("a")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.AllowElements("p", "br", "strong", "em", "del", "code", "pre", "blockquote", "ul", "ol", "li")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}
+
+func renderMarkdown(source string, policy *bluemonday.Policy) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
+
+// RenderArticleMarkdown converts article Markdown to sanitized HTML.
+func RenderArticleMarkdown(source string) (string, error) {
+	return renderMarkdown(source, articlePolicy)
+}
+
+// RenderCommentMarkdown converts comment Markdown to sanitized HTML using a
+// stricter policy than articles.
+func RenderCommentMarkdown(source string) (string, error) {
+	return renderMarkdown(source, commentPolicy)
+}
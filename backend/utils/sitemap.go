@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// SitemapURL is one location in a sitemap or sitemap index.
+type SitemapURL struct {
+	Loc     string
+	LastMod time.Time // Omitted when zero
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name       `xml:"urlset"`
+	NS      string         `xml:"xmlns,attr"`
+	URLs    []sitemapEntry `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	NS       string         `xml:"xmlns,attr"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+func sitemapEntries(urls []SitemapURL) []sitemapEntry {
+	entries := make([]sitemapEntry, 0, len(urls))
+	for _, u := range urls {
+		entry := sitemapEntry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			entry.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// SitemapURLSet encodes a sitemap listing urls.
+func SitemapURLSet(urls []SitemapURL) ([]byte, error) {
+	return marshalXML(sitemapURLSet{NS: sitemapNS, URLs: sitemapEntries(urls)})
+}
+
+// SitemapIndex encodes a sitemap index pointing to the child sitemaps.
+func SitemapIndex(sitemaps []SitemapURL) ([]byte, error) {
+	return marshalXML(sitemapIndex{NS: sitemapNS, Sitemaps: sitemapEntries(sitemaps)})
+}
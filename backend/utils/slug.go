@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify lowercases s and joins its letters and digits with hyphens.
+// Non-Latin letters are kept so CJK names still produce a readable slug.
+func Slugify(s string) string {
+	var sb strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingHyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			pendingHyphen = false
+			sb.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+	return sb.String()
+}
+
+// NumericSlug reports whether slug consists of digits only. Routes read such
+// a parameter as an ID, so these slugs could never be looked up.
+func NumericSlug(slug string) bool {
+	if slug == "" {
+		return false
+	}
+	for _, r := range slug {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,18 @@
+import api from './api/axios';
+
+// Reports every page view to the backend's cookieless analytics. Only the
+// first page of a visit sends document.referrer: later navigations happen
+// inside the app and would repeat it.
+export function trackPageViews(router) {
+    let referrer = document.referrer;
+
+    router.afterEach((to) => {
+        const body = JSON.stringify({ path: to.fullPath, referrer });
+        referrer = '';
+
+        const url = `${api.defaults.baseURL}/analytics/collect`;
+        if (!navigator.sendBeacon || !navigator.sendBeacon(url, body)) {
+            api.post('/analytics/collect', body, { headers: { 'Content-Type': 'text/plain' } }).catch(() => {});
+        }
+    });
+}
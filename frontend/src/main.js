@@ -4,6 +4,7 @@ import router from './router'
 import i18n from './i18n'
 import './style.css'
 import App from './App.vue'
+import { trackPageViews } from './analytics'
 
 const app = createApp(App)
 const pinia = createPinia()
@@ -32,5 +33,6 @@ app.directive('lazy', {
 
 app.use(pinia)
 app.use(router)
+trackPageViews(router)
 app.use(i18n)
 app.mount('#app')